@@ -0,0 +1,85 @@
+package couchdb
+
+import "context"
+
+// Active task types
+const (
+	TaskIndexer            = "indexer"
+	TaskReplication        = "replication"
+	TaskDatabaseCompaction = "database_compaction"
+	TaskViewCompaction     = "view_compaction"
+	TaskSearchIndexer      = "search_indexer"
+)
+
+// ActiveTask describes a running background task. Fields beyond the common
+// ones are only set for the task types noted.
+type ActiveTask struct {
+	Type      string `json:"type"`
+	Node      string `json:"node,omitempty"`
+	PID       string `json:"pid"`
+	StartedOn int64  `json:"started_on"` // Unix seconds
+	UpdatedOn int64  `json:"updated_on"` // Unix seconds
+
+	// Indexer and compaction tasks
+	Database       string `json:"database,omitempty"`
+	DesignDocument string `json:"design_document,omitempty"` // indexer, view_compaction
+	Progress       int    `json:"progress,omitempty"`        // percent
+	ChangesDone    int64  `json:"changes_done,omitempty"`
+	TotalChanges   int64  `json:"total_changes,omitempty"`
+	Phase          string `json:"phase,omitempty"` // compaction phase
+	Retry          bool   `json:"retry,omitempty"` // database_compaction
+
+	// Replication tasks
+	ReplicationID         string   `json:"replication_id,omitempty"`
+	DocID                 string   `json:"doc_id,omitempty"`
+	Source                string   `json:"source,omitempty"`
+	Target                string   `json:"target,omitempty"`
+	User                  string   `json:"user,omitempty"`
+	Continuous            bool     `json:"continuous,omitempty"`
+	DocsRead              int64    `json:"docs_read,omitempty"`
+	DocsWritten           int64    `json:"docs_written,omitempty"`
+	DocWriteFailures      int64    `json:"doc_write_failures,omitempty"`
+	MissingRevisionsFound int64    `json:"missing_revisions_found,omitempty"`
+	RevisionsChecked      int64    `json:"revisions_checked,omitempty"`
+	ChangesPending        int64    `json:"changes_pending,omitempty"`
+	SourceSeq             Sequence `json:"source_seq,omitempty"`
+	CheckpointedSourceSeq Sequence `json:"checkpointed_source_seq,omitempty"`
+	CheckpointInterval    int      `json:"checkpoint_interval,omitempty"` // milliseconds
+}
+
+// ActiveTasks returns the tasks running on the server, such as indexing,
+// compaction and replication
+func (c *Client) ActiveTasks(ctx context.Context) ([]ActiveTask, error) {
+	var tasks []ActiveTask
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&tasks).
+		Get("/_active_tasks")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return tasks, nil
+}
+
+// ActiveTasksOfType returns the running tasks of one type, e.g. TaskIndexer
+func (c *Client) ActiveTasksOfType(ctx context.Context, taskType string) ([]ActiveTask, error) {
+	tasks, err := c.ActiveTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []ActiveTask
+	for _, task := range tasks {
+		if task.Type == taskType {
+			matched = append(matched, task)
+		}
+	}
+
+	return matched, nil
+}
@@ -0,0 +1,49 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// IsAdminParty reports whether the server grants admin rights to anonymous
+// requests, which is the case for a fresh instance with no admins configured.
+// The check is made without the client's credentials.
+func (c *Client) IsAdminParty(ctx context.Context) (bool, error) {
+	info, err := c.Session(context.WithValue(ctx, anonymousKey{}, true))
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range info.UserCtx.Roles {
+		if role == "_admin" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// anonymousKey marks a context whose requests are sent without credentials
+type anonymousKey struct{}
+
+// stripCredentials removes the basic auth and session cookie from requests
+// made with an anonymous context. It runs as part of the pre-request hook,
+// as resty adds the client's basic auth after all other hooks.
+func stripCredentials(req *http.Request) {
+	if anonymous, _ := req.Context().Value(anonymousKey{}).(bool); anonymous {
+		req.Header.Del("Authorization")
+		req.Header.Del("Cookie")
+	}
+}
+
+// CreateAdmin creates a server admin on the local node. CouchDB hashes the
+// password on write. In a cluster the admin must be created on every node.
+func (c *Client) CreateAdmin(ctx context.Context, user, pass string) error {
+	if user == "" || pass == "" {
+		return fmt.Errorf("admin user and password are required")
+	}
+
+	_, err := c.SetConfigKey(ctx, LocalNode, "admins", user, pass)
+	return err
+}
@@ -0,0 +1,99 @@
+package couchdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAdminParty(t *testing.T) {
+	adminParty := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/_session", r.URL.Path)
+
+		if r.Method == "POST" {
+			http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "token"})
+			_, _ = w.Write([]byte(`{"ok":true,"name":"admin","roles":["_admin"]}`))
+			return
+		}
+
+		// The check is anonymous but still passes through the middleware
+		assert.Empty(t, r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("Cookie"))
+		assert.NotEmpty(t, r.Header.Get(requestIDHeader))
+
+		roles := `[]`
+		if adminParty {
+			roles = `["_admin"]`
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"userCtx":{"name":null,"roles":` + roles + `}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, &ClientOptions{Username: "admin", Password: "secret"})
+	ctx := context.Background()
+
+	ok, err := client.IsAdminParty(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	adminParty = false
+	_, err = client.Login(ctx, "admin", "secret")
+	require.NoError(t, err)
+
+	ok, err = client.IsAdminParty(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestIsAdminParty_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"service_unavailable","reason":"starting"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, nil).IsAdminParty(context.Background())
+	var couchErr *Error
+	require.ErrorAs(t, err, &couchErr)
+	assert.Equal(t, http.StatusServiceUnavailable, couchErr.StatusCode)
+}
+
+func TestCreateAdmin(t *testing.T) {
+	var path, body, auth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`""`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	// On an admin party the first admin is created anonymously
+	require.NoError(t, NewClient(server.URL, nil).CreateAdmin(ctx, "alice", "s3cret"))
+	assert.Equal(t, "/_node/_local/_config/admins/alice", path)
+	assert.Equal(t, `"s3cret"`, body)
+	assert.Empty(t, auth)
+
+	// Once locked down, further admins need an admin's credentials
+	require.NoError(t, NewClient(server.URL, &ClientOptions{Username: "alice", Password: "s3cret"}).CreateAdmin(ctx, "bob", "pw"))
+	assert.Equal(t, "/_node/_local/_config/admins/bob", path)
+	assert.NotEmpty(t, auth)
+
+	assert.Error(t, NewClient(server.URL, nil).CreateAdmin(ctx, "alice", ""))
+}
@@ -0,0 +1,186 @@
+package couchdb
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// ClientAPI is the set of Client methods, so code using a client can be
+// tested against a mock such as mocks.Client. Methods returning databases
+// return the concrete *Database; depend on DatabaseAPI where a database
+// needs to be substituted.
+type ClientAPI interface {
+	ActiveTasks(ctx context.Context) ([]ActiveTask, error)
+	ActiveTasksOfType(ctx context.Context, taskType string) ([]ActiveTask, error)
+	AllDbs(ctx context.Context) ([]string, error)
+	CancelReplication(ctx context.Context, id string) error
+	ConfigureCORS(ctx context.Context, cfg CORSConfig) error
+	CreateAdmin(ctx context.Context, user, pass string) error
+	CreateDB(ctx context.Context, name string) error
+	CreateDBWithOptions(ctx context.Context, name string, opts *DBCreateOptions) error
+	CreateReplication(ctx context.Context, spec *ReplicationSpec) (*Document, error)
+	DB(name string) *Database
+	DBsInfo(ctx context.Context, names []string) ([]DBInfoResult, error)
+	DeleteConfigKey(ctx context.Context, node, section, key string) (string, error)
+	DeleteDB(ctx context.Context, name string) error
+	EnsureDB(ctx context.Context, name string, opts *DBCreateOptions) (bool, error)
+	GetConfig(ctx context.Context, node string) (map[string]map[string]string, error)
+	GetConfigKey(ctx context.Context, node, section, key string) (string, error)
+	GetConfigSection(ctx context.Context, node, section string) (map[string]string, error)
+	GetReplication(ctx context.Context, id string) (*ReplicationSpec, error)
+	GetSchedulerDoc(ctx context.Context, replicatorDB, docID string) (*SchedulerDoc, error)
+	GetSmooshChannel(ctx context.Context, node, channel string) (*SmooshChannel, error)
+	GetSmooshConfig(ctx context.Context, node string) (*SmooshConfig, error)
+	Info(ctx context.Context) (*ServerInfo, error)
+	IsAdminParty(ctx context.Context) (bool, error)
+	ListReplications(ctx context.Context) ([]*ReplicationSpec, error)
+	Login(ctx context.Context, username, password string) (*UserContext, error)
+	Logout(ctx context.Context) error
+	Membership(ctx context.Context) (*Membership, error)
+	NewTenancy(opts *TenancyOptions) *Tenancy
+	NewUUIDSource(opts *UUIDSourceOptions) *UUIDSource
+	NodeInfo(ctx context.Context, node string) (*NodeInfo, error)
+	NodePrometheus(ctx context.Context, node string) ([]byte, error)
+	NodeStats(ctx context.Context, node string) (map[string]Stat, error)
+	NodeStatsPath(ctx context.Context, node string, path ...string) (map[string]Stat, error)
+	NodeSystem(ctx context.Context, node string) (*SystemStats, error)
+	Ping(ctx context.Context) error
+	Replicate(ctx context.Context, source, target string, opts *ReplicateOptions) (*ReplicationResult, error)
+	SchedulerDocs(ctx context.Context, replicatorDB string) ([]SchedulerDoc, error)
+	SchedulerJobs(ctx context.Context) ([]SchedulerJob, error)
+	ServerVersion(ctx context.Context) (string, error)
+	Session(ctx context.Context) (*SessionInfo, error)
+	SetConfigKey(ctx context.Context, node, section, key, value string) (string, error)
+	SetSmooshChannel(ctx context.Context, node, channel string, ch *SmooshChannel) error
+	SetSmooshConfig(ctx context.Context, node string, cfg *SmooshConfig) error
+	UUID(ctx context.Context) (string, error)
+	UUIDs(ctx context.Context, count int) ([]string, error)
+	Up(ctx context.Context) (*UpStatus, error)
+	Users() *Users
+	WaitForReplication(ctx context.Context, replicationID string, opts *WaitOptions) (*SchedulerDoc, error)
+	WaitUntilReady(ctx context.Context, interval time.Duration) error
+}
+
+// DatabaseAPI is the set of Database methods, see ClientAPI
+type DatabaseAPI interface {
+	AddAdmin(ctx context.Context, name string) error
+	AddAdminRole(ctx context.Context, role string) error
+	AddMember(ctx context.Context, name string) error
+	AddMemberRole(ctx context.Context, role string) error
+	AllDocs(ctx context.Context, opts *ViewOptions) (*ViewResult, error)
+	AllDocsByKeys(ctx context.Context, keys []string, opts *ViewOptions) (*ViewResult, error)
+	AllDocsStream(ctx context.Context, opts *ViewOptions) (*ViewRows, error)
+	AttachmentInfo(ctx context.Context, docID, name string, rev ...string) (*AttachmentMeta, error)
+	Bulk(ctx context.Context, docs []interface{}) (BulkResponse, error)
+	BulkGet(ctx context.Context, requests []BulkGetRequest, opts *BulkGetOptions) (*BulkGetResult, error)
+	BulkWithOptions(ctx context.Context, docs []interface{}, opts *BulkOptions) (BulkResponse, error)
+	Changes(ctx context.Context, opts map[string]interface{}) (map[string]interface{}, error)
+	ChangesFeed(ctx context.Context, opts *ChangesFeedOptions) *ChangesFeed
+	Compact(ctx context.Context) error
+	CompactAll(ctx context.Context, opts *CompactAllOptions) error
+	CompactDesignDoc(ctx context.Context, designDoc string) error
+	Copy(ctx context.Context, sourceID, targetID string, targetRev ...string) (*Document, error)
+	Counter(name, clientID string) *Counter
+	CreateIndex(ctx context.Context, index *MangoIndex) (*CreateIndexResult, error)
+	Delete(ctx context.Context, id, rev string) error
+	DeleteAttachment(ctx context.Context, docID, rev, name string) (*Document, error)
+	DeleteDesignDoc(ctx context.Context, name, rev string) error
+	DeleteDoc(ctx context.Context, doc interface{}) error
+	DeleteLocal(ctx context.Context, id, rev string) error
+	DeleteWithOptions(ctx context.Context, id, rev string, opts *WriteOptions) error
+	DeployDesignDocSafely(ctx context.Context, name string, ddoc *DesignDocument, opts *DeployOptions) (bool, error)
+	DiffDesignDoc(ctx context.Context, name string, desired *DesignDocument) (*DesignDocDiff, error)
+	EnsureCRDTViews(ctx context.Context) error
+	EnsureFieldView(ctx context.Context, fields ...string) (string, error)
+	EnsureIdempotencyIndex(ctx context.Context, opts *IdempotencyOptions) error
+	Explain(ctx context.Context, query *FindQuery) (*ExplainResult, error)
+	Export(ctx context.Context, w io.Writer, opts *ExportOptions) (int, error)
+	Find(ctx context.Context, query *FindQuery) (*FindResult, error)
+	FindByIdempotencyKey(ctx context.Context, key string, opts *IdempotencyOptions) (*Document, error)
+	Get(ctx context.Context, id string, rev ...string) (*Document, error)
+	GetAttachment(ctx context.Context, docID, name string, rev ...string) (io.ReadCloser, *AttachmentMeta, error)
+	GetChanges(ctx context.Context, opts *ChangesOptions) (*ChangesResponse, error)
+	GetConflicts(ctx context.Context, id string) (*ConflictSet, error)
+	GetDesignDoc(ctx context.Context, name string) (*DesignDocument, error)
+	GetInto(ctx context.Context, id string, dest interface{}, rev ...string) error
+	GetLocal(ctx context.Context, id string) (*Document, error)
+	GetMultipart(ctx context.Context, id string, opts *GetOptions) (*MultipartDocument, error)
+	GetOpenRevs(ctx context.Context, id string, revs []string, opts *GetOptions) ([]OpenRev, error)
+	GetOpenRevsMultipart(ctx context.Context, id string, revs []string, opts *GetOptions) (*OpenRevsReader, error)
+	GetRevsLimit(ctx context.Context) (int, error)
+	GetSecurity(ctx context.Context) (*SecurityObject, error)
+	GetWithOptions(ctx context.Context, id string, opts *GetOptions) (*Document, error)
+	Import(ctx context.Context, r io.Reader, opts *ImportOptions) (int, error)
+	Info(ctx context.Context) (*DatabaseInfo, error)
+	ListDesignDocs(ctx context.Context) (*ViewResult, error)
+	MissingRevs(ctx context.Context, revs map[string][]string) (map[string][]string, error)
+	Name() string
+	NewBulkLoader(ctx context.Context, opts *BulkLoaderOptions) *BulkLoader
+	NewChangesFollower(sink EventSink, store CheckpointStore, opts *FollowerOptions) *ChangesFollower
+	NewChangesProcessor(handler ChangeHandler, store CheckpointStore, opts *FollowerOptions) *ChangesFollower
+	NewExpiryReaper(opts *ExpiryReaperOptions) *ExpiryReaper
+	NewFindQuery(selectors ...Selector) *FindBuilder
+	NewOutboxDispatcher(handler OutboxHandler, store CheckpointStore, opts *FollowerOptions) *OutboxDispatcher
+	NewViewCache(opts *ViewCacheOptions) *ViewCache
+	NewViewQuery(designDoc, viewName string) *ViewBuilder
+	NewViewWarmer(opts *ViewWarmerOptions) *ViewWarmer
+	PaginateAllDocs(opts *ViewOptions, pageSize int) *Paginator
+	PaginateFind(query *FindQuery, pageSize int) *Paginator
+	PaginateView(designDoc, viewName string, opts *ViewOptions, pageSize int) *Paginator
+	PurgeSeq(ctx context.Context) (string, error)
+	PurgedSince(ctx context.Context, checkpoint string) (bool, string, error)
+	Put(ctx context.Context, doc interface{}) (*Document, error)
+	PutAttachment(ctx context.Context, docID, rev, name, contentType string, data []byte) (*Document, error)
+	PutAttachmentStream(ctx context.Context, docID, rev, name, contentType string, body io.Reader, size int64) (*Document, error)
+	PutDesignDoc(ctx context.Context, name string, designDoc *DesignDocument) (*Document, error)
+	PutIdempotent(ctx context.Context, key string, doc interface{}, opts *IdempotencyOptions) (*Document, error)
+	PutLocal(ctx context.Context, id string, doc interface{}) (*Document, error)
+	PutMultipart(ctx context.Context, doc interface{}, attachments ...*AttachmentUpload) (*Document, error)
+	PutWithOptions(ctx context.Context, doc interface{}, opts *WriteOptions) (*Document, error)
+	Q(viewName string) *ViewBuilder
+	RemoveAdmin(ctx context.Context, name string) error
+	RemoveAdminRole(ctx context.Context, role string) error
+	RemoveMember(ctx context.Context, name string) error
+	RemoveMemberRole(ctx context.Context, role string) error
+	ResolveConflict(ctx context.Context, id string, winner interface{}, losingRevs ...string) (BulkResponse, error)
+	Restore(ctx context.Context, id string) (*Document, error)
+	RevsDiff(ctx context.Context, revs map[string][]string) (map[string]RevsDiffResult, error)
+	Rewrite(ctx context.Context, designDoc, path, method string, body interface{}) (*RewriteResult, error)
+	Search(ctx context.Context, designDoc, indexName string, query *SearchQuery) (*SearchResult, error)
+	Set(name, clientID string) *Set
+	SetRevsLimit(ctx context.Context, limit int) error
+	SetSecurity(ctx context.Context, security *SecurityObject) error
+	Subscribe(ctx context.Context, opts SubscribeOptions) *Subscription
+	SyncDesignDocs(ctx context.Context, fsys fs.FS) ([]string, error)
+	Update(ctx context.Context, id string, doc interface{}) (*Document, error)
+	UpdateHandler(ctx context.Context, designDoc, handlerName, docID string, body interface{}) (*UpdateHandlerResult, error)
+	UpdateWithOptions(ctx context.Context, id string, doc interface{}, opts *WriteOptions) (*Document, error)
+	Upsert(ctx context.Context, id string, mutate UpsertFunc) (*Document, error)
+	UpsertWithOptions(ctx context.Context, id string, mutate UpsertFunc, opts *UpsertOptions) (*Document, error)
+	View(ctx context.Context, designDoc, viewName string, opts *ViewOptions) (*ViewResult, error)
+	ViewAll(ctx context.Context, designDoc, viewName string, includeDocs bool) (*ViewResult, error)
+	ViewByKey(ctx context.Context, designDoc, viewName string, key interface{}) (*ViewResult, error)
+	ViewByKeyRange(ctx context.Context, designDoc, viewName string, startKey, endKey interface{}) (*ViewResult, error)
+	ViewCleanup(ctx context.Context) error
+	ViewInfo(ctx context.Context, designDoc, viewName string) (map[string]interface{}, error)
+	ViewReduce(ctx context.Context, designDoc, viewName string, groupLevel int) (*ViewResult, error)
+	ViewStream(ctx context.Context, designDoc, viewName string, opts *ViewOptions) (*ViewRows, error)
+	ViewWithKeys(ctx context.Context, designDoc, viewName string, keys []interface{}, opts *ViewOptions) (*ViewResult, error)
+	WaitForCompaction(ctx context.Context, pollInterval time.Duration, progress func(CompactionProgress)) error
+	WaitForViewCompaction(ctx context.Context, designDoc string, pollInterval time.Duration, progress func(CompactionProgress)) error
+	WarmViews(ctx context.Context, designDoc string, opts *WarmOptions) error
+	WithDesignDoc(designDoc string) *Database
+	WithIDGenerator(gen IDGenerator) *Database
+	WithSoftDelete(opts *SoftDeleteOptions) *Database
+	WithTimestamps(opts *TimestampOptions) *Database
+	WithValidator(v DocValidator) *Database
+	WithWriteHook(hook WriteHook) *Database
+	WriteWithEvents(ctx context.Context, docs []interface{}, events ...*OutboxEvent) (BulkResponse, error)
+}
+
+var (
+	_ ClientAPI   = (*Client)(nil)
+	_ DatabaseAPI = (*Database)(nil)
+)
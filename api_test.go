@@ -0,0 +1,22 @@
+package couchdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The interfaces must keep up with the methods added to Client and Database
+func TestAPIInterfacesAreComplete(t *testing.T) {
+	for concrete, iface := range map[reflect.Type]reflect.Type{
+		reflect.TypeOf(&Client{}):   reflect.TypeOf((*ClientAPI)(nil)).Elem(),
+		reflect.TypeOf(&Database{}): reflect.TypeOf((*DatabaseAPI)(nil)).Elem(),
+	} {
+		for i := 0; i < concrete.NumMethod(); i++ {
+			name := concrete.Method(i).Name
+			_, ok := iface.MethodByName(name)
+			assert.True(t, ok, "%s.%s is missing from %s", concrete.Elem().Name(), name, iface.Name())
+		}
+	}
+}
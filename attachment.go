@@ -0,0 +1,182 @@
+package couchdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// AttachmentMeta describes an attachment as reported by response headers
+type AttachmentMeta struct {
+	ContentType   string
+	ContentLength int64
+	Digest        string // MD5 digest, e.g. "md5-..."
+	Encoding      string // Content-Encoding, if stored compressed
+}
+
+// PutAttachment uploads an attachment to a document. Use an empty rev to
+// create the document along with its first attachment.
+func (db *Database) PutAttachment(ctx context.Context, docID, rev, name, contentType string, data []byte) (*Document, error) {
+	req := db.client.resty.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", contentType).
+		SetBody(data)
+
+	return db.putAttachment(req, docID, rev, name)
+}
+
+// PutAttachmentStream uploads an attachment from a reader without buffering
+// it in memory. Pass the size when known so it is sent as Content-Length;
+// a negative size uses chunked transfer encoding.
+func (db *Database) PutAttachmentStream(ctx context.Context, docID, rev, name, contentType string, body io.Reader, size int64) (*Document, error) {
+	if size >= 0 {
+		ctx = context.WithValue(ctx, contentLengthKey{}, size)
+	}
+
+	req := db.client.stream.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", contentType).
+		SetBody(body)
+
+	return db.putAttachment(req, docID, rev, name)
+}
+
+// contentLengthKey carries the length of a streamed request body in its context
+type contentLengthKey struct{}
+
+// applyContentLength sets the length of streamed bodies, which resty would
+// otherwise send with chunked transfer encoding
+func applyContentLength(_ *resty.Client, req *http.Request) error {
+	if size, ok := req.Context().Value(contentLengthKey{}).(int64); ok && req.Body != nil {
+		req.ContentLength = size
+	}
+	return nil
+}
+
+func (db *Database) putAttachment(req *resty.Request, docID, rev, name string) (*Document, error) {
+	if rev != "" {
+		req.SetQueryParam("rev", rev)
+	}
+
+	var result struct {
+		ID  string `json:"id"`
+		Rev string `json:"rev"`
+		OK  bool   `json:"ok"`
+	}
+
+	resp, err := req.
+		SetResult(&result).
+		Put(db.attachmentPath(docID, name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &Document{ID: result.ID, Rev: result.Rev}, nil
+}
+
+// GetAttachment downloads an attachment. The content is streamed rather than
+// buffered; the caller must close the returned reader.
+func (db *Database) GetAttachment(ctx context.Context, docID, name string, rev ...string) (io.ReadCloser, *AttachmentMeta, error) {
+	req := db.client.stream.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true)
+
+	if len(rev) > 0 && rev[0] != "" {
+		req.SetQueryParam("rev", rev[0])
+	}
+
+	resp, err := req.Get(db.attachmentPath(docID, name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.IsError() {
+		defer resp.RawBody().Close()
+		return nil, nil, db.client.parseStreamError(resp)
+	}
+
+	return resp.RawBody(), attachmentMeta(resp), nil
+}
+
+// AttachmentInfo returns an attachment's metadata without downloading it
+func (db *Database) AttachmentInfo(ctx context.Context, docID, name string, rev ...string) (*AttachmentMeta, error) {
+	req := db.client.resty.R().SetContext(ctx)
+
+	if len(rev) > 0 && rev[0] != "" {
+		req.SetQueryParam("rev", rev[0])
+	}
+
+	resp, err := req.Head(db.attachmentPath(docID, name))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return attachmentMeta(resp), nil
+}
+
+// DeleteAttachment removes an attachment from a document
+func (db *Database) DeleteAttachment(ctx context.Context, docID, rev, name string) (*Document, error) {
+	var result struct {
+		ID  string `json:"id"`
+		Rev string `json:"rev"`
+		OK  bool   `json:"ok"`
+	}
+
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetQueryParam("rev", rev).
+		SetResult(&result).
+		Delete(db.attachmentPath(docID, name))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &Document{ID: result.ID, Rev: result.Rev}, nil
+}
+
+// attachmentPath builds the attachment URL path; attachment names may contain slashes
+func (db *Database) attachmentPath(docID, name string) string {
+	return db.docPath(docID, name)
+}
+
+func attachmentMeta(resp *resty.Response) *AttachmentMeta {
+	header := resp.Header()
+
+	meta := &AttachmentMeta{
+		ContentType: header.Get("Content-Type"),
+		Encoding:    header.Get("Content-Encoding"),
+	}
+
+	// Both headers carry the base64 MD5 digest of the attachment
+	digest := header.Get("Content-MD5")
+	if digest == "" {
+		digest = strings.Trim(header.Get("ETag"), `"`)
+	}
+	if digest != "" {
+		meta.Digest = "md5-" + digest
+	}
+
+	if length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		meta.ContentLength = length
+	}
+
+	return meta
+}
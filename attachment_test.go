@@ -0,0 +1,239 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachments(t *testing.T) {
+	var stored []byte
+	var storedType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/doc-1/dir%2Fnote.txt", r.URL.EscapedPath())
+
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "1-a", r.URL.Query().Get("rev"))
+			stored, _ = io.ReadAll(r.Body)
+			storedType = r.Header.Get("Content-Type")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"doc-1","rev":"2-b"}`))
+		case "GET", "HEAD":
+			w.Header().Set("Content-Type", storedType)
+			w.Header().Set("ETag", `"abc=="`)
+			w.Header().Set("Content-Length", "5")
+			if r.Method == "GET" {
+				_, _ = w.Write(stored)
+			}
+		case "DELETE":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true,"id":"doc-1","rev":"3-c"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	doc, err := db.PutAttachment(ctx, "doc-1", "1-a", "dir/note.txt", "text/plain", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", doc.Rev)
+	assert.Equal(t, "text/plain", storedType)
+
+	body, meta, err := db.GetAttachment(ctx, "doc-1", "dir/note.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, "text/plain", meta.ContentType)
+	assert.Equal(t, "md5-abc==", meta.Digest)
+
+	meta, err = db.AttachmentInfo(ctx, "doc-1", "dir/note.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), meta.ContentLength)
+
+	doc, err = db.DeleteAttachment(ctx, "doc-1", "2-b", "dir/note.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "3-c", doc.Rev)
+}
+
+func TestAttachmentInfo_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/db/doc-1/missing.txt":
+			w.WriteHeader(http.StatusNotFound)
+		case "/db/doc-1/secret.txt":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	// HEAD responses have no body, so the error is named after the status
+	for name, want := range map[string]string{
+		"missing.txt": "not_found",
+		"secret.txt":  "unauthorized",
+		"broken.txt":  "internal_server_error",
+	} {
+		_, err := db.AttachmentInfo(ctx, "doc-1", name)
+		var couchErr *Error
+		require.ErrorAs(t, err, &couchErr, name)
+		assert.Equal(t, want, couchErr.Type, name)
+	}
+
+	_, err := db.AttachmentInfo(ctx, "doc-1", "missing.txt")
+	assert.True(t, IsNotFound(err))
+}
+
+func TestPutAttachmentStream(t *testing.T) {
+	var contentLength int64
+	var transferEncoding []string
+	var received string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		transferEncoding = r.TransferEncoding
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true,"id":"doc-1","rev":"2-b"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	_, err := db.PutAttachmentStream(ctx, "doc-1", "1-a", "big.bin", "application/octet-stream", io.LimitReader(zeroReader{}, 1000), 1000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), contentLength)
+	assert.Empty(t, transferEncoding)
+	assert.Len(t, received, 1000)
+
+	_, err = db.PutAttachmentStream(ctx, "doc-1", "1-a", "big.bin", "application/octet-stream", io.LimitReader(zeroReader{}, 1000), -1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), contentLength)
+	assert.Equal(t, []string{"chunked"}, transferEncoding)
+	assert.Len(t, received, 1000)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestMultipartRoundTrip(t *testing.T) {
+	var storedDoc map[string]interface{}
+	storedParts := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/db/report", r.URL.Path)
+
+		if r.Method == "PUT" {
+			mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			require.NoError(t, err)
+			assert.Equal(t, "multipart/related", mediaType)
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, r.ContentLength, int64(len(body)), "the computed length must match the body")
+
+			reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+			part, err := reader.NextPart()
+			require.NoError(t, err)
+			require.NoError(t, json.NewDecoder(part).Decode(&storedDoc))
+
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				data, _ := io.ReadAll(part)
+				storedParts[part.FileName()] = string(data)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"report","rev":"1-a"}`))
+			return
+		}
+
+		assert.Equal(t, "true", r.URL.Query().Get("attachments"))
+		assert.Equal(t, "multipart/related", r.Header.Get("Accept"))
+
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/related; boundary="+mw.Boundary())
+		part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+		_ = json.NewEncoder(part).Encode(storedDoc)
+		for _, name := range []string{"data.csv", "summary.txt"} {
+			part, _ := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":        {"text/plain"},
+				"Content-Disposition": {`attachment; filename="` + name + `"`},
+			})
+			_, _ = part.Write([]byte(storedParts[name]))
+		}
+		_ = mw.Close()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	doc, err := db.PutMultipart(ctx, map[string]interface{}{"_id": "report", "title": "Q3"},
+		&AttachmentUpload{Name: "summary.txt", ContentType: "text/plain", Body: strings.NewReader("all good"), Length: 8},
+		&AttachmentUpload{Name: "data.csv", ContentType: "text/csv", Body: strings.NewReader("a,b\n1,2\n"), Length: 8},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", doc.Rev)
+
+	stubs := storedDoc["_attachments"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"follows": true, "content_type": "text/csv", "length": float64(8)}, stubs["data.csv"])
+	assert.Equal(t, map[string]string{"data.csv": "a,b\n1,2\n", "summary.txt": "all good"}, storedParts)
+
+	md, err := db.GetMultipart(ctx, "report", nil)
+	require.NoError(t, err)
+	defer md.Close()
+	assert.Equal(t, "Q3", md.Doc.Data["title"])
+
+	var names []string
+	for {
+		att, err := md.NextAttachment()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(att.Body)
+		require.NoError(t, err)
+		assert.Equal(t, storedParts[att.Name], string(data))
+		names = append(names, att.Name)
+	}
+	assert.Equal(t, []string{"data.csv", "summary.txt"}, names)
+
+	_, err = db.PutMultipart(ctx, map[string]interface{}{"title": "no id"})
+	assert.ErrorContains(t, err, "no _id")
+}
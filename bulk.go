@@ -0,0 +1,263 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BulkOptions holds options for bulk document operations
+type BulkOptions struct {
+	// FailOnError returns a *BulkError alongside the results when any
+	// document in the batch was rejected
+	FailOnError bool
+
+	// NewEdits set to false stores the documents with the revisions they
+	// carry instead of assigning new ones, as the replicator does. Documents
+	// then need _rev and usually _revisions. CouchDB reports only failures
+	// in this mode, so results do not line up with the documents.
+	NewEdits *bool
+
+	// BatchSize splits the documents into several _bulk_docs requests of at
+	// most this many documents. Zero sends them all in one request.
+	BatchSize int
+}
+
+// BulkFailure describes a single rejected document in a bulk operation
+type BulkFailure struct {
+	Index  int    // position of the document in the request, -1 if unknown
+	ID     string // document ID
+	Error  string // CouchDB error type, e.g. "conflict" or "forbidden"
+	Reason string
+}
+
+// BulkError aggregates the per-document failures of a bulk operation
+type BulkError struct {
+	Total    int
+	Failures []BulkFailure
+}
+
+// Error implements error
+func (e *BulkError) Error() string {
+	if len(e.Failures) == 0 {
+		return "bulk operation failed"
+	}
+
+	first := e.Failures[0]
+	return fmt.Sprintf("%d of %d bulk operations failed (first: %s: %s - %s)",
+		len(e.Failures), e.Total, first.ID, first.Error, first.Reason)
+}
+
+// Conflicts returns the failures caused by revision conflicts
+func (e *BulkError) Conflicts() []BulkFailure {
+	return e.byType("conflict")
+}
+
+// Forbidden returns the failures rejected by validation functions
+func (e *BulkError) Forbidden() []BulkFailure {
+	return e.byType("forbidden")
+}
+
+func (e *BulkError) byType(errorType string) []BulkFailure {
+	var failures []BulkFailure
+	for _, f := range e.Failures {
+		if f.Error == errorType {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
+// Unwrap returns an *Error per failure, so that errors.Is and errors.As
+// match any of them as with errors.Join, e.g. errors.Is(err, ErrConflict)
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = bulkItemError(f.Error, f.Reason)
+	}
+	return errs
+}
+
+// bulkErrorStatus maps the error types of _bulk_docs results to the HTTP
+// status the same error has on a single document request
+var bulkErrorStatus = map[string]int{
+	"bad_request":  http.StatusBadRequest,
+	"unauthorized": http.StatusUnauthorized,
+	"forbidden":    http.StatusForbidden,
+	"not_found":    http.StatusNotFound,
+	"conflict":     http.StatusConflict,
+	"file_exists":  http.StatusPreconditionFailed,
+}
+
+func bulkItemError(errorType, reason string) *Error {
+	status, ok := bulkErrorStatus[errorType]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	return &Error{StatusCode: status, Type: errorType, Reason: reason}
+}
+
+// Err returns the error of a rejected document as an *Error, or nil if it
+// was written
+func (r BulkResult) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+	return bulkItemError(r.Error, r.Reason)
+}
+
+// BulkResponse holds the results of a bulk operation in document order
+type BulkResponse []BulkResult
+
+// Succeeded returns the results of the documents that were written
+func (r BulkResponse) Succeeded() []BulkResult {
+	return r.filter(func(result BulkResult) bool { return result.Error == "" })
+}
+
+// Failed returns the results of the documents that were rejected
+func (r BulkResponse) Failed() []BulkResult {
+	return r.filter(func(result BulkResult) bool { return result.Error != "" })
+}
+
+// Conflicts returns the results of the documents rejected with a revision
+// conflict
+func (r BulkResponse) Conflicts() []BulkResult {
+	return r.filter(func(result BulkResult) bool { return result.Error == "conflict" })
+}
+
+// Err returns a *BulkError for the rejected documents, or nil if all were
+// written
+func (r BulkResponse) Err() error {
+	if bulkErr := NewBulkError(r); bulkErr != nil {
+		return bulkErr
+	}
+	return nil
+}
+
+func (r BulkResponse) filter(keep func(BulkResult) bool) []BulkResult {
+	var results []BulkResult
+	for _, result := range r {
+		if keep(result) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// NewBulkError collects the failed entries of results, returning nil if all succeeded
+func NewBulkError(results []BulkResult) *BulkError {
+	var failures []BulkFailure
+	for i, r := range results {
+		if r.Error != "" {
+			failures = append(failures, BulkFailure{
+				Index:  i,
+				ID:     r.ID,
+				Error:  r.Error,
+				Reason: r.Reason,
+			})
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &BulkError{Total: len(results), Failures: failures}
+}
+
+// BulkWithOptions performs bulk operations with the given options. When
+// batching, results of all batches are concatenated in document order; if a
+// batch fails, the results of the batches before it are returned along
+// with the error.
+func (db *Database) BulkWithOptions(ctx context.Context, docs []interface{}, opts *BulkOptions) (BulkResponse, error) {
+	if opts == nil {
+		opts = &BulkOptions{}
+	}
+
+	assignID := db.assignID(ctx)
+	bodies := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		body, err := db.prepareDoc(doc, i, assignID...)
+		if err != nil {
+			return nil, err
+		}
+		bodies[i] = body
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > len(docs) {
+		batchSize = len(docs)
+	}
+
+	results := make(BulkResponse, 0, len(docs))
+	for start := 0; ; start += batchSize {
+		end := min(start+batchSize, len(docs))
+
+		batch, err := db.bulkDocs(ctx, bodies[start:end], start, opts.NewEdits)
+		if err != nil {
+			return results, err
+		}
+		for i, result := range batch {
+			if result.Error == "" && start+i < end {
+				setTaggedFields(docs[start+i], result.ID, result.Rev)
+			}
+		}
+		results = append(results, batch...)
+
+		if end >= len(docs) {
+			break
+		}
+	}
+
+	if opts.FailOnError {
+		if bulkErr := NewBulkError(results); bulkErr != nil {
+			// Only failures are reported with new_edits=false, so their
+			// positions are not those of the documents
+			if opts.NewEdits != nil && !*opts.NewEdits {
+				bulkErr.Total = len(docs)
+				for i := range bulkErr.Failures {
+					bulkErr.Failures[i].Index = -1
+				}
+			}
+			return results, bulkErr
+		}
+	}
+
+	return results, nil
+}
+
+// bulkDocs sends one _bulk_docs request. Offset is the position of the batch
+// in the caller's documents, used to report oversized documents.
+func (db *Database) bulkDocs(ctx context.Context, docs []interface{}, offset int, newEdits *bool) ([]BulkResult, error) {
+	bulkDocs := BulkDocs{
+		Docs:     docs,
+		NewEdits: newEdits,
+	}
+
+	body, err := db.client.guardBulk(bulkDocs, docs)
+	if err != nil {
+		var sizeErr *SizeLimitError
+		if errors.As(err, &sizeErr) && sizeErr.Index >= 0 {
+			sizeErr.Index += offset
+		}
+		return nil, err
+	}
+
+	var results []BulkResult
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&results).
+		Post(db.path("_bulk_docs"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return results, nil
+}
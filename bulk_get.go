@@ -0,0 +1,117 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// BulkGetRequest identifies a document to fetch with BulkGet
+type BulkGetRequest struct {
+	ID        string   `json:"id"`
+	Rev       string   `json:"rev,omitempty"`
+	AttsSince []string `json:"atts_since,omitempty"`
+}
+
+// BulkGetOptions holds options for BulkGet
+type BulkGetOptions struct {
+	Revs        bool // include the revision history of each document
+	Attachments bool // include attachment content
+	Latest      bool // return the latest leaf revision instead of the requested one
+}
+
+// BulkGetResult is the response of the _bulk_get endpoint. Results are in
+// request order.
+type BulkGetResult struct {
+	Results []BulkGetItem `json:"results"`
+}
+
+// BulkGetItem holds the revisions returned for one requested document
+type BulkGetItem struct {
+	ID   string       `json:"id"`
+	Docs []BulkGetDoc `json:"docs"`
+}
+
+// BulkGetDoc holds either a document or the error that prevented reading it
+type BulkGetDoc struct {
+	OK    *Document     `json:"ok,omitempty"`
+	Error *BulkGetError `json:"error,omitempty"`
+}
+
+// BulkGetError describes a document that could not be read
+type BulkGetError struct {
+	ID     string `json:"id"`
+	Rev    string `json:"rev"`
+	Type   string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+// Error implements error
+func (e *BulkGetError) Error() string {
+	return fmt.Sprintf("%s: %s - %s", e.ID, e.Type, e.Reason)
+}
+
+// Documents returns all documents that were read successfully
+func (r *BulkGetResult) Documents() []*Document {
+	var docs []*Document
+	for _, item := range r.Results {
+		for _, doc := range item.Docs {
+			if doc.OK != nil {
+				docs = append(docs, doc.OK)
+			}
+		}
+	}
+	return docs
+}
+
+// Errors returns the per-document errors
+func (r *BulkGetResult) Errors() []*BulkGetError {
+	var errs []*BulkGetError
+	for _, item := range r.Results {
+		for _, doc := range item.Docs {
+			if doc.Error != nil {
+				errs = append(errs, doc.Error)
+			}
+		}
+	}
+	return errs
+}
+
+// BulkGet fetches multiple documents in a single request. Documents that
+// cannot be read are reported per entry rather than failing the request.
+func (db *Database) BulkGet(ctx context.Context, requests []BulkGetRequest, opts *BulkGetOptions) (*BulkGetResult, error) {
+	req := db.client.resty.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json")
+
+	if opts != nil {
+		if opts.Revs {
+			req.SetQueryParam("revs", "true")
+		}
+		if opts.Attachments {
+			req.SetQueryParam("attachments", "true")
+		}
+		if opts.Latest {
+			req.SetQueryParam("latest", "true")
+		}
+	}
+
+	if requests == nil {
+		requests = []BulkGetRequest{}
+	}
+
+	var result BulkGetResult
+	resp, err := req.
+		SetBody(map[string]interface{}{"docs": requests}).
+		SetResult(&result).
+		Post(db.path("_bulk_get"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &result, nil
+}
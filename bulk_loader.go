@@ -0,0 +1,278 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLoaderClosed is returned by BulkLoader.Add after Close
+var ErrLoaderClosed = errors.New("bulk loader is closed")
+
+// BulkLoaderOptions configures a BulkLoader
+type BulkLoaderOptions struct {
+	BatchSize     int           // documents per _bulk_docs request, defaults to 500
+	MaxBatchBytes int64         // approximate request size limit, defaults to the client's MaxRequestSize if set
+	Workers       int           // concurrent requests, defaults to 4
+	FlushInterval time.Duration // maximum time a document waits in a partial batch, defaults to 1s
+
+	// OnResult is called once per document with its result, or with the
+	// error of the request that carried it. It is called from the worker
+	// goroutines and must be safe for concurrent use.
+	OnResult func(doc interface{}, result BulkResult, err error)
+}
+
+// BulkLoader ingests documents in concurrently flushed _bulk_docs batches.
+// Add blocks while all workers are busy and the queue is full, so a fast
+// producer is slowed down to the rate the server accepts.
+type BulkLoader struct {
+	db   *Database
+	ctx  context.Context
+	opts BulkLoaderOptions
+
+	// mu is held while a batch is handed to the workers, so batches is
+	// never closed during a send
+	mu           sync.Mutex
+	pending      []bulkItem
+	pendingBytes int64
+	closed       bool
+
+	batches chan *bulkBatch
+	workers sync.WaitGroup
+	stop    chan struct{}
+
+	// outstanding holds the batches sent but not yet written, for Flush
+	outMu       sync.Mutex
+	outstanding map[*bulkBatch]struct{}
+
+	errOnce sync.Once
+	err     error
+}
+
+type bulkItem struct {
+	doc interface{}
+	raw json.RawMessage
+}
+
+type bulkBatch struct {
+	items []bulkItem
+	done  chan struct{}
+}
+
+// NewBulkLoader starts a loader writing to db. Requests use ctx; cancelling
+// it fails the remaining batches. Call Close to flush and stop the loader.
+func (db *Database) NewBulkLoader(ctx context.Context, opts *BulkLoaderOptions) *BulkLoader {
+	l := &BulkLoader{
+		db:          db,
+		ctx:         ctx,
+		stop:        make(chan struct{}),
+		outstanding: make(map[*bulkBatch]struct{}),
+	}
+	if opts != nil {
+		l.opts = *opts
+	}
+	if l.opts.BatchSize <= 0 {
+		l.opts.BatchSize = 500
+	}
+	if l.opts.MaxBatchBytes <= 0 {
+		l.opts.MaxBatchBytes = db.client.maxRequestSize
+	}
+	if l.opts.Workers <= 0 {
+		l.opts.Workers = 4
+	}
+	if l.opts.FlushInterval <= 0 {
+		l.opts.FlushInterval = time.Second
+	}
+
+	l.batches = make(chan *bulkBatch, l.opts.Workers)
+	for i := 0; i < l.opts.Workers; i++ {
+		l.workers.Add(1)
+		go l.work()
+	}
+	go l.flushPeriodically()
+
+	return l
+}
+
+// Add queues a document for writing. Documents without an _id get one
+// from the database's IDGenerator, if it has one, see WithIDGenerator.
+func (l *BulkLoader) Add(ctx context.Context, doc interface{}) error {
+	raw, err := l.encode(ctx, doc)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrLoaderClosed
+	}
+
+	// Start a new batch rather than exceed the size limit
+	size := int64(len(raw)) + 1
+	if l.opts.MaxBatchBytes > 0 && len(l.pending) > 0 && l.pendingBytes+size > l.opts.MaxBatchBytes {
+		if err := l.sendPending(ctx); err != nil {
+			return err
+		}
+	}
+
+	l.pending = append(l.pending, bulkItem{doc: doc, raw: raw})
+	l.pendingBytes += size
+
+	if len(l.pending) >= l.opts.BatchSize {
+		return l.sendPending(ctx)
+	}
+	return nil
+}
+
+// encode marshals a document, assigning it an ID first if the database
+// has an IDGenerator
+func (l *BulkLoader) encode(ctx context.Context, doc interface{}) (json.RawMessage, error) {
+	assignID := l.db.assignID(ctx)
+	if len(assignID) == 0 {
+		return json.Marshal(doc)
+	}
+
+	body, err := documentBody(doc)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := convertDoc(body, &m); err != nil {
+		return nil, err
+	}
+	if err := assignID[0](m); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
+
+// Flush sends the pending documents and waits until every batch sent so far
+// has been written
+func (l *BulkLoader) Flush(ctx context.Context) error {
+	l.mu.Lock()
+	err := l.sendPending(ctx)
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	l.outMu.Lock()
+	waiting := make([]*bulkBatch, 0, len(l.outstanding))
+	for b := range l.outstanding {
+		waiting = append(waiting, b)
+	}
+	l.outMu.Unlock()
+
+	for _, b := range waiting {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close flushes the pending documents and stops the workers. It returns the
+// first request error, if any; per-document failures are only reported to
+// OnResult.
+func (l *BulkLoader) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.stop)
+		_ = l.sendPending(context.Background())
+		close(l.batches)
+	}
+	l.mu.Unlock()
+
+	l.workers.Wait()
+	return l.err
+}
+
+// sendPending hands the current batch to the workers; l.mu must be held
+func (l *BulkLoader) sendPending(ctx context.Context) error {
+	if len(l.pending) == 0 {
+		return nil
+	}
+
+	b := &bulkBatch{items: l.pending, done: make(chan struct{})}
+	l.setOutstanding(b, true)
+
+	select {
+	case l.batches <- b:
+		l.pending, l.pendingBytes = nil, 0
+		return nil
+	case <-ctx.Done():
+		l.setOutstanding(b, false)
+		return ctx.Err()
+	}
+}
+
+func (l *BulkLoader) setOutstanding(b *bulkBatch, outstanding bool) {
+	l.outMu.Lock()
+	defer l.outMu.Unlock()
+	if outstanding {
+		l.outstanding[b] = struct{}{}
+	} else {
+		delete(l.outstanding, b)
+	}
+}
+
+func (l *BulkLoader) flushPeriodically() {
+	ticker := time.NewTicker(l.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			if !l.closed {
+				_ = l.sendPending(l.ctx)
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+func (l *BulkLoader) work() {
+	defer l.workers.Done()
+
+	for b := range l.batches {
+		l.write(b.items)
+		l.setOutstanding(b, false)
+		close(b.done)
+	}
+}
+
+func (l *BulkLoader) write(batch []bulkItem) {
+	docs := make([]interface{}, len(batch))
+	for i, item := range batch {
+		docs[i] = item.raw
+	}
+
+	results, err := l.db.bulkDocs(l.ctx, docs, 0, nil)
+	if err == nil && len(results) != len(batch) {
+		err = errors.New("bulk loader: result count does not match the batch")
+	}
+	if err != nil {
+		l.errOnce.Do(func() { l.err = err })
+	}
+
+	if l.opts.OnResult == nil {
+		return
+	}
+	for i, item := range batch {
+		if err != nil {
+			l.opts.OnResult(item.doc, BulkResult{}, err)
+			continue
+		}
+		l.opts.OnResult(item.doc, results[i], nil)
+	}
+}
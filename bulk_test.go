@@ -0,0 +1,259 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkWithOptions_FailOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode([]BulkResult{
+			{ID: "a", Rev: "1-a"},
+			{ID: "b", Error: "conflict", Reason: "Document update conflict."},
+			{ID: "c", Error: "forbidden", Reason: "invalid"},
+		})
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	docs := []interface{}{map[string]string{"_id": "a"}, map[string]string{"_id": "b"}, map[string]string{"_id": "c"}}
+
+	results, err := db.Bulk(context.Background(), docs)
+	require.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	results, err = db.BulkWithOptions(context.Background(), docs, &BulkOptions{FailOnError: true})
+	assert.Len(t, results, 3)
+
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, 3, bulkErr.Total)
+	require.Len(t, bulkErr.Failures, 2)
+	assert.Equal(t, BulkFailure{Index: 1, ID: "b", Error: "conflict", Reason: "Document update conflict."}, bulkErr.Conflicts()[0])
+	assert.Equal(t, 2, bulkErr.Forbidden()[0].Index)
+}
+
+func TestBulkResponse(t *testing.T) {
+	results := BulkResponse{
+		{ID: "a", Rev: "1-a"},
+		{ID: "b", Error: "conflict", Reason: "Document update conflict."},
+		{ID: "c", Error: "forbidden", Reason: "invalid"},
+		{ID: "d", Rev: "2-d"},
+	}
+
+	assert.Equal(t, []BulkResult{results[0], results[3]}, results.Succeeded())
+	assert.Equal(t, []BulkResult{results[1], results[2]}, results.Failed())
+	assert.Equal(t, []BulkResult{results[1]}, results.Conflicts())
+
+	err := results.Err()
+	require.Error(t, err)
+	assert.True(t, IsConflict(err))
+	assert.True(t, IsForbidden(err))
+	assert.False(t, IsNotFound(err))
+
+	var couchErr *Error
+	require.True(t, errors.As(err, &couchErr))
+	assert.Equal(t, &Error{StatusCode: http.StatusConflict, Type: "conflict", Reason: "Document update conflict."}, couchErr)
+
+	assert.NoError(t, results[0].Err())
+	assert.True(t, IsForbidden(results[2].Err()))
+
+	assert.NoError(t, BulkResponse{{ID: "a", Rev: "1-a"}}.Err())
+	assert.Nil(t, BulkResponse{}.Conflicts())
+}
+
+func TestBulkGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/_bulk_get", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("revs"))
+
+		var body struct {
+			Docs []BulkGetRequest `json:"docs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Len(t, body.Docs, 2)
+		assert.Equal(t, "1-a", body.Docs[0].Rev)
+
+		_, _ = w.Write([]byte(`{"results":[
+			{"id":"a","docs":[{"ok":{"_id":"a","_rev":"1-a","value":1}}]},
+			{"id":"b","docs":[{"error":{"id":"b","rev":"undefined","error":"not_found","reason":"missing"}}]}
+		]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	result, err := db.BulkGet(context.Background(), []BulkGetRequest{{ID: "a", Rev: "1-a"}, {ID: "b"}}, &BulkGetOptions{Revs: true})
+	require.NoError(t, err)
+
+	docs := result.Documents()
+	require.Len(t, docs, 1)
+	assert.Equal(t, "a", docs[0].ID)
+	assert.Equal(t, float64(1), docs[0].Data["value"])
+
+	errs := result.Errors()
+	require.Len(t, errs, 1)
+	assert.Equal(t, "not_found", errs[0].Type)
+}
+
+func TestBulkWithOptions_Batching(t *testing.T) {
+	var batches []int
+	var newEdits []interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body struct {
+			Docs     []map[string]interface{} `json:"docs"`
+			NewEdits interface{}              `json:"new_edits"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		batches = append(batches, len(body.Docs))
+		newEdits = append(newEdits, body.NewEdits)
+
+		// With new_edits=false CouchDB reports only the failures
+		results := []BulkResult{}
+		for _, doc := range body.Docs {
+			if doc["_id"] == "d3" {
+				results = append(results, BulkResult{ID: "d3", Error: "forbidden", Reason: "invalid revision"})
+			} else if body.NewEdits == nil {
+				results = append(results, BulkResult{ID: doc["_id"].(string), Rev: "1-a"})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+
+	docs := make([]interface{}, 5)
+	for i := range docs {
+		docs[i] = map[string]interface{}{"_id": "d" + string(rune('0'+i))}
+	}
+
+	noNewEdits := false
+	results, err := db.BulkWithOptions(context.Background(), docs, &BulkOptions{BatchSize: 2, NewEdits: &noNewEdits, FailOnError: true})
+	assert.Equal(t, []int{2, 2, 1}, batches)
+	assert.Equal(t, []interface{}{false, false, false}, newEdits)
+	require.Len(t, results, 1)
+
+	// The failure cannot be placed, as successes are left out
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, 5, bulkErr.Total)
+	assert.Equal(t, BulkFailure{Index: -1, ID: "d3", Error: "forbidden", Reason: "invalid revision"}, bulkErr.Failures[0])
+
+	batches = nil
+	results, err = db.BulkWithOptions(context.Background(), docs, &BulkOptions{FailOnError: true})
+	assert.Equal(t, []int{5}, batches)
+	assert.Nil(t, newEdits[len(newEdits)-1], "new_edits is omitted by default")
+	require.Len(t, results, 5)
+	require.True(t, errors.As(err, &bulkErr))
+	assert.Equal(t, 3, bulkErr.Failures[0].Index)
+}
+
+func TestBulkLoader(t *testing.T) {
+	var mu sync.Mutex
+	requests, received := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_bulk_docs", r.URL.Path)
+
+		var body struct {
+			Docs []map[string]interface{} `json:"docs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.LessOrEqual(t, len(body.Docs), 10)
+
+		mu.Lock()
+		requests++
+		received += len(body.Docs)
+		mu.Unlock()
+
+		results := make([]BulkResult, len(body.Docs))
+		for i, doc := range body.Docs {
+			id := doc["_id"].(string)
+			results[i] = BulkResult{ID: id, Rev: "1-a"}
+			if id == "doc-13" {
+				results[i] = BulkResult{ID: id, Error: "conflict", Reason: "Document update conflict."}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	var resultsMu sync.Mutex
+	var ok, failed []string
+
+	db := NewClient(server.URL, nil).DB("db")
+	loader := db.NewBulkLoader(context.Background(), &BulkLoaderOptions{
+		BatchSize: 10,
+		Workers:   3,
+		OnResult: func(doc interface{}, result BulkResult, err error) {
+			require.NoError(t, err)
+			resultsMu.Lock()
+			defer resultsMu.Unlock()
+			if result.Error != "" {
+				failed = append(failed, result.ID)
+			} else {
+				ok = append(ok, result.ID)
+			}
+		},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 95; i++ {
+		require.NoError(t, loader.Add(ctx, map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i)}))
+	}
+	require.NoError(t, loader.Close())
+	assert.ErrorIs(t, loader.Add(ctx, map[string]interface{}{}), ErrLoaderClosed)
+
+	assert.Equal(t, 10, requests)
+	assert.Equal(t, 95, received)
+	assert.Len(t, ok, 94)
+	assert.Equal(t, []string{"doc-13"}, failed)
+}
+
+func TestBulkLoader_FlushInterval(t *testing.T) {
+	written := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Docs []json.RawMessage `json:"docs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		written <- len(body.Docs)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"a","rev":"1-a"},{"id":"b","rev":"1-b"}]`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	loader := db.NewBulkLoader(context.Background(), &BulkLoaderOptions{FlushInterval: 10 * time.Millisecond})
+	defer loader.Close()
+
+	ctx := context.Background()
+	require.NoError(t, loader.Add(ctx, map[string]interface{}{"_id": "a"}))
+	require.NoError(t, loader.Add(ctx, map[string]interface{}{"_id": "b"}))
+
+	select {
+	case n := <-written:
+		assert.Equal(t, 2, n)
+	case <-time.After(2 * time.Second):
+		t.Fatal("partial batch was not flushed")
+	}
+	require.NoError(t, loader.Flush(ctx))
+}
@@ -0,0 +1,148 @@
+package couchdb
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ChangeRev is a leaf revision listed in a change
+type ChangeRev struct {
+	Rev string `json:"rev"`
+}
+
+// Change represents a single row of the changes feed
+type Change struct {
+	Seq     Sequence    `json:"seq"`
+	ID      string      `json:"id"`
+	Changes []ChangeRev `json:"changes"`
+	Deleted bool        `json:"deleted,omitempty"`
+	Doc     *Document   `json:"doc,omitempty"`
+}
+
+// ChangesResponse represents a normal or longpoll changes feed response
+type ChangesResponse struct {
+	Results []Change `json:"results"`
+	LastSeq Sequence `json:"last_seq"`
+	Pending int64    `json:"pending"`
+}
+
+// ChangesOptions holds options for changes feed requests
+type ChangesOptions struct {
+	Feed        string // "normal", "longpoll", "continuous" or "eventsource"
+	Since       string
+	Limit       int
+	Descending  bool
+	IncludeDocs bool
+	Conflicts   bool
+	Style       string // "main_only" or "all_docs"
+	Filter      string
+	Timeout     int // milliseconds to wait for changes in longpoll mode
+	Heartbeat   int // milliseconds between heartbeats
+	Params      map[string]string
+
+	// Selector limits the feed to documents matching a Mango selector
+	// (filter=_selector). It takes precedence over DocIDs and Filter.
+	Selector interface{}
+	// DocIDs limits the feed to the given documents (filter=_doc_ids).
+	// It takes precedence over Filter.
+	DocIDs []string
+}
+
+// GetChanges returns a typed page of the changes feed
+func (db *Database) GetChanges(ctx context.Context, opts *ChangesOptions) (*ChangesResponse, error) {
+	return db.getChanges(ctx, db.client.resty, opts)
+}
+
+func (db *Database) getChanges(ctx context.Context, client *resty.Client, opts *ChangesOptions) (*ChangesResponse, error) {
+	var result ChangesResponse
+	req := client.R().
+		SetContext(ctx).
+		SetQueryParams(opts.queryParams()).
+		SetResult(&result)
+
+	resp, err := db.sendChanges(req, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &result, nil
+}
+
+// sendChanges requests the changes feed, posting the selector or document
+// IDs of a built-in filter in the body
+func (db *Database) sendChanges(req *resty.Request, opts *ChangesOptions) (*resty.Response, error) {
+	if body := opts.requestBody(); body != nil {
+		return req.SetBody(body).Post(db.path("_changes"))
+	}
+	return req.Get(db.path("_changes"))
+}
+
+// requestBody returns the POST body for the _selector and _doc_ids
+// filters, or nil if neither is used
+func (opts *ChangesOptions) requestBody() interface{} {
+	switch {
+	case opts == nil:
+		return nil
+	case opts.Selector != nil:
+		return map[string]interface{}{"selector": opts.Selector}
+	case opts.DocIDs != nil:
+		return map[string]interface{}{"doc_ids": opts.DocIDs}
+	}
+	return nil
+}
+
+// queryParams encodes the options as URL query parameters
+func (opts *ChangesOptions) queryParams() map[string]string {
+	params := make(map[string]string)
+	if opts == nil {
+		return params
+	}
+
+	if opts.Feed != "" {
+		params["feed"] = opts.Feed
+	}
+	if opts.Since != "" {
+		params["since"] = opts.Since
+	}
+	if opts.Limit > 0 {
+		params["limit"] = strconv.Itoa(opts.Limit)
+	}
+	if opts.Descending {
+		params["descending"] = "true"
+	}
+	if opts.IncludeDocs {
+		params["include_docs"] = "true"
+	}
+	if opts.Conflicts {
+		params["conflicts"] = "true"
+	}
+	if opts.Style != "" {
+		params["style"] = opts.Style
+	}
+	switch {
+	case opts.Selector != nil:
+		params["filter"] = "_selector"
+	case opts.DocIDs != nil:
+		params["filter"] = "_doc_ids"
+	case opts.Filter != "":
+		params["filter"] = opts.Filter
+	}
+	if opts.Timeout > 0 {
+		params["timeout"] = strconv.Itoa(opts.Timeout)
+	}
+	if opts.Heartbeat > 0 {
+		params["heartbeat"] = strconv.Itoa(opts.Heartbeat)
+	}
+	for k, v := range opts.Params {
+		params[k] = v
+	}
+
+	return params
+}
@@ -0,0 +1,375 @@
+package couchdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangesFeedOptions configures a streaming ChangesFeed
+type ChangesFeedOptions struct {
+	ChangesOptions
+
+	// ReconnectDelay is the pause before reconnecting after the feed ends or
+	// fails, defaults to one second
+	ReconnectDelay time.Duration
+	// MaxRetries limits consecutive failed connection attempts; zero retries forever
+	MaxRetries int
+	// BufferSize is the capacity of the changes channel
+	BufferSize int
+}
+
+// ChangesFeed streams typed changes over a channel. It follows the feed in
+// continuous (default), eventsource or longpoll mode and transparently
+// reconnects from the last received sequence when the connection drops or
+// times out. The eventsource mode reads the feed as Server-Sent Events, for
+// proxies that handle those better than long chunked responses; it resumes
+// with the Last-Event-ID header and honours the server's retry delay. A
+// feed started from "now" first reads the current update sequence, so that
+// a reconnect does not skip changes made while it was disconnected.
+type ChangesFeed struct {
+	db      *Database
+	opts    ChangesFeedOptions
+	changes chan Change
+	cancel  context.CancelFunc
+
+	mu      sync.Mutex
+	lastSeq string
+	err     error
+	retry   time.Duration // reconnect delay requested by an eventsource feed
+}
+
+// ChangesFeed starts streaming the database's changes. Read from Changes()
+// until it is closed, then check Err(). Cancel ctx or call Close to stop.
+func (db *Database) ChangesFeed(ctx context.Context, opts *ChangesFeedOptions) *ChangesFeed {
+	f := &ChangesFeed{db: db}
+	if opts != nil {
+		f.opts = *opts
+	}
+	if f.opts.Feed == "" {
+		f.opts.Feed = "continuous"
+	}
+	if f.opts.Heartbeat <= 0 {
+		f.opts.Heartbeat = 10000
+	}
+	if f.opts.ReconnectDelay <= 0 {
+		f.opts.ReconnectDelay = time.Second
+	}
+	f.lastSeq = f.opts.Since
+	f.changes = make(chan Change, f.opts.BufferSize)
+
+	ctx, f.cancel = context.WithCancel(ctx)
+	go f.run(ctx)
+
+	return f
+}
+
+// Changes returns the channel on which changes are delivered
+func (f *ChangesFeed) Changes() <-chan Change {
+	return f.changes
+}
+
+// Err returns the error that stopped the feed, or nil if it was cancelled
+func (f *ChangesFeed) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// LastSeq returns the sequence of the last delivered change
+func (f *ChangesFeed) LastSeq() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lastSeq
+}
+
+// Close stops the feed
+func (f *ChangesFeed) Close() {
+	f.cancel()
+}
+
+func (f *ChangesFeed) run(ctx context.Context) {
+	defer close(f.changes)
+	defer f.cancel()
+
+	failures := 0
+	for {
+		var err error
+		if f.LastSeq() == "now" {
+			err = f.resolveNow(ctx)
+		}
+		if err == nil {
+			switch f.opts.Feed {
+			case "longpoll":
+				err = f.longpoll(ctx)
+			case "eventsource":
+				err = f.eventsource(ctx)
+			default:
+				err = f.stream(ctx)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			// Client errors such as a missing database or bad credentials will not heal
+			var couchErr *Error
+			if errors.As(err, &couchErr) && couchErr.StatusCode < 500 {
+				f.setErr(err)
+				return
+			}
+
+			failures++
+			if f.opts.MaxRetries > 0 && failures > f.opts.MaxRetries {
+				f.setErr(fmt.Errorf("changes feed: giving up after %d attempts: %w", failures, err))
+				return
+			}
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(f.reconnectDelay()):
+		}
+	}
+}
+
+// resolveNow replaces the "now" start sequence with the current update
+// sequence. Reconnecting with "now" after a drop before the first change
+// would skip the changes made while the feed was disconnected.
+func (f *ChangesFeed) resolveNow(ctx context.Context) error {
+	info, err := f.db.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	f.setLastSeq(info.UpdateSeq.String())
+	return nil
+}
+
+// stream reads a continuous feed until the server closes it
+func (f *ChangesFeed) stream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := f.opts.ChangesOptions
+	opts.Since = f.LastSeq()
+
+	req := f.db.client.stream.R().
+		SetContext(ctx).
+		SetQueryParams(opts.queryParams()).
+		SetDoNotParseResponse(true)
+
+	resp, err := f.db.sendChanges(req, &opts)
+
+	if err != nil {
+		return err
+	}
+
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.IsError() {
+		return f.db.client.parseStreamError(resp)
+	}
+
+	// Treat a connection that misses several heartbeats as dead
+	silence := 3 * time.Duration(opts.Heartbeat) * time.Millisecond
+	watchdog := time.AfterFunc(silence, cancel)
+	defer watchdog.Stop()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		watchdog.Reset(silence)
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue // heartbeat
+		}
+
+		var row struct {
+			Change
+			LastSeq *Sequence `json:"last_seq"`
+		}
+		if err := json.Unmarshal(line, &row); err != nil {
+			return fmt.Errorf("changes feed: decode line: %w", err)
+		}
+
+		if row.LastSeq != nil {
+			f.setLastSeq(row.LastSeq.String())
+			return nil
+		}
+
+		if !f.deliver(ctx, row.Change) {
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
+
+// longpoll requests one batch of changes
+func (f *ChangesFeed) longpoll(ctx context.Context) error {
+	opts := f.opts.ChangesOptions
+	opts.Since = f.LastSeq()
+	opts.Heartbeat = 0
+
+	result, err := f.db.getChanges(ctx, f.db.client.stream, &opts)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range result.Results {
+		if !f.deliver(ctx, change) {
+			return ctx.Err()
+		}
+	}
+
+	if result.LastSeq != "" {
+		f.setLastSeq(result.LastSeq.String())
+	}
+
+	return nil
+}
+
+// eventsource reads a feed=eventsource response until the server closes it.
+// Every event carries a change as data and its sequence as id; heartbeats
+// are events of type heartbeat.
+func (f *ChangesFeed) eventsource(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := f.opts.ChangesOptions
+	opts.Since = f.LastSeq()
+
+	req := f.db.client.stream.R().
+		SetContext(ctx).
+		SetQueryParams(opts.queryParams()).
+		SetHeader("Accept", "text/event-stream").
+		SetDoNotParseResponse(true)
+	if opts.Since != "" {
+		req.SetHeader("Last-Event-ID", opts.Since)
+	}
+
+	resp, err := f.db.sendChanges(req, &opts)
+
+	if err != nil {
+		return err
+	}
+
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.IsError() {
+		return f.db.client.parseStreamError(resp)
+	}
+
+	silence := 3 * time.Duration(opts.Heartbeat) * time.Millisecond
+	watchdog := time.AfterFunc(silence, cancel)
+	defer watchdog.Stop()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var event, id string
+	var data []byte
+	for scanner.Scan() {
+		watchdog.Reset(silence)
+
+		line := scanner.Text()
+		if line != "" {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				if len(data) > 0 {
+					data = append(data, '\n')
+				}
+				data = append(data, value...)
+			case "id":
+				id = value
+			case "retry":
+				if ms, err := strconv.Atoi(value); err == nil {
+					f.setRetry(time.Duration(ms) * time.Millisecond)
+				}
+			}
+			continue
+		}
+
+		// A blank line ends the event
+		if len(data) > 0 && (event == "" || event == "message") {
+			var row struct {
+				Change
+				LastSeq *Sequence `json:"last_seq"`
+			}
+			if err := json.Unmarshal(data, &row); err != nil {
+				return fmt.Errorf("changes feed: decode event: %w", err)
+			}
+
+			if row.LastSeq != nil {
+				f.setLastSeq(row.LastSeq.String())
+				return nil
+			}
+			if !f.deliver(ctx, row.Change) {
+				return ctx.Err()
+			}
+			if id != "" {
+				f.setLastSeq(id)
+			}
+		}
+		event, id, data = "", "", data[:0]
+	}
+
+	return scanner.Err()
+}
+
+func (f *ChangesFeed) deliver(ctx context.Context, change Change) bool {
+	select {
+	case f.changes <- change:
+		f.setLastSeq(change.Seq.String())
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (f *ChangesFeed) setLastSeq(seq string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastSeq = seq
+}
+
+func (f *ChangesFeed) setRetry(retry time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retry = retry
+}
+
+func (f *ChangesFeed) reconnectDelay() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.retry > 0 {
+		return f.retry
+	}
+	return f.opts.ReconnectDelay
+}
+
+func (f *ChangesFeed) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
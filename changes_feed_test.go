@@ -0,0 +1,164 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesFeed_ContinuousReconnect(t *testing.T) {
+	var mu sync.Mutex
+	var sinces []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "continuous", r.URL.Query().Get("feed"))
+
+		mu.Lock()
+		sinces = append(sinces, r.URL.Query().Get("since"))
+		first := len(sinces) == 1
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if first {
+			fmt.Fprintln(w, `{"seq":"1-a","id":"doc-1","changes":[{"rev":"1-x"}]}`)
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, `{"seq":"2-b","id":"doc-2","changes":[{"rev":"1-y"}],"deleted":true}`)
+			fmt.Fprintln(w, `{"last_seq":"2-b","pending":0}`)
+			return
+		}
+		fmt.Fprintln(w, `{"seq":"3-c","id":"doc-3","changes":[{"rev":"1-z"}]}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	feed := db.ChangesFeed(context.Background(), &ChangesFeedOptions{ReconnectDelay: 10 * time.Millisecond})
+
+	var ids []string
+	for change := range feed.Changes() {
+		ids = append(ids, change.ID)
+		if change.ID == "doc-2" {
+			assert.True(t, change.Deleted)
+		}
+		if len(ids) == 3 {
+			feed.Close()
+		}
+	}
+
+	require.NoError(t, feed.Err())
+	assert.Equal(t, []string{"doc-1", "doc-2", "doc-3"}, ids)
+	assert.Equal(t, "3-c", feed.LastSeq())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "2-b"}, sinces[:2])
+}
+
+func TestChangesFeed_EventSource(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "eventsource", r.URL.Query().Get("feed"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		first := len(lastEventIDs) == 1
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if first {
+			fmt.Fprint(w, "retry: 5\n\n")
+			fmt.Fprint(w, "data: {\"seq\":\"1-a\",\"id\":\"doc-1\",\"changes\":[{\"rev\":\"1-x\"}]}\nid: 1-a\n\n")
+			fmt.Fprint(w, "event: heartbeat\ndata:\n\n")
+			fmt.Fprint(w, ": comment\ndata: {\"seq\":\"2-b\",\"id\":\"doc-2\",\n")
+			fmt.Fprint(w, "data: \"changes\":[{\"rev\":\"1-y\"}]}\nid: 2-b\n\n")
+			return
+		}
+		fmt.Fprint(w, "data: {\"seq\":\"3-c\",\"id\":\"doc-3\",\"changes\":[{\"rev\":\"1-z\"}]}\nid: 3-c\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	feed := db.ChangesFeed(context.Background(), &ChangesFeedOptions{
+		ChangesOptions: ChangesOptions{Feed: "eventsource"},
+		ReconnectDelay: time.Hour, // the server's retry must win
+	})
+
+	var ids []string
+	for change := range feed.Changes() {
+		ids = append(ids, change.ID)
+		if len(ids) == 3 {
+			feed.Close()
+		}
+	}
+
+	require.NoError(t, feed.Err())
+	assert.Equal(t, []string{"doc-1", "doc-2", "doc-3"}, ids)
+	assert.Equal(t, "3-c", feed.LastSeq())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "2-b"}, lastEventIDs[:2])
+}
+
+func TestChangesFeed_StopsOnClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error":"not_found","reason":"Database does not exist."}`)
+	}))
+	defer server.Close()
+
+	feed := NewClient(server.URL, nil).DB("missing").ChangesFeed(context.Background(), nil)
+	for range feed.Changes() {
+	}
+
+	var couchErr *Error
+	require.ErrorAs(t, feed.Err(), &couchErr)
+	assert.Equal(t, 404, couchErr.StatusCode)
+}
+
+func TestGetChanges_BuiltinFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch r.URL.Query().Get("filter") {
+		case "_selector":
+			assert.Equal(t, map[string]interface{}{"type": "order"}, body["selector"])
+		case "_doc_ids":
+			assert.Equal(t, []interface{}{"a", "b"}, body["doc_ids"])
+		default:
+			t.Errorf("unexpected filter %q", r.URL.Query().Get("filter"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"seq":"1-a","id":"a","changes":[{"rev":"1-x"}]}],"last_seq":"1-a"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	changes, err := db.GetChanges(ctx, &ChangesOptions{Selector: map[string]interface{}{"type": "order"}})
+	require.NoError(t, err)
+	assert.Len(t, changes.Results, 1)
+
+	_, err = db.GetChanges(ctx, &ChangesOptions{DocIDs: []string{"a", "b"}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,120 @@
+// Package changesbridge forwards a CouchDB changes feed to a message
+// broker or any other Sink, in batches and in feed order:
+//
+//	sink := changesbridge.NewKafkaSink(writer, nil)
+//	bridge := changesbridge.New(db, sink, &changesbridge.Options{
+//		ChangesOptions: couchdb.ChangesOptions{
+//			IncludeDocs: true,
+//			Selector:    couchdb.Eq("type", "order"),
+//		},
+//	})
+//	err := bridge.Run(ctx)
+//
+// Delivery is at-least-once. The feed position is checkpointed after each
+// batch the sink accepted, so after a failure or restart the bridge resumes
+// with the first batch that was not confirmed and a sink may see changes
+// again, but never misses one.
+package changesbridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+// Sink receives batches of changes. Send must return only once the whole
+// batch is stored; an error makes the bridge stop without checkpointing
+// the batch.
+type Sink interface {
+	Send(ctx context.Context, changes []couchdb.Change) error
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(ctx context.Context, changes []couchdb.Change) error
+
+// Send implements Sink
+func (f SinkFunc) Send(ctx context.Context, changes []couchdb.Change) error {
+	return f(ctx, changes)
+}
+
+// Options configures a Bridge
+type Options struct {
+	// ChangesOptions selects the changes to forward, e.g. with a Selector or
+	// Filter. Feed, Since, Limit and Timeout are managed by the bridge.
+	couchdb.ChangesOptions
+
+	CheckpointKey string                  // defaults to "changesbridge-<db>"
+	Store         couchdb.CheckpointStore // defaults to a LocalCheckpointStore in the database
+	BatchSize     int                     // maximum changes per Send, defaults to 100
+	PollTimeout   time.Duration           // longpoll wait, defaults to 20 seconds; keep below the client timeout
+
+	// OnBatch is called after each batch is sent and checkpointed
+	OnBatch func(changes int, seq string)
+}
+
+// Bridge forwards the changes of a database to a Sink. It is a
+// couchdb.ChangesFollower whose sink collects the changes of each checkpoint
+// into one batch.
+type Bridge struct {
+	follower *couchdb.ChangesFollower
+}
+
+// New creates a bridge forwarding db's changes to sink
+func New(db *couchdb.Database, sink Sink, opts *Options) *Bridge {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.CheckpointKey == "" {
+		o.CheckpointKey = "changesbridge-" + db.Name()
+	}
+
+	followerOpts := &couchdb.FollowerOptions{
+		ChangesOptions: o.ChangesOptions,
+		CheckpointKey:  o.CheckpointKey,
+		BatchSize:      o.BatchSize,
+		PollTimeout:    o.PollTimeout,
+	}
+	if o.OnBatch != nil {
+		followerOpts.OnCheckpoint = func(changes int, seq string) {
+			if changes > 0 {
+				o.OnBatch(changes, seq)
+			}
+		}
+	}
+
+	return &Bridge{follower: db.NewChangesFollower(&batchSink{sink: sink}, o.Store, followerOpts)}
+}
+
+// Run forwards changes until ctx is cancelled or the feed, the sink or the
+// checkpoint store fails. It can simply be called again after an error.
+func (b *Bridge) Run(ctx context.Context) error {
+	return b.follower.Run(ctx)
+}
+
+// batchSink buffers the changes the follower publishes and sends them to
+// the Sink when the follower checkpoints, i.e. after every polled page
+type batchSink struct {
+	sink  Sink
+	batch []couchdb.Change
+}
+
+// Publish implements couchdb.EventSink
+func (s *batchSink) Publish(_ context.Context, change *couchdb.Change) error {
+	s.batch = append(s.batch, *change)
+	return nil
+}
+
+// Flush implements couchdb.FlushingEventSink. A failed batch is dropped:
+// the follower stops without checkpointing it, and the next Run reads it
+// from the feed again.
+func (s *batchSink) Flush(ctx context.Context) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	batch := s.batch
+	s.batch = nil
+	return s.sink.Send(ctx, batch)
+}
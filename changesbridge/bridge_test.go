@@ -0,0 +1,147 @@
+package changesbridge
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changesServer serves a feed of n changes in pages of the requested limit
+func changesServer(t *testing.T, n int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/db/_changes", r.URL.Path)
+		assert.Equal(t, "longpoll", r.URL.Query().Get("feed"))
+
+		since, _ := strconv.Atoi(r.URL.Query().Get("since"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var results []map[string]interface{}
+		for seq := since + 1; seq <= n && len(results) < limit; seq++ {
+			results = append(results, map[string]interface{}{
+				"seq": strconv.Itoa(seq), "id": fmt.Sprintf("doc%d", seq), "changes": []map[string]string{{"rev": "1-a"}},
+			})
+		}
+		last := since
+		if len(results) > 0 {
+			last = since + len(results)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "last_seq": strconv.Itoa(last)})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBridge(t *testing.T) {
+	server := changesServer(t, 5)
+	db := couchdb.NewClient(server.URL, nil).DB("db")
+	store := couchdb.NewMemoryCheckpointStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sent [][]string
+	fail := true
+	sink := SinkFunc(func(ctx context.Context, changes []couchdb.Change) error {
+		var ids []string
+		for _, change := range changes {
+			ids = append(ids, change.ID)
+		}
+		if len(sent) == 1 && fail {
+			fail = false
+			return errors.New("broker down")
+		}
+		sent = append(sent, ids)
+		if len(sent) == 3 {
+			cancel()
+		}
+		return nil
+	})
+
+	bridge := New(db, sink, &Options{Store: store, BatchSize: 2})
+
+	err := bridge.Run(ctx)
+	assert.ErrorContains(t, err, "send 2 changes after 2: broker down")
+	seq, _ := store.Load(context.Background(), "changesbridge-db")
+	assert.Equal(t, "2", seq, "only the accepted batch is checkpointed")
+
+	assert.ErrorIs(t, bridge.Run(ctx), context.Canceled)
+	assert.Equal(t, [][]string{{"doc1", "doc2"}, {"doc3", "doc4"}, {"doc5"}}, sent)
+
+	seq, _ = store.Load(context.Background(), "changesbridge-db")
+	assert.Equal(t, "5", seq)
+}
+
+type recordingWriter struct {
+	msgs []KafkaMessage
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...KafkaMessage) error {
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func TestKafkaSink(t *testing.T) {
+	writer := &recordingWriter{}
+	sink := NewKafkaSink(writer, &KafkaSinkOptions{Headers: map[string]string{"source": "orders"}})
+
+	err := sink.Send(context.Background(), []couchdb.Change{
+		{Seq: "1-a", ID: "o1", Changes: []couchdb.ChangeRev{{Rev: "1-x"}}},
+		{Seq: "2-b", ID: "o2", Deleted: true},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, writer.msgs, 2)
+	assert.Equal(t, "o1", string(writer.msgs[0].Key))
+	assert.JSONEq(t, `{"seq":"1-a","id":"o1","changes":[{"rev":"1-x"}]}`, string(writer.msgs[0].Value))
+	assert.Equal(t, map[string]string{"source": "orders", "couchdb-seq": "1-a"}, writer.msgs[0].Headers)
+	assert.Equal(t, "2-b", writer.msgs[1].Headers["couchdb-seq"])
+}
+
+func TestRESTProxyWriter(t *testing.T) {
+	var records []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/topics/orders" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Topic not found"}`))
+			return
+		}
+		assert.Equal(t, "application/vnd.kafka.binary.v2+json", r.Header.Get("Content-Type"))
+
+		var body struct {
+			Records []map[string]string `json:"records"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		records = body.Records
+
+		if body.Records[0]["value"] == base64.StdEncoding.EncodeToString([]byte("bad")) {
+			_, _ = w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"rejected"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":7}]}`))
+	}))
+	defer server.Close()
+
+	writer := NewRESTProxyWriter(server.URL+"/", "orders", nil)
+	ctx := context.Background()
+
+	require.NoError(t, writer.WriteMessages(ctx, KafkaMessage{Key: []byte("o1"), Value: []byte(`{"n":1}`)}))
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("o1")), records[0]["key"])
+
+	err := writer.WriteMessages(ctx, KafkaMessage{Key: []byte("o1"), Value: []byte("bad")})
+	assert.EqualError(t, err, "rest proxy: record 0: rejected")
+
+	err = NewRESTProxyWriter(server.URL+"/missing", "orders", nil).WriteMessages(ctx, KafkaMessage{})
+	assert.EqualError(t, err, "rest proxy: 404 Not Found: Topic not found")
+}
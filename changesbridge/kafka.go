@@ -0,0 +1,161 @@
+package changesbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+// KafkaMessage is a record to produce to Kafka
+type KafkaMessage struct {
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// KafkaWriter produces messages to a topic, in order, returning once all
+// are acknowledged. Adapt the producer of a Kafka client library to it, or
+// use a RESTProxyWriter.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...KafkaMessage) error
+}
+
+// KafkaSinkOptions configures a KafkaSink
+type KafkaSinkOptions struct {
+	// Encode turns a change into the message value, defaults to the change
+	// as JSON: {"seq", "id", "changes", "deleted", "doc"}
+	Encode func(change *couchdb.Change) ([]byte, error)
+
+	// Headers are added to every message, e.g. the source database
+	Headers map[string]string
+}
+
+// KafkaSink is a Sink producing one message per change. Messages are keyed
+// by document ID, so all changes of a document go to the same partition and
+// are consumed in order; use a compacted topic to keep the latest state of
+// each document. Every message carries the change's sequence in the
+// couchdb-seq header.
+type KafkaSink struct {
+	writer KafkaWriter
+	opts   KafkaSinkOptions
+}
+
+// NewKafkaSink creates a sink producing through writer
+func NewKafkaSink(writer KafkaWriter, opts *KafkaSinkOptions) *KafkaSink {
+	s := &KafkaSink{writer: writer}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Encode == nil {
+		s.opts.Encode = func(change *couchdb.Change) ([]byte, error) {
+			return json.Marshal(change)
+		}
+	}
+	return s
+}
+
+// Send implements Sink
+func (s *KafkaSink) Send(ctx context.Context, changes []couchdb.Change) error {
+	msgs := make([]KafkaMessage, 0, len(changes))
+	for i := range changes {
+		change := &changes[i]
+
+		value, err := s.opts.Encode(change)
+		if err != nil {
+			return fmt.Errorf("encode change %s: %w", change.ID, err)
+		}
+
+		headers := make(map[string]string, len(s.opts.Headers)+1)
+		for k, v := range s.opts.Headers {
+			headers[k] = v
+		}
+		headers["couchdb-seq"] = change.Seq.String()
+
+		msgs = append(msgs, KafkaMessage{Key: []byte(change.ID), Value: value, Headers: headers})
+	}
+
+	return s.writer.WriteMessages(ctx, msgs...)
+}
+
+// RESTProxyWriter is a KafkaWriter producing through the Confluent REST
+// Proxy (API v2). The v2 API has no record headers, so they are dropped.
+type RESTProxyWriter struct {
+	url    string
+	client *http.Client
+}
+
+// NewRESTProxyWriter creates a writer producing to topic through the REST
+// Proxy at baseURL. A nil client uses http.DefaultClient.
+func NewRESTProxyWriter(baseURL, topic string, client *http.Client) *RESTProxyWriter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &RESTProxyWriter{
+		url:    strings.TrimSuffix(baseURL, "/") + "/topics/" + url.PathEscape(topic),
+		client: client,
+	}
+}
+
+// WriteMessages implements KafkaWriter
+func (w *RESTProxyWriter) WriteMessages(ctx context.Context, msgs ...KafkaMessage) error {
+	type record struct {
+		Key   []byte `json:"key"` // base64, as the binary embedded format expects
+		Value []byte `json:"value"`
+	}
+	records := make([]record, len(msgs))
+	for i, msg := range msgs {
+		records[i] = record{Key: msg.Key, Value: msg.Value}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.binary.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Offsets []struct {
+			Partition *int   `json:"partition"`
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(data, &result)
+
+	if resp.StatusCode >= 300 {
+		if result.Message != "" {
+			return fmt.Errorf("rest proxy: %s: %s", resp.Status, result.Message)
+		}
+		return fmt.Errorf("rest proxy: %s", resp.Status)
+	}
+
+	// The request succeeds even if single records were rejected
+	for i, offset := range result.Offsets {
+		if offset.ErrorCode != nil || offset.Error != "" {
+			return fmt.Errorf("rest proxy: record %d: %s", i, offset.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package couchdb
+
+import (
+	"context"
+	"sync"
+)
+
+// CheckpointStore persists the last processed changes sequence under a key
+type CheckpointStore interface {
+	// Load returns the stored sequence, or "" if none has been saved
+	Load(ctx context.Context, key string) (string, error)
+	// Save stores the sequence for the key
+	Save(ctx context.Context, key, seq string) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory; useful for tests and
+// consumers that can afford to replay the feed after a restart
+type MemoryCheckpointStore struct {
+	mu   sync.Mutex
+	seqs map[string]string
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{seqs: make(map[string]string)}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryCheckpointStore) Load(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seqs[key], nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryCheckpointStore) Save(_ context.Context, key, seq string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seqs[key] = seq
+	return nil
+}
+
+// LocalCheckpointStore keeps checkpoints in non-replicating _local documents
+type LocalCheckpointStore struct {
+	db *Database
+}
+
+// NewLocalCheckpointStore creates a checkpoint store backed by _local documents in db
+func NewLocalCheckpointStore(db *Database) *LocalCheckpointStore {
+	return &LocalCheckpointStore{db: db}
+}
+
+// Load implements CheckpointStore
+func (s *LocalCheckpointStore) Load(ctx context.Context, key string) (string, error) {
+	doc, err := s.db.GetLocal(ctx, key)
+	if err != nil {
+		if isStatus(err, 404) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	seq, _ := doc.Data["seq"].(string)
+	return seq, nil
+}
+
+// Save implements CheckpointStore
+func (s *LocalCheckpointStore) Save(ctx context.Context, key, seq string) error {
+	doc, err := s.db.GetLocal(ctx, key)
+	if err != nil {
+		if !isStatus(err, 404) {
+			return err
+		}
+		doc = &Document{Data: map[string]interface{}{}}
+	}
+
+	doc.Data["seq"] = seq
+
+	_, err = s.db.PutLocal(ctx, key, doc)
+	return err
+}
@@ -0,0 +1,241 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Membership lists the nodes of a cluster
+type Membership struct {
+	AllNodes     []string `json:"all_nodes"`     // nodes this node knows of
+	ClusterNodes []string `json:"cluster_nodes"` // nodes that are members of the cluster
+}
+
+// NodeInfo identifies a cluster node
+type NodeInfo struct {
+	Name string `json:"name"`
+}
+
+// Stat is a single node statistic
+type Stat struct {
+	Type      string         // "counter", "gauge" or "histogram"
+	Desc      string         // human-readable description
+	Value     float64        // counters and gauges
+	Histogram *HistogramStat // histograms only
+}
+
+// HistogramStat summarises a histogram statistic, typically request times in milliseconds
+type HistogramStat struct {
+	N                 int64        `json:"n"`
+	Min               float64      `json:"min"`
+	Max               float64      `json:"max"`
+	ArithmeticMean    float64      `json:"arithmetic_mean"`
+	GeometricMean     float64      `json:"geometric_mean"`
+	HarmonicMean      float64      `json:"harmonic_mean"`
+	Median            float64      `json:"median"`
+	Variance          float64      `json:"variance"`
+	StandardDeviation float64      `json:"standard_deviation"`
+	Skewness          float64      `json:"skewness"`
+	Kurtosis          float64      `json:"kurtosis"`
+	Percentile        [][2]float64 `json:"percentile"` // [percentile, value] pairs
+}
+
+// SystemStats are a node's Erlang VM statistics from _system
+type SystemStats struct {
+	Uptime                  int64                      `json:"uptime"` // seconds
+	Memory                  SystemMemory               `json:"memory"`
+	RunQueue                int64                      `json:"run_queue"`
+	ETSTableCount           int64                      `json:"ets_table_count"`
+	ContextSwitches         int64                      `json:"context_switches"`
+	Reductions              int64                      `json:"reductions"`
+	GarbageCollectionCount  int64                      `json:"garbage_collection_count"`
+	WordsReclaimed          int64                      `json:"words_reclaimed"`
+	IOInput                 int64                      `json:"io_input"`  // bytes
+	IOOutput                int64                      `json:"io_output"` // bytes
+	OSProcCount             int64                      `json:"os_proc_count"`
+	StaleProcCount          int64                      `json:"stale_proc_count"`
+	ProcessCount            int64                      `json:"process_count"`
+	ProcessLimit            int64                      `json:"process_limit"`
+	MessageQueues           map[string]json.RawMessage `json:"message_queues"` // a length, or a summary object for process groups
+	InternalReplicationJobs int64                      `json:"internal_replication_jobs"`
+}
+
+// SystemMemory is the Erlang VM memory use in bytes
+type SystemMemory struct {
+	Other         int64 `json:"other"`
+	Atom          int64 `json:"atom"`
+	AtomUsed      int64 `json:"atom_used"`
+	Processes     int64 `json:"processes"`
+	ProcessesUsed int64 `json:"processes_used"`
+	Binary        int64 `json:"binary"`
+	Code          int64 `json:"code"`
+	ETS           int64 `json:"ets"`
+}
+
+// Total is the sum of the memory categories
+func (m SystemMemory) Total() int64 {
+	return m.Other + m.Atom + m.Processes + m.Binary + m.Code + m.ETS
+}
+
+// Membership returns the nodes of the cluster
+func (c *Client) Membership(ctx context.Context) (*Membership, error) {
+	var membership Membership
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&membership).
+		Get("/_membership")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &membership, nil
+}
+
+// NodeInfo returns the name of a node. Use LocalNode for the node handling the request.
+func (c *Client) NodeInfo(ctx context.Context, node string) (*NodeInfo, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var info NodeInfo
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&info).
+		Get(buildPath("_node", node))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &info, nil
+}
+
+// NodeStats returns a node's statistics keyed by their dotted path, e.g.
+// "couchdb.open_databases" or "couchdb.httpd_request_methods.GET"
+func (c *Client) NodeStats(ctx context.Context, node string) (map[string]Stat, error) {
+	return c.NodeStatsPath(ctx, node)
+}
+
+// NodeStatsPath returns the statistics under a path of the stats tree,
+// such as ("couchdb", "httpd_request_methods") or a single stat like
+// ("couchdb", "open_databases"). Keys are the full dotted paths, as in NodeStats.
+func (c *Client) NodeStatsPath(ctx context.Context, node string, path ...string) (map[string]Stat, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	url := buildPath(append([]string{"_node", node, "_stats"}, path...)...)
+
+	var raw map[string]json.RawMessage
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&raw).
+		Get(url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	stats := make(map[string]Stat)
+
+	// A path to a single stat returns the stat itself
+	if _, isStat := raw["value"]; isStat && raw["type"] != nil && len(path) > 0 {
+		stat, err := decodeStat(raw)
+		if err != nil {
+			return nil, err
+		}
+		stats[strings.Join(path, ".")] = stat
+		return stats, nil
+	}
+
+	if err := flattenStats(path, raw, stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// NodeSystem returns a node's Erlang VM statistics: memory use, process
+// counts, message queues and I/O totals
+func (c *Client) NodeSystem(ctx context.Context, node string) (*SystemStats, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var stats SystemStats
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&stats).
+		Get(buildPath("_node", node, "_system"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &stats, nil
+}
+
+// flattenStats walks the nested stats tree, collecting every object that
+// has a type and a value as a Stat
+func flattenStats(path []string, tree map[string]json.RawMessage, stats map[string]Stat) error {
+	for name, data := range tree {
+		var node map[string]json.RawMessage
+		if err := json.Unmarshal(data, &node); err != nil {
+			continue // not an object
+		}
+
+		key := append(path[:len(path):len(path)], name)
+
+		if _, isStat := node["value"]; !isStat || node["type"] == nil {
+			if err := flattenStats(key, node, stats); err != nil {
+				return err
+			}
+			continue
+		}
+
+		stat, err := decodeStat(node)
+		if err != nil {
+			return err
+		}
+		stats[strings.Join(key, ".")] = stat
+	}
+
+	return nil
+}
+
+func decodeStat(node map[string]json.RawMessage) (Stat, error) {
+	var stat Stat
+	if err := json.Unmarshal(node["type"], &stat.Type); err != nil {
+		return stat, err
+	}
+	if desc, ok := node["desc"]; ok {
+		_ = json.Unmarshal(desc, &stat.Desc)
+	}
+
+	if stat.Type == "histogram" {
+		stat.Histogram = &HistogramStat{}
+		return stat, json.Unmarshal(node["value"], stat.Histogram)
+	}
+
+	// Counters and gauges are numbers; anything else is left at zero
+	_ = json.Unmarshal(node["value"], &stat.Value)
+	return stat, nil
+}
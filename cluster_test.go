@@ -0,0 +1,132 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterIntrospection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/_membership":
+			_, _ = w.Write([]byte(`{"all_nodes":["a@h1","b@h2"],"cluster_nodes":["a@h1","b@h2","c@h3"]}`))
+		case "/_node/_local":
+			_, _ = w.Write([]byte(`{"name":"a@h1"}`))
+		case "/_node/a@h1/_stats/couchdb/open_databases":
+			_, _ = w.Write([]byte(`{"value": 12, "type": "counter", "desc": "number of open databases"}`))
+		case "/_node/a@h1/_stats/couchdb/httpd_request_methods":
+			_, _ = w.Write([]byte(`{"GET": {"value": 100, "type": "counter", "desc": "GET requests"}}`))
+		case "/_node/a@h1/_system":
+			_, _ = w.Write([]byte(`{"uptime": 3600, "memory": {"other": 1, "atom": 2, "atom_used": 1, "processes": 3, "processes_used": 2, "binary": 4, "code": 5, "ets": 6},
+				"run_queue": 1, "process_count": 400, "process_limit": 262144, "message_queues": {"couch_server": 0, "rexi_server": {"count": 3, "sum": 0}}}`))
+		case "/_node/a@h1/_stats":
+			_, _ = w.Write([]byte(`{
+				"couchdb": {
+					"open_databases": {"value": 12, "type": "counter", "desc": "number of open databases"},
+					"request_time": {"value": {"n": 3, "median": 4.5, "percentile": [[50, 4.5], [99, 9]]}, "type": "histogram", "desc": "length of a request"},
+					"httpd_request_methods": {"GET": {"value": 100, "type": "counter", "desc": "GET requests"}}
+				}
+			}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	membership, err := client.Membership(ctx)
+	require.NoError(t, err)
+	assert.Len(t, membership.ClusterNodes, 3)
+
+	info, err := client.NodeInfo(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "a@h1", info.Name)
+
+	stats, err := client.NodeStats(ctx, info.Name)
+	require.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, float64(12), stats["couchdb.open_databases"].Value)
+	assert.Equal(t, float64(100), stats["couchdb.httpd_request_methods.GET"].Value)
+
+	requestTime := stats["couchdb.request_time"]
+	require.NotNil(t, requestTime.Histogram)
+	assert.Equal(t, 4.5, requestTime.Histogram.Median)
+	assert.Equal(t, [2]float64{99, 9}, requestTime.Histogram.Percentile[1])
+
+	stats, err = client.NodeStatsPath(ctx, info.Name, "couchdb", "open_databases")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Stat{"couchdb.open_databases": {Type: "counter", Desc: "number of open databases", Value: 12}}, stats)
+
+	stats, err = client.NodeStatsPath(ctx, info.Name, "couchdb", "httpd_request_methods")
+	require.NoError(t, err)
+	assert.Equal(t, float64(100), stats["couchdb.httpd_request_methods.GET"].Value)
+
+	system, err := client.NodeSystem(ctx, info.Name)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), system.Uptime)
+	assert.Equal(t, int64(400), system.ProcessCount)
+	assert.Equal(t, int64(21), system.Memory.Total())
+	assert.Contains(t, system.MessageQueues, "rexi_server")
+}
+
+func TestActiveTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/_active_tasks", r.URL.Path)
+		_, _ = w.Write([]byte(`[
+			{"type":"indexer","node":"a@h1","pid":"<0.1.0>","database":"shards/00000000-1fffffff/db.1","design_document":"_design/app","progress":42,"changes_done":420,"total_changes":1000,"started_on":1700000000,"updated_on":1700000010},
+			{"type":"replication","pid":"<0.2.0>","replication_id":"abc+continuous","doc_id":"rep1","source":"http://a/db/","target":"http://b/db/","continuous":true,"docs_written":7,"source_seq":"12-xyz","checkpointed_source_seq":10,"started_on":1700000000,"updated_on":1700000010}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	tasks, err := client.ActiveTasks(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, 42, tasks[0].Progress)
+	assert.Equal(t, "_design/app", tasks[0].DesignDocument)
+	assert.Equal(t, "12-xyz", tasks[1].SourceSeq.String())
+	assert.Equal(t, "10", tasks[1].CheckpointedSourceSeq.String())
+
+	replications, err := client.ActiveTasksOfType(ctx, TaskReplication)
+	require.NoError(t, err)
+	require.Len(t, replications, 1)
+	assert.Equal(t, int64(7), replications[0].DocsWritten)
+}
+
+func TestDBsInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/_dbs_info", r.URL.Path)
+		require.Equal(t, "POST", r.Method)
+		_, _ = w.Write([]byte(`[
+			{"key":"users","info":{"db_name":"users","doc_count":3,"update_seq":"5-g1AAA",
+				"sizes":{"file":1000,"external":300,"active":500},
+				"cluster":{"q":2,"n":3,"w":2,"r":2},"props":{"partitioned":true}}},
+			{"key":"missing","error":"not_found"}
+		]`))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL, nil).DBsInfo(context.Background(), []string{"users", "missing"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	info := results[0].Info
+	require.NotNil(t, info)
+	assert.Equal(t, int64(500), info.Sizes.Active)
+	assert.Equal(t, ClusterParams{Q: 2, N: 3, W: 2, R: 2}, *info.Cluster)
+	assert.True(t, info.Props.Partitioned)
+
+	assert.Nil(t, results[1].Info)
+	assert.Equal(t, "not_found", results[1].Error)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+func dbList(ctx context.Context, client *couchdb.Client, _ []string) error {
+	dbs, err := client.AllDbs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range dbs {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+func dbCreate(ctx context.Context, client *couchdb.Client, args []string) error {
+	fs := flag.NewFlagSet("db create", flag.ContinueOnError)
+	q := fs.Int("q", 0, "number of shards")
+	n := fs.Int("n", 0, "number of replicas")
+	partitioned := fs.Bool("partitioned", false, "create a partitioned database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 1, "<db>"); err != nil {
+		return err
+	}
+
+	return client.CreateDBWithOptions(ctx, fs.Arg(0), &couchdb.DBCreateOptions{
+		Q:           *q,
+		N:           *n,
+		Partitioned: *partitioned,
+	})
+}
+
+func dbDelete(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<db>"); err != nil {
+		return err
+	}
+	return client.DeleteDB(ctx, args[0])
+}
+
+func dbInfo(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<db>"); err != nil {
+		return err
+	}
+
+	info, err := client.DB(args[0]).Info(ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(info)
+}
+
+func docGet(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 2, "<db> <id>"); err != nil {
+		return err
+	}
+
+	doc, err := client.DB(args[0]).Get(ctx, args[1])
+	if err != nil {
+		return err
+	}
+	return printJSON(doc)
+}
+
+func docPut(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<db> [id]"); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	var doc couchdb.Document
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("parse document: %w", err)
+	}
+
+	db := client.DB(args[0])
+	if len(args) > 1 {
+		doc.ID = args[1]
+	}
+
+	var result *couchdb.Document
+	if doc.ID != "" {
+		result, err = db.Update(ctx, doc.ID, &doc)
+	} else {
+		result, err = db.Put(ctx, &doc)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.ID, result.Rev)
+	return nil
+}
+
+func docDelete(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 3, "<db> <id> <rev>"); err != nil {
+		return err
+	}
+	return client.DB(args[0]).Delete(ctx, args[1], args[2])
+}
+
+func ddocSync(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 2, "<db> <dir>"); err != nil {
+		return err
+	}
+
+	db := client.DB(args[0])
+	files, err := filepath.Glob(filepath.Join(args[1], "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		var ddoc couchdb.DesignDocument
+		if err := json.Unmarshal(data, &ddoc); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		ddoc.ID = ""
+		ddoc.Rev = ""
+
+		existing, err := db.GetDesignDoc(ctx, name)
+		switch {
+		case err == nil:
+			ddoc.Rev = existing.Rev
+		case !couchdb.IsNotFound(err):
+			return err
+		}
+
+		result, err := db.PutDesignDoc(ctx, name, &ddoc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		fmt.Println(result.ID, result.Rev)
+	}
+
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
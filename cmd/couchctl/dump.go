@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+func dump(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<db>"); err != nil {
+		return err
+	}
+
+	// Export streams _all_docs in a single request; paging with skip would
+	// make CouchDB walk all earlier rows for every page
+	out := bufio.NewWriter(os.Stdout)
+	if _, err := client.DB(args[0]).Export(ctx, out, nil); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+func restore(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<db>"); err != nil {
+		return err
+	}
+
+	// Import drops the revisions, which are meaningless in the target, and
+	// writes the documents as new ones
+	written, err := client.DB(args[0]).Import(ctx, bufio.NewReader(os.Stdin), nil)
+
+	failed := 0
+	var bulkErr *couchdb.BulkError
+	if errors.As(err, &bulkErr) {
+		for _, f := range bulkErr.Failures {
+			fmt.Fprintf(os.Stderr, "%s: %s - %s\n", f.ID, f.Error, f.Reason)
+		}
+		failed, err = len(bulkErr.Failures), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "restored %d documents, %d failed\n", written, failed)
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redirect replaces *std with a temporary file holding content until the
+// test ends, and returns the file to read what a command wrote to it
+func redirect(t *testing.T, std **os.File, content string) *os.File {
+	t.Helper()
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "std"))
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	orig := *std
+	*std = f
+	t.Cleanup(func() {
+		*std = orig
+		_ = f.Close()
+	})
+	return f
+}
+
+func readAll(t *testing.T, f *os.File) string {
+	t.Helper()
+
+	_, err := f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDumpRestore(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("src")
+	server.CreateDB("dst")
+
+	client := couchdb.NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	for _, doc := range []map[string]interface{}{
+		{"_id": "a", "n": 1},
+		{"_id": "b", "n": 2},
+	} {
+		_, err := client.DB("src").Put(ctx, doc)
+		require.NoError(t, err)
+	}
+
+	stdout := redirect(t, &os.Stdout, "")
+	require.NoError(t, dump(ctx, client, []string{"src"}))
+	dumped := readAll(t, stdout)
+	lines := strings.Split(strings.TrimSpace(dumped), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"_id":"a"`)
+
+	redirect(t, &os.Stdin, dumped)
+	stderr := redirect(t, &os.Stderr, "")
+	require.NoError(t, restore(ctx, client, []string{"dst"}))
+	assert.Equal(t, "restored 2 documents, 0 failed\n", readAll(t, stderr))
+
+	doc, err := client.DB("dst").Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), doc.Data["n"])
+	assert.True(t, strings.HasPrefix(doc.Rev, "1-"), "restored documents start a new history")
+
+	// Documents that exist already are reported, not fatal
+	redirect(t, &os.Stdin, dumped)
+	stderr = redirect(t, &os.Stderr, "")
+	require.NoError(t, restore(ctx, client, []string{"dst"}))
+	out := readAll(t, stderr)
+	assert.Contains(t, out, "a: conflict")
+	assert.Contains(t, out, "restored 0 documents, 2 failed\n")
+}
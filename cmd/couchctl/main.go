@@ -0,0 +1,116 @@
+// Command couchctl is a command line client for CouchDB built on the couchdb package.
+//
+// The server is configured through the COUCHDB_URL, COUCHDB_USER and
+// COUCHDB_PASS environment variables or the matching global flags.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, client *couchdb.Client, args []string) error
+}
+
+var commands = []command{
+	{"db list", "list all databases", dbList},
+	{"db create", "[-q n] [-n n] [-partitioned] <db>  create a database", dbCreate},
+	{"db delete", "<db>  delete a database", dbDelete},
+	{"db info", "<db>  show database information", dbInfo},
+	{"doc get", "<db> <id>  print a document", docGet},
+	{"doc put", "<db> [id] < doc.json  create or update a document", docPut},
+	{"doc delete", "<db> <id> <rev>  delete a document", docDelete},
+	{"dump", "<db> > docs.jsonl  write all documents as JSON lines", dump},
+	{"restore", "<db> < docs.jsonl  load documents from JSON lines", restore},
+	{"ddoc sync", "<db> <dir>  upload every <name>.json in dir as _design/<name>", ddocSync},
+	{"replication start", "[-continuous] [-create-target] <id> <source> <target>  create a _replicator document", replicationStart},
+	{"replication status", "<id>  show scheduler state of a replication", replicationStatus},
+	{"replication wait", "[-timeout d] [-interval d] <id>  block until a replication completes", replicationWait},
+	{"replication cancel", "<id>  delete a _replicator document", replicationCancel},
+	{"changes", "[-since seq] [-docs] <db>  tail the changes feed", changesTail},
+}
+
+func main() {
+	global := flag.NewFlagSet("couchctl", flag.ExitOnError)
+	url := global.String("url", envOr("COUCHDB_URL", "http://localhost:5984"), "CouchDB server URL")
+	user := global.String("user", os.Getenv("COUCHDB_USER"), "username")
+	pass := global.String("pass", os.Getenv("COUCHDB_PASS"), "password")
+	timeout := global.Duration("timeout", 30*time.Second, "HTTP request timeout")
+	global.Usage = usage
+	_ = global.Parse(os.Args[1:])
+
+	cmd, args := lookup(global.Args())
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	client := couchdb.NewClient(*url, &couchdb.ClientOptions{
+		Username: *user,
+		Password: *pass,
+		Timeout:  *timeout,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := cmd.run(ctx, client, args); err != nil {
+		fmt.Fprintln(os.Stderr, "couchctl:", err)
+		os.Exit(1)
+	}
+}
+
+// lookup finds the longest command matching the leading arguments
+func lookup(args []string) (*command, []string) {
+	var best *command
+	var rest []string
+
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") != commands[i].name {
+			continue
+		}
+		if best == nil || len(words) > len(strings.Fields(best.name)) {
+			best = &commands[i]
+			rest = args[len(words):]
+		}
+	}
+
+	return best, rest
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: couchctl [-url u] [-user u] [-pass p] [-timeout d] <command> [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func requireArgs(args []string, n int, names string) error {
+	if len(args) < n {
+		return fmt.Errorf("missing arguments, expected %s", names)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	cmd, args := lookup([]string{"replication", "start", "-continuous", "r1", "a", "b"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "replication start", cmd.name)
+	assert.Equal(t, []string{"-continuous", "r1", "a", "b"}, args)
+
+	cmd, args = lookup([]string{"dump", "mydb"})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "dump", cmd.name)
+	assert.Equal(t, []string{"mydb"}, args)
+
+	cmd, _ = lookup([]string{"db"})
+	assert.Nil(t, cmd)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+func replicationStart(ctx context.Context, client *couchdb.Client, args []string) error {
+	fs := flag.NewFlagSet("replication start", flag.ContinueOnError)
+	continuous := fs.Bool("continuous", false, "keep replicating new changes")
+	createTarget := fs.Bool("create-target", false, "create the target database")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 3, "<id> <source> <target>"); err != nil {
+		return err
+	}
+
+	result, err := client.CreateReplication(ctx, &couchdb.ReplicationSpec{
+		ID:           fs.Arg(0),
+		Source:       couchdb.ReplicationEndpoint{URL: fs.Arg(1)},
+		Target:       couchdb.ReplicationEndpoint{URL: fs.Arg(2)},
+		Continuous:   *continuous,
+		CreateTarget: *createTarget,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.ID, result.Rev)
+	return nil
+}
+
+func replicationStatus(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<id>"); err != nil {
+		return err
+	}
+
+	doc, err := client.GetSchedulerDoc(ctx, "", args[0])
+	if err != nil {
+		return err
+	}
+	return printJSON(doc)
+}
+
+func replicationWait(ctx context.Context, client *couchdb.Client, args []string) error {
+	fs := flag.NewFlagSet("replication wait", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 0, "give up after this duration")
+	interval := fs.Duration("interval", 2*time.Second, "poll interval")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 1, "<id>"); err != nil {
+		return err
+	}
+
+	doc, err := client.WaitForReplication(ctx, fs.Arg(0), &couchdb.WaitOptions{
+		PollInterval: *interval,
+		Timeout:      *timeout,
+		Progress: func(doc *couchdb.SchedulerDoc) {
+			if doc.Info != nil {
+				fmt.Fprintf(os.Stderr, "%s: %d docs written\n", doc.State, doc.Info.DocsWritten)
+			} else {
+				fmt.Fprintln(os.Stderr, doc.State)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(doc.State)
+	return nil
+}
+
+func replicationCancel(ctx context.Context, client *couchdb.Client, args []string) error {
+	if err := requireArgs(args, 1, "<id>"); err != nil {
+		return err
+	}
+
+	return client.CancelReplication(ctx, args[0])
+}
+
+func changesTail(ctx context.Context, client *couchdb.Client, args []string) error {
+	fs := flag.NewFlagSet("changes", flag.ContinueOnError)
+	since := fs.String("since", "now", "sequence to start from")
+	includeDocs := fs.Bool("docs", false, "include documents")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(fs.Args(), 1, "<db>"); err != nil {
+		return err
+	}
+
+	db := client.DB(fs.Arg(0))
+	enc := json.NewEncoder(os.Stdout)
+	seq := *since
+
+	for {
+		changes, err := db.GetChanges(ctx, &couchdb.ChangesOptions{
+			Feed:        "longpoll",
+			Since:       seq,
+			IncludeDocs: *includeDocs,
+			Timeout:     20000,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for _, change := range changes.Results {
+			if err := enc.Encode(change); err != nil {
+				return err
+			}
+		}
+		seq = changes.LastSeq.String()
+	}
+}
@@ -0,0 +1,212 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CompactAllOptions controls CompactAll
+type CompactAllOptions struct {
+	Concurrency     int           // compactions running at once, defaults to 1
+	PollInterval    time.Duration // compact_running poll interval, defaults to one second
+	SkipViewCleanup bool          // do not run _view_cleanup afterwards
+}
+
+// CompactAll performs full maintenance of the database: it compacts the
+// database file and the view indexes of every design document, waits for
+// each compaction to finish and finally removes stale index files.
+func (db *Database) CompactAll(ctx context.Context, opts *CompactAllOptions) error {
+	if opts == nil {
+		opts = &CompactAllOptions{}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	designDocs, err := db.ListDesignDocs(ctx)
+	if err != nil {
+		return err
+	}
+
+	jobs := []func() error{
+		func() error {
+			if err := db.Compact(ctx); err != nil {
+				return err
+			}
+			return db.WaitForCompaction(ctx, interval, nil)
+		},
+	}
+
+	for _, row := range designDocs.Rows {
+		name := strings.TrimPrefix(row.ID, "_design/")
+		jobs = append(jobs, func() error {
+			if err := db.CompactDesignDoc(ctx, name); err != nil {
+				return err
+			}
+			return db.WaitForViewCompaction(ctx, name, interval, nil)
+		})
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for _, job := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+		go func(job func() error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := job(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if opts.SkipViewCleanup {
+		return nil
+	}
+
+	return db.ViewCleanup(ctx)
+}
+
+// CompactionProgress describes a compaction that is still running
+type CompactionProgress struct {
+	Percent int          // average progress of Tasks, 0 when none are reported yet
+	Tasks   []ActiveTask // the compaction tasks, one per shard in a cluster
+}
+
+// WaitForCompaction polls the database info every pollInterval until
+// compaction has finished. If progress is not nil it is called after each
+// poll that finds compaction still running, with the matching active tasks.
+func (db *Database) WaitForCompaction(ctx context.Context, pollInterval time.Duration, progress func(CompactionProgress)) error {
+	return poll(ctx, pollInterval, func() (bool, error) {
+		info, err := db.Info(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !info.CompactRunning {
+			return true, nil
+		}
+
+		db.reportCompaction(ctx, TaskDatabaseCompaction, "", progress)
+		return false, nil
+	})
+}
+
+// WaitForViewCompaction polls a design document's index info every
+// pollInterval until compaction of its views has finished, reporting
+// progress like WaitForCompaction
+func (db *Database) WaitForViewCompaction(ctx context.Context, designDoc string, pollInterval time.Duration, progress func(CompactionProgress)) error {
+	return poll(ctx, pollInterval, func() (bool, error) {
+		info, err := db.ViewInfo(ctx, designDoc, "")
+		if err != nil {
+			return false, err
+		}
+
+		index, _ := info["view_index"].(map[string]interface{})
+		if running, _ := index["compact_running"].(bool); !running {
+			return true, nil
+		}
+
+		db.reportCompaction(ctx, TaskViewCompaction, "_design/"+designDoc, progress)
+		return false, nil
+	})
+}
+
+// reportCompaction passes the database's running compaction tasks to
+// progress. Reading the tasks needs admin rights; as progress is optional,
+// a failure is logged and the report skipped rather than ending the wait.
+func (db *Database) reportCompaction(ctx context.Context, taskType, designDoc string, progress func(CompactionProgress)) {
+	if progress == nil {
+		return
+	}
+
+	tasks, err := db.client.ActiveTasksOfType(ctx, taskType)
+	if err != nil {
+		db.client.logger.Warnf("compaction progress of %s: %v", db.name, err)
+		return
+	}
+
+	var p CompactionProgress
+	for _, task := range tasks {
+		if !isShardOf(task.Database, db.name) || (designDoc != "" && task.DesignDocument != designDoc) {
+			continue
+		}
+		p.Tasks = append(p.Tasks, task)
+		p.Percent += task.Progress
+	}
+	if len(p.Tasks) > 0 {
+		p.Percent /= len(p.Tasks)
+	}
+
+	progress(p)
+}
+
+// isShardOf reports whether an active task's database, either a plain name
+// or a shard file such as "shards/00000000-7fffffff/db.1700000000", is name
+func isShardOf(database, name string) bool {
+	if database == name {
+		return true
+	}
+	// Database names may contain slashes, so only the range is cut off
+	parts := strings.SplitN(database, "/", 3)
+	if len(parts) != 3 || parts[0] != "shards" {
+		return false
+	}
+
+	file := parts[2]
+	if dot := strings.LastIndex(file, "."); dot >= 0 {
+		file = file[:dot]
+	}
+	return file == name
+}
+
+// poll calls done every interval until it reports true, fails, or ctx ends
+func poll(ctx context.Context, interval time.Duration, done func() (bool, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		finished, err := done()
+		if err != nil {
+			return err
+		}
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
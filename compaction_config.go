@@ -0,0 +1,182 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Smoosh (auto-compaction daemon) configuration sections
+const (
+	SmooshSection       = "smoosh"
+	smooshChannelPrefix = "smoosh."
+)
+
+// SmooshConfig holds the top-level [smoosh] settings
+type SmooshConfig struct {
+	DBChannels      []string // channels used for database compaction
+	ViewChannels    []string // channels used for view index compaction
+	CleanupChannels []string // channels used for index cleanup
+	Staleness       int      // minutes before priorities are recalculated
+}
+
+// SmooshChannel holds the settings of a single [smoosh.<channel>] section.
+// Zero values are left untouched when writing.
+type SmooshChannel struct {
+	Priority     string  // "ratio", "slack" or "upgrade"
+	MinPriority  float64 // minimum priority to enqueue a compaction
+	MaxPriority  float64 // maximum priority to enqueue a compaction
+	MinSize      int64   // minimum file size in bytes
+	MaxSize      int64   // maximum file size in bytes
+	MinChanges   int64   // minimum number of changes since last compaction
+	From         string  // start of the compaction window, "HH:MM"
+	To           string  // end of the compaction window, "HH:MM"
+	StrictWindow bool    // suspend running compactions outside the window
+	Concurrency  int     // maximum concurrent compactions
+	Capacity     int     // maximum queued jobs
+}
+
+// GetSmooshConfig reads the [smoosh] section of a node
+func (c *Client) GetSmooshConfig(ctx context.Context, node string) (*SmooshConfig, error) {
+	section, err := c.GetConfigSection(ctx, node, SmooshSection)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &SmooshConfig{
+		DBChannels:      splitConfigList(section["db_channels"]),
+		ViewChannels:    splitConfigList(section["view_channels"]),
+		CleanupChannels: splitConfigList(section["cleanup_channels"]),
+	}
+
+	if v, ok := section["staleness"]; ok {
+		if cfg.Staleness, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid smoosh staleness %q: %w", v, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// SetSmooshConfig writes the non-empty fields of cfg to the [smoosh] section of a node
+func (c *Client) SetSmooshConfig(ctx context.Context, node string, cfg *SmooshConfig) error {
+	values := map[string]string{}
+
+	if len(cfg.DBChannels) > 0 {
+		values["db_channels"] = strings.Join(cfg.DBChannels, ",")
+	}
+	if len(cfg.ViewChannels) > 0 {
+		values["view_channels"] = strings.Join(cfg.ViewChannels, ",")
+	}
+	if len(cfg.CleanupChannels) > 0 {
+		values["cleanup_channels"] = strings.Join(cfg.CleanupChannels, ",")
+	}
+	if cfg.Staleness > 0 {
+		values["staleness"] = strconv.Itoa(cfg.Staleness)
+	}
+
+	return c.setConfigValues(ctx, node, SmooshSection, values)
+}
+
+// GetSmooshChannel reads the [smoosh.<channel>] section of a node
+func (c *Client) GetSmooshChannel(ctx context.Context, node, channel string) (*SmooshChannel, error) {
+	section, err := c.GetConfigSection(ctx, node, smooshChannelPrefix+channel)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := &SmooshChannel{
+		Priority:     section["priority"],
+		From:         section["from"],
+		To:           section["to"],
+		StrictWindow: section["strict_window"] == "true",
+	}
+
+	parsers := []struct {
+		key   string
+		parse func(string) error
+	}{
+		{"min_priority", func(v string) (err error) { ch.MinPriority, err = strconv.ParseFloat(v, 64); return }},
+		{"max_priority", func(v string) (err error) { ch.MaxPriority, err = strconv.ParseFloat(v, 64); return }},
+		{"min_size", func(v string) (err error) { ch.MinSize, err = strconv.ParseInt(v, 10, 64); return }},
+		{"max_size", func(v string) (err error) { ch.MaxSize, err = strconv.ParseInt(v, 10, 64); return }},
+		{"min_changes", func(v string) (err error) { ch.MinChanges, err = strconv.ParseInt(v, 10, 64); return }},
+		{"concurrency", func(v string) (err error) { ch.Concurrency, err = strconv.Atoi(v); return }},
+		{"capacity", func(v string) (err error) { ch.Capacity, err = strconv.Atoi(v); return }},
+	}
+
+	for _, p := range parsers {
+		v, ok := section[p.key]
+		if !ok {
+			continue
+		}
+		if err := p.parse(v); err != nil {
+			return nil, fmt.Errorf("invalid smoosh.%s %s %q: %w", channel, p.key, v, err)
+		}
+	}
+
+	return ch, nil
+}
+
+// SetSmooshChannel writes the non-zero fields of ch to the [smoosh.<channel>] section of a node
+func (c *Client) SetSmooshChannel(ctx context.Context, node, channel string, ch *SmooshChannel) error {
+	values := map[string]string{}
+
+	if ch.Priority != "" {
+		values["priority"] = ch.Priority
+	}
+	if ch.MinPriority > 0 {
+		values["min_priority"] = strconv.FormatFloat(ch.MinPriority, 'f', -1, 64)
+	}
+	if ch.MaxPriority > 0 {
+		values["max_priority"] = strconv.FormatFloat(ch.MaxPriority, 'f', -1, 64)
+	}
+	if ch.MinSize > 0 {
+		values["min_size"] = strconv.FormatInt(ch.MinSize, 10)
+	}
+	if ch.MaxSize > 0 {
+		values["max_size"] = strconv.FormatInt(ch.MaxSize, 10)
+	}
+	if ch.MinChanges > 0 {
+		values["min_changes"] = strconv.FormatInt(ch.MinChanges, 10)
+	}
+	if ch.From != "" {
+		values["from"] = ch.From
+	}
+	if ch.To != "" {
+		values["to"] = ch.To
+	}
+	if ch.StrictWindow {
+		values["strict_window"] = "true"
+	}
+	if ch.Concurrency > 0 {
+		values["concurrency"] = strconv.Itoa(ch.Concurrency)
+	}
+	if ch.Capacity > 0 {
+		values["capacity"] = strconv.Itoa(ch.Capacity)
+	}
+
+	return c.setConfigValues(ctx, node, smooshChannelPrefix+channel, values)
+}
+
+func (c *Client) setConfigValues(ctx context.Context, node, section string, values map[string]string) error {
+	for key, value := range values {
+		if _, err := c.SetConfigKey(ctx, node, section, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitConfigList splits a comma-separated config value, skipping blank entries
+func splitConfigList(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,123 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitConfigList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"ratio_dbs", []string{"ratio_dbs"}},
+		{" ratio_dbs , slack_dbs,upgrade_dbs ", []string{"ratio_dbs", "slack_dbs", "upgrade_dbs"}},
+		{"ratio_dbs,, ,slack_dbs,", []string{"ratio_dbs", "slack_dbs"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, splitConfigList(tt.value), "%q", tt.value)
+	}
+}
+
+// configServer serves the config sections in sections and records writes
+func configServer(t *testing.T, sections map[string]map[string]string) (*httptest.Server, map[string]string) {
+	written := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/_node/_local/_config/")
+
+		if r.Method == "PUT" {
+			var value string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&value))
+			written[path] = value
+			_, _ = w.Write([]byte(`""`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(sections[path])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, written
+}
+
+func TestSmooshConfig(t *testing.T) {
+	server, written := configServer(t, map[string]map[string]string{
+		"smoosh": {"db_channels": " ratio_dbs , slack_dbs", "view_channels": "", "cleanup_channels": " ", "staleness": "5"},
+	})
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	cfg, err := client.GetSmooshConfig(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, &SmooshConfig{DBChannels: []string{"ratio_dbs", "slack_dbs"}, Staleness: 5}, cfg)
+
+	require.NoError(t, client.SetSmooshConfig(ctx, "", &SmooshConfig{
+		DBChannels:   []string{"ratio_dbs", "upgrade_dbs"},
+		ViewChannels: []string{"ratio_views"},
+		Staleness:    10,
+	}))
+	assert.Equal(t, map[string]string{
+		"smoosh/db_channels":   "ratio_dbs,upgrade_dbs",
+		"smoosh/view_channels": "ratio_views",
+		"smoosh/staleness":     "10",
+	}, written)
+
+	// Empty fields are left untouched
+	clear(written)
+	require.NoError(t, client.SetSmooshConfig(ctx, "", &SmooshConfig{}))
+	assert.Empty(t, written)
+}
+
+func TestSmooshConfig_InvalidStaleness(t *testing.T) {
+	server, _ := configServer(t, map[string]map[string]string{
+		"smoosh": {"staleness": "soon"},
+	})
+
+	_, err := NewClient(server.URL, nil).GetSmooshConfig(context.Background(), "")
+	assert.ErrorContains(t, err, `invalid smoosh staleness "soon"`)
+}
+
+func TestSmooshChannel(t *testing.T) {
+	server, written := configServer(t, map[string]map[string]string{
+		"smoosh.ratio_dbs": {
+			"priority": "ratio", "min_priority": "2.5", "max_size": "1073741824",
+			"from": "20:00", "to": "06:00", "strict_window": "true", "concurrency": "2",
+		},
+		"smoosh.broken": {"capacity": "many"},
+	})
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	ch, err := client.GetSmooshChannel(ctx, "", "ratio_dbs")
+	require.NoError(t, err)
+	assert.Equal(t, &SmooshChannel{
+		Priority: "ratio", MinPriority: 2.5, MaxSize: 1 << 30,
+		From: "20:00", To: "06:00", StrictWindow: true, Concurrency: 2,
+	}, ch)
+
+	_, err = client.GetSmooshChannel(ctx, "", "broken")
+	assert.ErrorContains(t, err, `invalid smoosh.broken capacity "many"`)
+
+	require.NoError(t, client.SetSmooshChannel(ctx, "", "slack_dbs", &SmooshChannel{
+		Priority: "slack", MinPriority: 1.5, MinSize: 1024, MinChanges: 100, StrictWindow: true, Capacity: 50,
+	}))
+	assert.Equal(t, map[string]string{
+		"smoosh.slack_dbs/priority":      "slack",
+		"smoosh.slack_dbs/min_priority":  "1.5",
+		"smoosh.slack_dbs/min_size":      "1024",
+		"smoosh.slack_dbs/min_changes":   "100",
+		"smoosh.slack_dbs/strict_window": "true",
+		"smoosh.slack_dbs/capacity":      "50",
+	}, written)
+}
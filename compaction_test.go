@@ -0,0 +1,128 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForCompaction(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/logs":
+			running := polls.Add(1) < 3
+			_, _ = w.Write([]byte(`{"db_name":"logs","compact_running":` + map[bool]string{true: "true", false: "false"}[running] + `}`))
+		case "/_active_tasks":
+			_, _ = w.Write([]byte(`[
+				{"type":"database_compaction","database":"shards/00000000-7fffffff/logs.1700000000","progress":40},
+				{"type":"database_compaction","database":"shards/80000000-ffffffff/logs.1700000000","progress":60},
+				{"type":"database_compaction","database":"shards/00000000-7fffffff/other.1700000000","progress":10},
+				{"type":"indexer","database":"shards/00000000-7fffffff/logs.1700000000","progress":90}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("logs")
+
+	var reports []CompactionProgress
+	err := db.WaitForCompaction(context.Background(), 5*time.Millisecond, func(p CompactionProgress) {
+		reports = append(reports, p)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, reports, 2)
+	assert.Equal(t, 50, reports[0].Percent)
+	assert.Len(t, reports[0].Tasks, 2)
+}
+
+func TestIsShardOf(t *testing.T) {
+	assert.True(t, isShardOf("logs", "logs"))
+	assert.True(t, isShardOf("shards/00000000-1fffffff/logs.1700000000", "logs"))
+	assert.True(t, isShardOf("shards/00000000-1fffffff/app/logs.1700000000", "app/logs"))
+	assert.False(t, isShardOf("shards/00000000-1fffffff/logs2.1700000000", "logs"))
+	assert.False(t, isShardOf("other", "logs"))
+}
+
+func TestWaitForCompaction_ProgressUnavailable(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/logs":
+			running := polls.Add(1) < 3
+			_, _ = w.Write([]byte(`{"db_name":"logs","compact_running":` + map[bool]string{true: "true", false: "false"}[running] + `}`))
+		case "/_active_tasks":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":"forbidden","reason":"You are not a server admin."}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("logs")
+
+	// Without admin rights the wait still ends, just without progress reports
+	reports := 0
+	err := db.WaitForCompaction(context.Background(), 5*time.Millisecond, func(CompactionProgress) {
+		reports++
+	})
+	require.NoError(t, err)
+	assert.Zero(t, reports)
+	assert.Equal(t, int32(3), polls.Load())
+}
+
+func TestCompactAll(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/logs/_all_docs":
+			assert.Equal(t, `"_design/"`, r.URL.Query().Get("startkey"))
+			_, _ = w.Write([]byte(`{"total_rows":2,"rows":[{"id":"_design/app","key":"_design/app"},{"id":"_design/stats","key":"_design/stats"}]}`))
+		case r.Method == "POST":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case r.URL.Path == "/logs":
+			_, _ = w.Write([]byte(`{"db_name":"logs","compact_running":false}`))
+		default:
+			_, _ = w.Write([]byte(`{"name":"app","view_index":{"compact_running":false}}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("logs")
+	require.NoError(t, db.CompactAll(context.Background(), &CompactAllOptions{Concurrency: 2, PollInterval: 5 * time.Millisecond}))
+
+	assert.Equal(t, "GET /logs/_all_docs", requests[0])
+	assert.ElementsMatch(t, []string{
+		"POST /logs/_compact", "GET /logs",
+		"POST /logs/_compact/app", "GET /logs/_design/app/_info",
+		"POST /logs/_compact/stats", "GET /logs/_design/stats/_info",
+	}, requests[1:len(requests)-1])
+	assert.Equal(t, "POST /logs/_view_cleanup", requests[len(requests)-1])
+
+	requests = nil
+	require.NoError(t, db.CompactAll(context.Background(), &CompactAllOptions{SkipViewCleanup: true}))
+	assert.NotContains(t, requests, "POST /logs/_view_cleanup")
+}
@@ -0,0 +1,82 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConflictSet holds the winning revision of a document and the bodies of
+// its conflicting revisions
+type ConflictSet struct {
+	Current   *Document
+	Conflicts []*Document
+}
+
+// HasConflicts reports whether the document has conflicting revisions
+func (s *ConflictSet) HasConflicts() bool {
+	return len(s.Conflicts) > 0
+}
+
+// Revs returns the revisions of the conflicting documents
+func (s *ConflictSet) Revs() []string {
+	revs := make([]string, len(s.Conflicts))
+	for i, doc := range s.Conflicts {
+		revs[i] = doc.Rev
+	}
+	return revs
+}
+
+// GetConflicts returns the current revision of a document together with the
+// bodies of its conflicting revisions
+func (db *Database) GetConflicts(ctx context.Context, id string) (*ConflictSet, error) {
+	doc, err := db.GetWithOptions(ctx, id, &GetOptions{Conflicts: true})
+	if err != nil {
+		return nil, err
+	}
+
+	set := &ConflictSet{Current: doc}
+	if len(doc.Conflicts) == 0 {
+		return set, nil
+	}
+
+	revs, err := db.GetOpenRevs(ctx, id, doc.Conflicts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rev := range revs {
+		// A conflict may have been resolved since the first read
+		if rev.OK != nil && !rev.OK.Deleted {
+			set.Conflicts = append(set.Conflicts, rev.OK)
+		}
+	}
+
+	return set, nil
+}
+
+// ResolveConflict writes winner as the new revision of the document and
+// deletes the losing revisions in a single _bulk_docs request. The winner
+// must carry the _rev of the branch it extends, typically the current
+// revision. A *BulkError is returned if any write was rejected.
+func (db *Database) ResolveConflict(ctx context.Context, id string, winner interface{}, losingRevs ...string) (BulkResponse, error) {
+	body, err := toMap(winner)
+	if err != nil {
+		return nil, err
+	}
+
+	if docID, ok := body["_id"]; ok && docID != id {
+		return nil, fmt.Errorf("winner has ID %v, expected %s", docID, id)
+	}
+	body["_id"] = id
+
+	docs := []interface{}{body}
+	for _, rev := range losingRevs {
+		docs = append(docs, map[string]interface{}{
+			"_id":      id,
+			"_rev":     rev,
+			"_deleted": true,
+		})
+	}
+
+	return db.BulkWithOptions(ctx, docs, &BulkOptions{FailOnError: true})
+}
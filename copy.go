@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"context"
+	"strings"
+)
+
+// CopyOptions configures CopyDatabase
+type CopyOptions struct {
+	BatchSize      int  // documents per _bulk_docs request, defaults to 500
+	Attachments    bool // copy attachment content; otherwise attachments are dropped
+	SkipDesignDocs bool // leave out _design/ documents
+
+	// KeepRevisions writes the documents with their source revisions
+	// (new_edits=false), like replication does. Otherwise they are written
+	// as new documents, which fails for IDs that already exist in the target.
+	KeepRevisions bool
+
+	// Filter selects the documents to copy; all are copied when nil
+	Filter func(doc *Document) bool
+
+	// TransformID maps a source ID to its ID in the target, e.g. to add a
+	// prefix. Returning "" skips the document.
+	TransformID func(id string) string
+
+	// Transform may modify a document before it is written
+	Transform func(doc *Document) error
+
+	// Progress is called after each batch with the number of documents
+	// read from the source and written to the target so far
+	Progress func(CopyProgress)
+}
+
+// CopyProgress reports how far a CopyDatabase has got
+type CopyProgress struct {
+	Read    int // source documents read, including skipped ones
+	Sent    int // documents sent to the target
+	Written int // documents the target accepted
+}
+
+// CopyDatabase streams the documents of source from _all_docs and bulk
+// writes them to target. It is meant for cases where replication is not an
+// option, such as copying between servers that cannot reach each other or
+// reshaping documents on the way. It returns the number of documents
+// written; documents rejected by the target are reported in a *BulkError.
+func CopyDatabase(ctx context.Context, source, target *Database, opts *CopyOptions) (int, error) {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	rows, err := source.AllDocsStream(ctx, &ViewOptions{IncludeDocs: true, Attachments: opts.Attachments})
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	read := 0
+	bw := newBatchWriter(target, batchSize, opts.KeepRevisions)
+	if opts.Progress != nil {
+		bw.onBatch = func(sent, written int) {
+			opts.Progress(CopyProgress{Read: read, Sent: sent, Written: written})
+		}
+	}
+
+	for rows.Next() {
+		doc := rows.Row().Doc
+		if doc == nil {
+			continue
+		}
+		read++
+
+		if opts.SkipDesignDocs && strings.HasPrefix(doc.ID, "_design/") {
+			continue
+		}
+		if opts.Filter != nil && !opts.Filter(doc) {
+			continue
+		}
+
+		if opts.TransformID != nil {
+			if doc.ID = opts.TransformID(doc.ID); doc.ID == "" {
+				continue
+			}
+		}
+		if !opts.KeepRevisions {
+			doc.Rev = ""
+		}
+		if !opts.Attachments {
+			doc.Attachments = nil
+		}
+		if opts.Transform != nil {
+			if err := opts.Transform(doc); err != nil {
+				return bw.written, err
+			}
+		}
+
+		if err := bw.add(ctx, doc); err != nil {
+			return bw.written, err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return bw.written, err
+	}
+
+	return bw.close(ctx)
+}
@@ -0,0 +1,72 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig holds the [cors] settings written by ConfigureCORS. Empty
+// lists leave the current values untouched.
+type CORSConfig struct {
+	Origins     []string // allowed origins such as "https://app.example.com", or "*" for any
+	Credentials bool     // allow cookies and authorization headers; not allowed with "*"
+	Methods     []string // allowed methods, CouchDB defaults to all
+	Headers     []string // extra request headers browsers may send
+	MaxAge      int      // seconds browsers may cache a preflight response
+
+	// Nodes to configure; defaults to every node of the cluster
+	Nodes []string
+}
+
+// ConfigureCORS enables CORS and writes the [cors] section on each node.
+// CouchDB keeps its configuration per node, so all of them need the
+// settings for requests to be answered the same wherever they land.
+func (c *Client) ConfigureCORS(ctx context.Context, cfg CORSConfig) error {
+	if len(cfg.Origins) == 0 {
+		return errors.New("cors: at least one origin is required")
+	}
+	if cfg.Credentials {
+		for _, origin := range cfg.Origins {
+			if origin == "*" {
+				return errors.New("cors: credentials cannot be allowed for the \"*\" origin")
+			}
+		}
+	}
+
+	nodes := cfg.Nodes
+	if len(nodes) == 0 {
+		membership, err := c.Membership(ctx)
+		if err != nil {
+			return err
+		}
+		nodes = membership.ClusterNodes
+	}
+
+	values := map[string]string{
+		"origins":     strings.Join(cfg.Origins, ", "),
+		"credentials": strconv.FormatBool(cfg.Credentials),
+	}
+	if len(cfg.Methods) > 0 {
+		values["methods"] = strings.Join(cfg.Methods, ", ")
+	}
+	if len(cfg.Headers) > 0 {
+		values["headers"] = strings.Join(cfg.Headers, ", ")
+	}
+	if cfg.MaxAge > 0 {
+		values["max_age"] = strconv.Itoa(cfg.MaxAge)
+	}
+
+	for _, node := range nodes {
+		if _, err := c.SetConfigKey(ctx, node, "chttpd", "enable_cors", "true"); err != nil {
+			return fmt.Errorf("cors on %s: %w", node, err)
+		}
+		if err := c.setConfigValues(ctx, node, "cors", values); err != nil {
+			return fmt.Errorf("cors on %s: %w", node, err)
+		}
+	}
+
+	return nil
+}
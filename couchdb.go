@@ -4,6 +4,7 @@ package couchdb
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -20,20 +21,76 @@ func NewClient(baseURL string, opts *ClientOptions) *Client {
 		opts.Timeout = 30 * time.Second
 	}
 
+	logger := opts.Logger
+	if logger == nil {
+		logger = newStdLogger()
+	}
+
+	// Both clients share one connection pool
+	var transport http.RoundTripper = http.DefaultTransport
+	if opts.Transport != nil {
+		transport = opts.Transport.newTransport()
+	}
+
+	c := &Client{
+		resty:   newRestyClient(baseURL, opts, logger, transport, opts.Timeout),
+		stream:  newRestyClient(baseURL, opts, logger, transport, 0),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		logger:  logger,
+
+		maxDocumentSize: opts.MaxDocumentSize,
+		maxRequestSize:  opts.MaxRequestSize,
+		session:         newSessionState(opts.SessionTimeout),
+	}
+
+	for _, r := range []*resty.Client{c.resty, c.stream} {
+		if !opts.DisableRequestID {
+			r.OnBeforeRequest(applyRequestID)
+		}
+		r.OnBeforeRequest(applyRequestOverrides)
+		r.OnBeforeRequest(c.applySession)
+		r.OnAfterResponse(c.captureSession)
+		r.OnAfterResponse(captureResponseInfo)
+
+		if opts.Metrics != nil {
+			r.SetTransport(&metricsTransport{next: r.GetClient().Transport, metrics: opts.Metrics})
+		}
+	}
+
+	// Streamed bodies cannot be replayed, so only the buffered client retries
+	opts.Retry.apply(c.resty, logger)
+
+	return c
+}
+
+// newRestyClient builds a configured HTTP client. Streaming requests use a
+// separate client without a default timeout, since their bodies are read
+// for as long as the feed stays open.
+func newRestyClient(baseURL string, opts *ClientOptions, logger Logger, transport http.RoundTripper, timeout time.Duration) *resty.Client {
 	client := resty.New()
 	client.SetBaseURL(strings.TrimSuffix(baseURL, "/"))
-	client.SetTimeout(opts.Timeout)
+	// The transport enforces the timeout so that it can be overridden per request
+	client.SetTransport(&timeoutTransport{next: transport, timeout: timeout})
 	client.SetHeader("Content-Type", "application/json")
 	client.SetDebug(opts.Debug)
+	client.SetLogger(logger)
+	// Session cookies are managed by the client itself, see session.go
+	client.SetCookieJar(nil)
 
 	if opts.Username != "" && opts.Password != "" {
 		client.SetBasicAuth(opts.Username, opts.Password)
 	}
 
-	return &Client{
-		resty:   client,
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-	}
+	client.SetPreRequestHook(prepareRawRequest)
+
+	return client
+}
+
+// prepareRawRequest adjusts the final HTTP request. Resty allows a single
+// pre-request hook, so it combines the adjustments made at that stage.
+func prepareRawRequest(c *resty.Client, req *http.Request) error {
+	stripCredentials(req)
+	return applyContentLength(c, req)
 }
 
 type ServerInfo struct {
@@ -51,7 +108,7 @@ func (db *Database) CompactDesignDoc(ctx context.Context, designDoc string) erro
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
-		Post("/" + db.name + "/_compact/" + designDoc)
+		Post(db.path("_compact", designDoc))
 
 	if err != nil {
 		return err
@@ -99,7 +156,7 @@ func (db *Database) Changes(ctx context.Context, opts map[string]interface{}) (m
 	var result map[string]interface{}
 	resp, err := req.
 		SetResult(&result).
-		Get("/" + db.name + "/_changes")
+		Get(db.path("_changes"))
 
 	if err != nil {
 		return nil, err
@@ -117,7 +174,7 @@ func (db *Database) Compact(ctx context.Context) error {
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
-		Post("/" + db.name + "/_compact")
+		Post(db.path("_compact"))
 
 	if err != nil {
 		return err
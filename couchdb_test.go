@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -21,7 +20,7 @@ type CouchDBTestSuite struct {
 	client     *Client
 	testDB     *Database
 	useMock    bool
-	mockServer *httptest.Server
+	mockServer *couchdbtest.Server
 }
 
 // SetupSuite runs before all tests
@@ -61,79 +60,12 @@ func (suite *CouchDBTestSuite) TearDownSuite() {
 }
 
 func (suite *CouchDBTestSuite) setupMockServer() {
-	suite.mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		suite.handleMockRequest(w, r)
-	}))
+	suite.mockServer = couchdbtest.NewServer()
+	suite.mockServer.CreateDB("_users")
+	suite.mockServer.CreateDB("_replicator")
 	suite.client = NewClient(suite.mockServer.URL, nil)
 }
 
-func (suite *CouchDBTestSuite) handleMockRequest(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	switch {
-	case r.Method == "GET" && r.URL.Path == "/":
-		err := json.NewEncoder(w).Encode(ServerInfo{
-			CouchDB: "Welcome",
-			Version: "3.2.0",
-			UUID:    "mock-uuid-123",
-		})
-		if err != nil {
-			return
-
-		}
-	case r.Method == "GET" && r.URL.Path == "/_all_dbs":
-		err := json.NewEncoder(w).Encode([]string{"_users", "_replicator", "test-db"})
-		if err != nil {
-			return
-		}
-	case r.Method == "PUT" && r.URL.Path == "/"+suite.testDB.name:
-		w.WriteHeader(http.StatusCreated)
-		err := json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
-		if err != nil {
-			return
-		}
-	case r.Method == "GET" && r.URL.Path == "/"+suite.testDB.name:
-		err := json.NewEncoder(w).Encode(DatabaseInfo{
-			DBName:    suite.testDB.name,
-			DocCount:  0,
-			UpdateSeq: "0",
-		})
-		if err != nil {
-			return
-		}
-	case r.Method == "POST" && r.URL.Path == "/"+suite.testDB.name:
-		err := json.NewEncoder(w).Encode(map[string]interface{}{
-			"ok":  true,
-			"id":  "mock-doc-id",
-			"rev": "1-mock-rev",
-		})
-		if err != nil {
-			return
-		}
-	case r.Method == "GET" && r.URL.Path == "/"+suite.testDB.name+"/test-doc":
-		err := json.NewEncoder(w).Encode(Document{
-			ID:  "test-doc",
-			Rev: "1-mock-rev",
-			Data: map[string]interface{}{
-				"name": "Test Document",
-				"type": "test",
-			},
-		})
-		if err != nil {
-			return
-		}
-	default:
-		w.WriteHeader(http.StatusNotFound)
-		err := json.NewEncoder(w).Encode(map[string]interface{}{
-			"error":  "not_found",
-			"reason": "missing",
-		})
-		if err != nil {
-			return
-		}
-	}
-}
-
 // Test NewClient
 func TestNewClient(t *testing.T) {
 	tests := []struct {
@@ -366,29 +298,19 @@ func (suite *CouchDBTestSuite) TestDatabase_Lifecycle() {
 
 	// Create database
 	err := suite.client.CreateDB(ctx, suite.testDB.name)
-	if !suite.useMock {
-		suite.Require().NoError(err)
-	}
+	suite.Require().NoError(err)
 
 	// Get database info
 	info, err := suite.testDB.Info(ctx)
-	if !suite.useMock {
-		suite.Require().NoError(err)
-		suite.Equal(suite.testDB.name, info.DBName)
-	}
+	suite.Require().NoError(err)
+	suite.Equal(suite.testDB.name, info.DBName)
 }
 
 func (suite *CouchDBTestSuite) TestDocument_CRUD() {
-	if suite.useMock {
-		suite.T().Skip("Skipping CRUD test with mock server")
-	}
-
 	ctx := context.Background()
 
-	err := suite.client.CreateDB(ctx, suite.testDB.name)
-	if err != nil {
-		return
-	}
+	_, err := suite.client.EnsureDB(ctx, suite.testDB.name, nil)
+	suite.Require().NoError(err)
 
 	// Create document
 	testDoc := map[string]interface{}{
@@ -433,15 +355,9 @@ func (suite *CouchDBTestSuite) TestDocument_CRUD() {
 }
 
 func (suite *CouchDBTestSuite) TestDocument_Bulk() {
-	if suite.useMock {
-		suite.T().Skip("Skipping bulk test with mock server")
-	}
-
 	ctx := context.Background()
-	err := suite.client.CreateDB(ctx, suite.testDB.name)
-	if err != nil {
-		return
-	}
+	_, err := suite.client.EnsureDB(ctx, suite.testDB.name, nil)
+	suite.Require().NoError(err)
 
 	docs := []interface{}{
 		map[string]interface{}{
@@ -471,19 +387,15 @@ func (suite *CouchDBTestSuite) TestDocument_Bulk() {
 
 func (suite *CouchDBTestSuite) TestClient_UUID() {
 	uuid, err := suite.client.UUID(context.Background())
-	if !suite.useMock {
-		suite.Require().NoError(err)
-		suite.NotEmpty(uuid)
-	}
+	suite.Require().NoError(err)
+	suite.NotEmpty(uuid)
 
 	// Test multiple UUIDs
 	uuids, err := suite.client.UUIDs(context.Background(), 3)
-	if !suite.useMock {
-		suite.Require().NoError(err)
-		suite.Len(uuids, 3)
-		for _, u := range uuids {
-			suite.NotEmpty(u)
-		}
+	suite.Require().NoError(err)
+	suite.Len(uuids, 3)
+	for _, u := range uuids {
+		suite.NotEmpty(u)
 	}
 }
 
@@ -0,0 +1,267 @@
+package couchdbtest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type database struct {
+	name     string
+	seq      int64
+	docs     map[string]*document
+	local    map[string]map[string]interface{}
+	indexes  map[string][2]string // Mango index definition to design doc ID and name
+	security map[string]interface{}
+}
+
+// document holds every known revision of a document as a tree linked by parent
+type document struct {
+	id   string
+	seq  int64
+	revs map[string]*revision
+}
+
+type revision struct {
+	rev     string
+	gen     int
+	parent  string
+	body    map[string]interface{} // without _id and _rev
+	deleted bool
+	leaf    bool
+}
+
+// updateError is a rejected write, reported like CouchDB does
+type updateError struct {
+	status int
+	kind   string
+	reason string
+}
+
+var (
+	errConflict = &updateError{http.StatusConflict, "conflict", "Document update conflict."}
+	errNoRev    = &updateError{http.StatusBadRequest, "bad_request", "Document rev is required when new_edits is false."}
+	errBadRev   = &updateError{http.StatusBadRequest, "bad_request", "Invalid rev format"}
+)
+
+func newDatabase(name string) *database {
+	return &database{
+		name:     name,
+		docs:     make(map[string]*document),
+		local:    make(map[string]map[string]interface{}),
+		indexes:  make(map[string][2]string),
+		security: map[string]interface{}{},
+	}
+}
+
+func (db *database) info() map[string]interface{} {
+	var count, deleted int64
+	for _, doc := range db.docs {
+		if doc.winner().deleted {
+			deleted++
+		} else {
+			count++
+		}
+	}
+
+	return map[string]interface{}{
+		"db_name":         db.name,
+		"doc_count":       count,
+		"doc_del_count":   deleted,
+		"update_seq":      strconv.FormatInt(db.seq, 10),
+		"purge_seq":       "0",
+		"compact_running": false,
+		"sizes":           map[string]int64{},
+		"props":           map[string]interface{}{},
+	}
+}
+
+// update writes a document revision. With newEdits the body's _rev must be
+// the leaf being updated and a new revision is generated; without, the
+// body's _rev is stored as is, which may create a conflict.
+func (db *database) update(id string, body map[string]interface{}, newEdits bool) (string, *updateError) {
+	prev, _ := body["_rev"].(string)
+	deleted, _ := body["_deleted"].(bool)
+
+	doc, exists := db.docs[id]
+	if !exists {
+		doc = &document{id: id, revs: make(map[string]*revision)}
+	}
+
+	var rev *revision
+	if newEdits {
+		var parent *revision
+		switch {
+		case prev != "":
+			parent = doc.revs[prev]
+			if parent == nil || !parent.leaf || parent.deleted {
+				return "", errConflict
+			}
+		case exists:
+			// Only a deleted document may be recreated without a revision
+			parent = doc.winner()
+			if !parent.deleted {
+				return "", errConflict
+			}
+		}
+
+		rev = &revision{gen: 1, body: stripMeta(body), deleted: deleted, leaf: true}
+		if parent != nil {
+			rev.gen, rev.parent = parent.gen+1, parent.rev
+		}
+		rev.rev = strconv.Itoa(rev.gen) + "-" + digest(rev.parent, rev.body, deleted)
+	} else {
+		if prev == "" {
+			return "", errNoRev
+		}
+		if _, ok := doc.revs[prev]; ok {
+			return prev, nil // already known
+		}
+
+		gen, err := revGen(prev)
+		if err != nil {
+			return "", errBadRev
+		}
+		rev = &revision{rev: prev, gen: gen, body: stripMeta(body), deleted: deleted, leaf: true}
+		rev.parent = parentFromRevisions(body["_revisions"])
+	}
+
+	if parent := doc.revs[rev.parent]; parent != nil {
+		parent.leaf = false
+	}
+	doc.revs[rev.rev] = rev
+
+	db.seq++
+	doc.seq = db.seq
+	db.docs[id] = doc
+
+	return rev.rev, nil
+}
+
+// putLocal writes a _local document, which has no revision history
+func (db *database) putLocal(id string, body map[string]interface{}) (string, *updateError) {
+	gen := 0
+	if current, ok := db.local[id]; ok {
+		rev, _ := current["_rev"].(string)
+		if given, _ := body["_rev"].(string); given != rev {
+			return "", errConflict
+		}
+		gen, _ = revGen(rev)
+	}
+
+	rev := "0-" + strconv.Itoa(gen+1)
+	stored := stripMeta(body)
+	stored["_id"], stored["_rev"] = id, rev
+	db.local[id] = stored
+	return rev, nil
+}
+
+// winner picks the revision CouchDB returns by default: the longest
+// non-deleted branch, with ties broken by the higher revision
+func (doc *document) winner() *revision {
+	var best *revision
+	for _, rev := range doc.revs {
+		if !rev.leaf {
+			continue
+		}
+		if best == nil || wins(rev, best) {
+			best = rev
+		}
+	}
+	return best
+}
+
+func wins(a, b *revision) bool {
+	if a.deleted != b.deleted {
+		return !a.deleted
+	}
+	if a.gen != b.gen {
+		return a.gen > b.gen
+	}
+	return a.rev > b.rev
+}
+
+// conflicts returns the losing non-deleted leaves
+func (doc *document) conflicts() []string {
+	winner := doc.winner()
+
+	var conflicts []string
+	for _, rev := range doc.revs {
+		if rev.leaf && !rev.deleted && rev != winner {
+			conflicts = append(conflicts, rev.rev)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// body returns a revision as CouchDB serves it
+func (doc *document) body(rev *revision, revs, conflicts bool) map[string]interface{} {
+	body := make(map[string]interface{}, len(rev.body)+4)
+	for k, v := range rev.body {
+		body[k] = v
+	}
+	body["_id"] = doc.id
+	body["_rev"] = rev.rev
+	if rev.deleted {
+		body["_deleted"] = true
+	}
+
+	if conflicts {
+		if c := doc.conflicts(); len(c) > 0 {
+			body["_conflicts"] = c
+		}
+	}
+
+	if revs {
+		var ids []string
+		for r := rev; r != nil; r = doc.revs[r.parent] {
+			ids = append(ids, r.rev[strings.IndexByte(r.rev, '-')+1:])
+		}
+		body["_revisions"] = map[string]interface{}{"start": rev.gen, "ids": ids}
+	}
+
+	return body
+}
+
+// stripMeta copies a document body without the fields the simulator manages
+func stripMeta(body map[string]interface{}) map[string]interface{} {
+	stored := make(map[string]interface{}, len(body))
+	for k, v := range body {
+		switch k {
+		case "_id", "_rev", "_deleted", "_revisions", "_conflicts", "_deleted_conflicts", "_revs_info":
+			continue
+		}
+		stored[k] = v
+	}
+	return stored
+}
+
+// digest derives a deterministic revision hash from the parent and content
+func digest(parent string, body map[string]interface{}, deleted bool) string {
+	data, _ := json.Marshal(body) // map keys are sorted
+	sum := md5.Sum([]byte(parent + strconv.FormatBool(deleted) + string(data)))
+	return hex.EncodeToString(sum[:])
+}
+
+func revGen(rev string) (int, error) {
+	gen, _, _ := strings.Cut(rev, "-")
+	return strconv.Atoi(gen)
+}
+
+// parentFromRevisions finds the parent revision in a _revisions field
+func parentFromRevisions(value interface{}) string {
+	revisions, _ := value.(map[string]interface{})
+	start, _ := revisions["start"].(float64)
+	ids, _ := revisions["ids"].([]interface{})
+	if len(ids) < 2 {
+		return ""
+	}
+
+	id, _ := ids[1].(string)
+	return strconv.Itoa(int(start)-1) + "-" + id
+}
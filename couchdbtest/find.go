@@ -0,0 +1,328 @@
+package couchdbtest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type findRequest struct {
+	Selector map[string]interface{} `json:"selector"`
+	Fields   []string               `json:"fields"`
+	Sort     []interface{}          `json:"sort"`
+	Limit    *int                   `json:"limit"`
+	Skip     int                    `json:"skip"`
+	Bookmark string                 `json:"bookmark"`
+}
+
+// serveFind runs a Mango query by scanning every document; indexes are
+// never needed. The bookmark is the position after the last returned match.
+func (s *Server) serveFind(w http.ResponseWriter, r *http.Request, db *database) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w)
+		return
+	}
+
+	var req findRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Selector == nil {
+		writeError(w, http.StatusBadRequest, "missing_required_key", "Missing required key: selector")
+		return
+	}
+
+	var matches []map[string]interface{}
+	for _, doc := range db.docs {
+		winner := doc.winner()
+		if winner.deleted || strings.HasPrefix(doc.id, "_design/") {
+			continue
+		}
+		body := doc.body(winner, false, false)
+		ok, err := matchSelector(body, req.Selector)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_operator", err.Error())
+			return
+		}
+		if ok {
+			matches = append(matches, body)
+		}
+	}
+
+	sortFields, err := parseSort(req.Sort)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		for _, f := range sortFields {
+			a, _ := lookupField(matches[i], f.field)
+			b, _ := lookupField(matches[j], f.field)
+			if c := collate(a, b); c != 0 {
+				return (c < 0) != f.desc
+			}
+		}
+		return matches[i]["_id"].(string) < matches[j]["_id"].(string)
+	})
+
+	start := req.Skip
+	if req.Bookmark != "" && req.Bookmark != "nil" {
+		_, err := fmt.Sscanf(req.Bookmark, "%d", &start)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_bookmark", "Invalid bookmark value")
+			return
+		}
+	}
+	start = min(start, len(matches))
+
+	limit := 25
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	end := min(start+limit, len(matches))
+
+	docs := make([]map[string]interface{}, 0, end-start)
+	for _, doc := range matches[start:end] {
+		docs = append(docs, project(doc, req.Fields))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"docs":     docs,
+		"bookmark": fmt.Sprint(end),
+	})
+}
+
+// serveIndex accepts Mango index definitions. They are remembered only to
+// report whether an index exists, since queries always scan.
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request, db *database) {
+	if r.Method != http.MethodPost {
+		unsupported(w, r)
+		return
+	}
+
+	var req struct {
+		Index map[string]interface{} `json:"index"`
+		DDoc  string                 `json:"ddoc"`
+		Name  string                 `json:"name"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if _, ok := req.Index["fields"].([]interface{}); !ok {
+		writeError(w, http.StatusBadRequest, "bad_request", "Index definition must include fields.")
+		return
+	}
+
+	key := fmt.Sprint(normalize(req.Index))
+	if req.Name == "" {
+		req.Name = newUUID()
+	}
+	if req.DDoc == "" {
+		req.DDoc = newUUID()
+	}
+	id := "_design/" + strings.TrimPrefix(req.DDoc, "_design/")
+
+	result := "created"
+	if existing, ok := db.indexes[key]; ok {
+		result, id, req.Name = "exists", existing[0], existing[1]
+	} else {
+		db.indexes[key] = [2]string{id, req.Name}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"result": result, "id": id, "name": req.Name})
+}
+
+type sortField struct {
+	field string
+	desc  bool
+}
+
+func parseSort(spec []interface{}) ([]sortField, error) {
+	var fields []sortField
+	for _, item := range spec {
+		switch v := item.(type) {
+		case string:
+			fields = append(fields, sortField{field: v})
+		case map[string]interface{}:
+			for field, dir := range v {
+				fields = append(fields, sortField{field: field, desc: dir == "desc"})
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort field: %v", item)
+		}
+	}
+	return fields, nil
+}
+
+// project keeps only the given top-level fields
+func project(doc map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return doc
+	}
+	out := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := lookupField(doc, field); ok {
+			out[field] = v
+		}
+	}
+	return out
+}
+
+// lookupField resolves a dotted field path
+func lookupField(doc map[string]interface{}, field string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// matchSelector evaluates a Mango selector against a document
+func matchSelector(doc map[string]interface{}, selector map[string]interface{}) (bool, error) {
+	for key, cond := range selector {
+		ok, err := matchEntry(doc, "", key, cond)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchEntry evaluates one selector entry below the field path prefix
+func matchEntry(doc map[string]interface{}, prefix, key string, cond interface{}) (bool, error) {
+	switch key {
+	case "$and", "$or", "$nor":
+		list, ok := cond.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s requires an array", key)
+		}
+		matched := 0
+		for _, item := range list {
+			sub, ok := item.(map[string]interface{})
+			if !ok {
+				return false, fmt.Errorf("%s requires an array of selectors", key)
+			}
+			ok, err := matchSelectorAt(doc, prefix, sub)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched++
+			}
+		}
+		switch key {
+		case "$and":
+			return matched == len(list), nil
+		case "$or":
+			return matched > 0, nil
+		default:
+			return matched == 0, nil
+		}
+
+	case "$not":
+		sub, ok := cond.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("$not requires a selector")
+		}
+		ok, err := matchSelectorAt(doc, prefix, sub)
+		return !ok && err == nil, err
+	}
+
+	field := key
+	if prefix != "" {
+		field = prefix + "." + key
+	}
+
+	ops, isObject := cond.(map[string]interface{})
+	if !isObject || len(ops) == 0 {
+		value, exists := lookupField(doc, field)
+		return exists && collate(value, cond) == 0, nil
+	}
+
+	for op, arg := range ops {
+		if !strings.HasPrefix(op, "$") {
+			// A nested object addresses subfields
+			ok, err := matchSelectorAt(doc, field, ops)
+			return ok, err
+		}
+		value, exists := lookupField(doc, field)
+		ok, err := matchOperator(op, value, exists, arg)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchSelectorAt(doc map[string]interface{}, prefix string, selector map[string]interface{}) (bool, error) {
+	for key, cond := range selector {
+		ok, err := matchEntry(doc, prefix, key, cond)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchOperator(op string, value interface{}, exists bool, arg interface{}) (bool, error) {
+	if op == "$exists" {
+		want, _ := arg.(bool)
+		return exists == want, nil
+	}
+	if !exists {
+		return false, nil
+	}
+
+	switch op {
+	case "$eq":
+		return collate(value, arg) == 0, nil
+	case "$ne":
+		return collate(value, arg) != 0, nil
+	case "$gt":
+		return collate(value, arg) > 0, nil
+	case "$gte":
+		return collate(value, arg) >= 0, nil
+	case "$lt":
+		return collate(value, arg) < 0, nil
+	case "$lte":
+		return collate(value, arg) <= 0, nil
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s requires an array", op)
+		}
+		found := false
+		for _, item := range list {
+			if collate(value, item) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in"), nil
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("$regex requires a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		str, ok := value.(string)
+		return ok && re.MatchString(str), nil
+	case "$size":
+		list, ok := value.([]interface{})
+		n, _ := arg.(float64)
+		return ok && len(list) == int(n), nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %s", op)
+}
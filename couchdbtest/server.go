@@ -0,0 +1,498 @@
+// Package couchdbtest provides an in-memory CouchDB simulator for unit tests
+// of code built on the couchdb client, so a data layer can be tested
+// without a running server.
+//
+//	server := couchdbtest.NewServer()
+//	defer server.Close()
+//	client := couchdb.NewClient(server.URL, nil)
+//
+// The simulator covers the core document API: databases, document CRUD
+// with revision checks, _bulk_docs (including new_edits=false, which can be
+// used to create conflicts), _all_docs, _local documents, _uuids, views
+// whose map functions are written in Go and registered with AddView, and
+// Mango queries, which scan all documents; created indexes are accepted but
+// not used. Security objects are stored but not enforced. Design documents
+// are stored but their JavaScript is never run.
+// Authentication, attachments as separate resources and the changes feed
+// are not simulated; such requests fail with 400 bad_request.
+package couchdbtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory CouchDB served over HTTP
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	dbs   map[string]*database
+	views map[string]View // keyed by "designDoc/view"
+}
+
+// NewServer starts a simulator with no databases. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		dbs:   make(map[string]*database),
+		views: make(map[string]View),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// CreateDB creates a database directly, e.g. to seed a test. It does
+// nothing if the database exists.
+func (s *Server) CreateDB(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.dbs[name]; !ok {
+		s.dbs[name] = newDatabase(name)
+	}
+}
+
+// Reset deletes all databases, keeping the registered views
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dbs = make(map[string]*database)
+}
+
+// validDBName matches the names CouchDB accepts for databases
+var validDBName = regexp.MustCompile(`^[a-z][a-z0-9_$()+/-]*$`)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments, err := splitPath(r.URL.EscapedPath())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	if len(segments) == 0 {
+		s.serveRoot(w, r)
+		return
+	}
+
+	if strings.HasPrefix(segments[0], "_") {
+		s.serveServerEndpoint(w, r, segments[0])
+		return
+	}
+
+	if len(segments) == 1 {
+		s.serveDatabase(w, r, segments[0])
+		return
+	}
+
+	db, ok := s.dbs[segments[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+		return
+	}
+
+	s.serveInDatabase(w, r, db, segments[1:])
+}
+
+func (s *Server) serveRoot(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"couchdb": "Welcome",
+			"version": "3.3.3",
+			"uuid":    "couchdbtest",
+			"vendor":  map[string]string{"name": "couchdbtest"},
+		})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveServerEndpoint(w http.ResponseWriter, r *http.Request, endpoint string) {
+	switch endpoint {
+	case "_up":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "seeds": map[string]interface{}{}})
+
+	case "_all_dbs":
+		names := make([]string, 0, len(s.dbs))
+		for name := range s.dbs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeJSON(w, http.StatusOK, names)
+
+	case "_uuids":
+		count := 1
+		if c := r.URL.Query().Get("count"); c != "" {
+			n, err := strconv.Atoi(c)
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, "bad_request", "count must be a positive integer")
+				return
+			}
+			count = n
+		}
+
+		uuids := make([]string, count)
+		for i := range uuids {
+			uuids[i] = newUUID()
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"uuids": uuids})
+
+	default:
+		unsupported(w, r)
+	}
+}
+
+func (s *Server) serveDatabase(w http.ResponseWriter, r *http.Request, name string) {
+	db, exists := s.dbs[name]
+
+	switch r.Method {
+	case http.MethodPut:
+		if !validDBName.MatchString(name) {
+			writeError(w, http.StatusBadRequest, "illegal_database_name", "Name: '"+name+"'. Only lowercase characters (a-z), digits (0-9), and any of the characters _, $, (, ), +, -, and / are allowed. Must begin with a letter.")
+			return
+		}
+		if exists {
+			writeError(w, http.StatusPreconditionFailed, "file_exists", "The database could not be created, the file already exists.")
+			return
+		}
+		s.dbs[name] = newDatabase(name)
+		writeJSON(w, http.StatusCreated, map[string]bool{"ok": true})
+
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+			return
+		}
+		delete(s.dbs, name)
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+			return
+		}
+		writeJSON(w, http.StatusOK, db.info())
+
+	case http.MethodPost:
+		if !exists {
+			writeError(w, http.StatusNotFound, "not_found", "Database does not exist.")
+			return
+		}
+
+		var body map[string]interface{}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		id, _ := body["_id"].(string)
+		if id == "" {
+			id = newUUID()
+		}
+		s.writeUpdate(w, db, id, body, r.URL.Query().Get("new_edits") != "false")
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) serveInDatabase(w http.ResponseWriter, r *http.Request, db *database, segments []string) {
+	switch segments[0] {
+	case "_all_docs":
+		params, ok := readQuery(w, r)
+		if !ok {
+			return
+		}
+		result, err := db.allDocs(params)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "query_parse_error", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+
+	case "_find":
+		s.serveFind(w, r, db)
+
+	case "_index":
+		s.serveIndex(w, r, db)
+
+	case "_security":
+		s.serveSecurity(w, r, db)
+
+	case "_bulk_docs":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w)
+			return
+		}
+		s.serveBulkDocs(w, r, db)
+
+	case "_design":
+		if len(segments) < 2 {
+			unsupported(w, r)
+			return
+		}
+		if len(segments) == 4 && segments[2] == "_view" {
+			s.serveView(w, r, db, segments[1], segments[3])
+			return
+		}
+		if len(segments) != 2 {
+			unsupported(w, r)
+			return
+		}
+		s.serveDocument(w, r, db, "_design/"+segments[1])
+
+	case "_local":
+		if len(segments) != 2 {
+			unsupported(w, r)
+			return
+		}
+		s.serveLocal(w, r, db, "_local/"+segments[1])
+
+	default:
+		if strings.HasPrefix(segments[0], "_") || len(segments) != 1 {
+			unsupported(w, r)
+			return
+		}
+		s.serveDocument(w, r, db, segments[0])
+	}
+}
+
+func (s *Server) serveDocument(w http.ResponseWriter, r *http.Request, db *database, id string) {
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		doc, ok := db.docs[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not_found", "missing")
+			return
+		}
+
+		rev := doc.winner()
+		if want := query.Get("rev"); want != "" {
+			if rev = doc.revs[want]; rev == nil {
+				writeError(w, http.StatusNotFound, "not_found", "missing")
+				return
+			}
+		} else if rev.deleted {
+			writeError(w, http.StatusNotFound, "not_found", "deleted")
+			return
+		}
+
+		body := doc.body(rev, query.Get("revs") == "true", query.Get("conflicts") == "true")
+		w.Header().Set("ETag", `"`+rev.rev+`"`)
+		writeJSON(w, http.StatusOK, body)
+
+	case http.MethodPut:
+		var body map[string]interface{}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		if _, ok := body["_rev"]; !ok {
+			if rev := revFromRequest(r); rev != "" {
+				body["_rev"] = rev
+			}
+		}
+		s.writeUpdate(w, db, id, body, query.Get("new_edits") != "false")
+
+	case http.MethodDelete:
+		rev := revFromRequest(r)
+		if doc, ok := db.docs[id]; !ok || (rev == "" && doc.winner().deleted) {
+			writeError(w, http.StatusNotFound, "not_found", "missing")
+			return
+		}
+		s.writeUpdate(w, db, id, map[string]interface{}{"_rev": rev, "_deleted": true}, true)
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// writeUpdate applies a single document write and sends CouchDB's response
+func (s *Server) writeUpdate(w http.ResponseWriter, db *database, id string, body map[string]interface{}, newEdits bool) {
+	rev, err := db.update(id, body, newEdits)
+	if err != nil {
+		writeError(w, err.status, err.kind, err.reason)
+		return
+	}
+
+	w.Header().Set("ETag", `"`+rev+`"`)
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"ok": true, "id": id, "rev": rev})
+}
+
+func (s *Server) serveBulkDocs(w http.ResponseWriter, r *http.Request, db *database) {
+	var request struct {
+		Docs     []map[string]interface{} `json:"docs"`
+		NewEdits *bool                    `json:"new_edits"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	newEdits := request.NewEdits == nil || *request.NewEdits
+
+	results := make([]map[string]interface{}, 0, len(request.Docs))
+	for _, doc := range request.Docs {
+		id, _ := doc["_id"].(string)
+		if id == "" {
+			id = newUUID()
+		}
+
+		rev, err := db.update(id, doc, newEdits)
+		switch {
+		case err != nil:
+			results = append(results, map[string]interface{}{"id": id, "error": err.kind, "reason": err.reason})
+		case newEdits:
+			results = append(results, map[string]interface{}{"ok": true, "id": id, "rev": rev})
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, results)
+}
+
+func (s *Server) serveLocal(w http.ResponseWriter, r *http.Request, db *database, id string) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		doc, ok := db.local[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not_found", "missing")
+			return
+		}
+		writeJSON(w, http.StatusOK, doc)
+
+	case http.MethodPut:
+		var body map[string]interface{}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		rev, err := db.putLocal(id, body)
+		if err != nil {
+			writeError(w, err.status, err.kind, err.reason)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"ok": true, "id": id, "rev": rev})
+
+	case http.MethodDelete:
+		if _, ok := db.local[id]; !ok {
+			writeError(w, http.StatusNotFound, "not_found", "missing")
+			return
+		}
+		delete(db.local, id)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "id": id, "rev": "0-0"})
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveSecurity stores the security object; it is not enforced
+func (s *Server) serveSecurity(w http.ResponseWriter, r *http.Request, db *database) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, db.security)
+
+	case http.MethodPut:
+		var body map[string]interface{}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		db.security = body
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true})
+
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// revFromRequest returns the revision from the rev parameter or If-Match header
+func revFromRequest(r *http.Request) string {
+	if rev := r.URL.Query().Get("rev"); rev != "" {
+		return rev
+	}
+	return strings.Trim(r.Header.Get("If-Match"), `"`)
+}
+
+// splitPath splits an escaped URL path into unescaped segments
+func splitPath(path string) ([]string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, nil
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+// readQuery collects the view parameters from the query string and, for
+// POST requests, the JSON body
+func readQuery(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	params := make(map[string]interface{})
+	for name, values := range r.URL.Query() {
+		var value interface{}
+		if err := json.Unmarshal([]byte(values[0]), &value); err != nil {
+			value = values[0] // plain strings such as stale=ok
+		}
+		params[name] = value
+	}
+
+	if r.Method == http.MethodPost {
+		var body map[string]interface{}
+		if !readJSON(w, r, &body) {
+			return nil, false
+		}
+		for name, value := range body {
+			params[name] = value
+		}
+	}
+
+	return params, true
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, dest interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid UTF-8 JSON")
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, kind, reason string) {
+	writeJSON(w, status, map[string]string{"error": kind, "reason": reason})
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed.")
+}
+
+func unsupported(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusBadRequest, "bad_request", "couchdbtest does not support "+r.Method+" "+r.URL.Path)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
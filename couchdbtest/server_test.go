@@ -0,0 +1,263 @@
+package couchdbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) (*Server, *couchdb.Client) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	return server, couchdb.NewClient(server.URL, nil)
+}
+
+func TestDatabases(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, client.CreateDB(ctx, "users"))
+	assert.ErrorIs(t, client.CreateDB(ctx, "users"), couchdb.ErrPreconditionFailed)
+	assert.Error(t, client.CreateDB(ctx, "Users"))
+
+	dbs, err := client.AllDbs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users"}, dbs)
+
+	created, err := client.EnsureDB(ctx, "users", nil)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	require.NoError(t, client.DeleteDB(ctx, "users"))
+	_, err = client.DB("users").Info(ctx)
+	assert.True(t, couchdb.IsNotFound(err))
+
+	uuids, err := client.UUIDs(ctx, 3)
+	require.NoError(t, err)
+	assert.Len(t, uuids, 3)
+
+	require.NoError(t, client.WaitUntilReady(ctx, time.Millisecond))
+}
+
+func TestDocuments(t *testing.T) {
+	server, client := newClient(t)
+	server.CreateDB("db")
+	db := client.DB("db")
+	ctx := context.Background()
+
+	created, err := db.Put(ctx, map[string]interface{}{"_id": "a", "name": "Ann"})
+	require.NoError(t, err)
+	assert.Equal(t, "a", created.ID)
+	assert.Regexp(t, `^1-`, created.Rev)
+
+	_, err = db.Put(ctx, map[string]interface{}{"_id": "a", "name": "Other"})
+	assert.True(t, couchdb.IsConflict(err))
+
+	updated, err := db.Update(ctx, "a", map[string]interface{}{"_rev": created.Rev, "name": "Anne"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^2-`, updated.Rev)
+
+	_, err = db.Update(ctx, "a", map[string]interface{}{"_rev": created.Rev, "name": "stale"})
+	assert.True(t, couchdb.IsConflict(err))
+
+	doc, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, updated.Rev, doc.Rev)
+	assert.Equal(t, "Anne", doc.Data["name"])
+
+	old, err := db.GetWithOptions(ctx, "a", &couchdb.GetOptions{Rev: created.Rev, Revs: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Ann", old.Data["name"])
+
+	generated, err := db.Put(ctx, map[string]interface{}{"name": "no id"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, generated.ID)
+
+	require.NoError(t, db.Delete(ctx, "a", updated.Rev))
+	_, err = db.Get(ctx, "a")
+	var couchErr *couchdb.Error
+	require.True(t, errors.As(err, &couchErr))
+	assert.Equal(t, "deleted", couchErr.Reason)
+
+	// A deleted document can be recreated without a revision
+	recreated, err := db.Put(ctx, map[string]interface{}{"_id": "a", "name": "Again"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^4-`, recreated.Rev)
+
+	info, err := db.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), info.DocCount)
+}
+
+func TestConflicts(t *testing.T) {
+	server, client := newClient(t)
+	server.CreateDB("db")
+	db := client.DB("db")
+	ctx := context.Background()
+
+	first, err := db.Put(ctx, map[string]interface{}{"_id": "doc", "v": 1})
+	require.NoError(t, err)
+
+	// Two replicas updated the same revision
+	newEdits := false
+	_, err = db.BulkWithOptions(ctx, []interface{}{
+		map[string]interface{}{"_id": "doc", "_rev": "2-aaa", "v": 2, "_revisions": map[string]interface{}{"start": 2, "ids": []string{"aaa", first.Rev[2:]}}},
+		map[string]interface{}{"_id": "doc", "_rev": "2-bbb", "v": 3, "_revisions": map[string]interface{}{"start": 2, "ids": []string{"bbb", first.Rev[2:]}}},
+	}, &couchdb.BulkOptions{NewEdits: &newEdits})
+	require.NoError(t, err)
+
+	doc, err := db.GetWithOptions(ctx, "doc", &couchdb.GetOptions{Conflicts: true})
+	require.NoError(t, err)
+	assert.Equal(t, "2-bbb", doc.Rev)
+	assert.Equal(t, []string{"2-aaa"}, doc.Conflicts)
+
+	require.NoError(t, db.Delete(ctx, "doc", "2-aaa"))
+	doc, err = db.GetWithOptions(ctx, "doc", &couchdb.GetOptions{Conflicts: true})
+	require.NoError(t, err)
+	assert.Empty(t, doc.Conflicts)
+}
+
+func TestAllDocsAndViews(t *testing.T) {
+	server, client := newClient(t)
+	server.CreateDB("db")
+	server.AddView("app", "by_type", View{
+		Map: func(doc map[string]interface{}, emit func(key, value interface{})) {
+			if t, ok := doc["type"]; ok {
+				emit(t, doc["n"])
+			}
+		},
+		Reduce: "_sum",
+	})
+
+	db := client.DB("db")
+	ctx := context.Background()
+
+	_, err := db.Bulk(ctx, []interface{}{
+		map[string]interface{}{"_id": "c", "type": "user", "n": 1},
+		map[string]interface{}{"_id": "a", "type": "post", "n": 2},
+		map[string]interface{}{"_id": "b", "type": "user", "n": 3},
+	})
+	require.NoError(t, err)
+
+	all, err := db.AllDocs(ctx, &couchdb.ViewOptions{IncludeDocs: true, StartKey: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), all.TotalRows)
+	require.Len(t, all.Rows, 2)
+	assert.Equal(t, "b", all.Rows[0].ID)
+	assert.Equal(t, "user", all.Rows[0].Doc.Data["type"])
+
+	byKeys, err := db.AllDocsByKeys(ctx, []string{"a", "missing"}, nil)
+	require.NoError(t, err)
+	require.Len(t, byKeys.Rows, 2)
+	assert.Equal(t, "not_found", byKeys.Rows[1].Error)
+
+	reduce := false
+	users, err := db.View(ctx, "app", "by_type", &couchdb.ViewOptions{Key: "user", Reduce: &reduce, IncludeDocs: true})
+	require.NoError(t, err)
+	require.Len(t, users.Rows, 2)
+	assert.Equal(t, "b", users.Rows[0].ID)
+	assert.NotNil(t, users.Rows[0].Doc)
+
+	grouped, err := db.View(ctx, "app", "by_type", &couchdb.ViewOptions{Group: true})
+	require.NoError(t, err)
+	require.Len(t, grouped.Rows, 2)
+	assert.Equal(t, "post", grouped.Rows[0].Key)
+	assert.Equal(t, float64(4), grouped.Rows[1].Value)
+
+	desc, err := db.View(ctx, "app", "by_type", &couchdb.ViewOptions{Reduce: &reduce, Descending: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, desc.Rows, 1)
+	assert.Equal(t, "c", desc.Rows[0].ID)
+
+	_, err = db.View(ctx, "app", "missing", nil)
+	assert.True(t, couchdb.IsNotFound(err))
+}
+
+func TestFind(t *testing.T) {
+	server, client := newClient(t)
+	server.CreateDB("db")
+	db := client.DB("db")
+	ctx := context.Background()
+
+	_, err := db.Bulk(ctx, []interface{}{
+		map[string]interface{}{"_id": "a", "type": "user", "age": 30, "address": map[string]interface{}{"city": "Oslo"}},
+		map[string]interface{}{"_id": "b", "type": "user", "age": 20, "tags": []string{"x", "y"}},
+		map[string]interface{}{"_id": "c", "type": "post", "age": 40},
+		map[string]interface{}{"_id": "_design/app"},
+	})
+	require.NoError(t, err)
+
+	ids := func(query *couchdb.FindQuery) []string {
+		t.Helper()
+		result, err := db.Find(ctx, query)
+		require.NoError(t, err)
+		var ids []string
+		for _, doc := range result.Docs {
+			ids = append(ids, doc.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids(&couchdb.FindQuery{}))
+	assert.Equal(t, []string{"a", "b"}, ids(&couchdb.FindQuery{Selector: couchdb.Eq("type", "user")}))
+	assert.Equal(t, []string{"b", "a"}, ids(&couchdb.FindQuery{
+		Selector: couchdb.And(couchdb.Eq("type", "user"), couchdb.Gte("age", 20)),
+		Sort:     []couchdb.SortField{{Field: "age"}},
+	}))
+	assert.Equal(t, []string{"a"}, ids(&couchdb.FindQuery{Selector: couchdb.Selector{"address": map[string]interface{}{"city": "Oslo"}}}))
+	assert.Equal(t, []string{"a", "c"}, ids(&couchdb.FindQuery{Selector: couchdb.Not(couchdb.Exists("tags", true))}))
+	assert.Equal(t, []string{"b"}, ids(&couchdb.FindQuery{Selector: couchdb.Or(couchdb.In("age", 20, 21), couchdb.Eq("address.city", "Bergen"))}))
+
+	page, err := db.Find(ctx, &couchdb.FindQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Docs, 2)
+	assert.Equal(t, []string{"c"}, ids(&couchdb.FindQuery{Limit: 2, Bookmark: page.Bookmark}))
+
+	_, err = db.Find(ctx, &couchdb.FindQuery{Selector: couchdb.Selector{"age": map[string]interface{}{"$near": 1}}})
+	assert.ErrorIs(t, err, couchdb.ErrBadRequest)
+
+	index := &couchdb.MangoIndex{Fields: []string{"type"}, DesignDoc: "app", Name: "by-type"}
+	created, err := db.CreateIndex(ctx, index)
+	require.NoError(t, err)
+	assert.Equal(t, &couchdb.CreateIndexResult{Result: "created", ID: "_design/app", Name: "by-type"}, created)
+	created, err = db.CreateIndex(ctx, index)
+	require.NoError(t, err)
+	assert.Equal(t, "exists", created.Result)
+}
+
+func TestLocalDocuments(t *testing.T) {
+	server, client := newClient(t)
+	server.CreateDB("db")
+	db := client.DB("db")
+	ctx := context.Background()
+
+	store := couchdb.NewLocalCheckpointStore(db)
+	require.NoError(t, store.Save(ctx, "feed", "42"))
+	require.NoError(t, store.Save(ctx, "feed", "43"))
+
+	seq, err := store.Load(ctx, "feed")
+	require.NoError(t, err)
+	assert.Equal(t, "43", seq)
+
+	all, err := db.AllDocs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, all.Rows)
+}
+
+func TestCollate(t *testing.T) {
+	ordered := []interface{}{
+		nil, false, true, float64(1), float64(2), "a", "b",
+		[]interface{}{"a"}, []interface{}{"a", float64(1)}, []interface{}{"b"},
+		map[string]interface{}{"a": float64(1)},
+	}
+	for i := 1; i < len(ordered); i++ {
+		assert.Negative(t, collate(ordered[i-1], ordered[i]), "%v < %v", ordered[i-1], ordered[i])
+		assert.Positive(t, collate(ordered[i], ordered[i-1]), "%v > %v", ordered[i], ordered[i-1])
+	}
+}
@@ -0,0 +1,460 @@
+package couchdbtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// MapFunc is a view map function. It is called with each document,
+// including _id and _rev, and may call emit any number of times.
+type MapFunc func(doc map[string]interface{}, emit func(key, value interface{}))
+
+// View is a view implemented in Go, standing in for the JavaScript view of
+// a design document
+type View struct {
+	Map MapFunc
+
+	// Reduce is one of the built-in reduce functions "_count", "_sum" or
+	// "_stats", or empty for a map-only view
+	Reduce string
+}
+
+// AddView registers a view served at _design/{designDoc}/_view/{name} in
+// every database. The design document itself does not have to exist.
+func (s *Server) AddView(designDoc, name string, view View) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.views[designDoc+"/"+name] = view
+}
+
+type viewRow struct {
+	ID    string      `json:"id,omitempty"`
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+	Doc   interface{} `json:"doc,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+type viewResult struct {
+	TotalRows *int      `json:"total_rows,omitempty"`
+	Offset    *int      `json:"offset,omitempty"`
+	Rows      []viewRow `json:"rows"`
+	UpdateSeq string    `json:"update_seq,omitempty"`
+}
+
+func (s *Server) serveView(w http.ResponseWriter, r *http.Request, db *database, designDoc, name string) {
+	view, ok := s.views[designDoc+"/"+name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "missing_named_view")
+		return
+	}
+
+	params, ok := readQuery(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := db.queryView(view, params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "query_parse_error", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (db *database) queryView(view View, params map[string]interface{}) (*viewResult, error) {
+	var rows []viewRow
+	for _, doc := range db.docs {
+		winner := doc.winner()
+		if winner.deleted || strings.HasPrefix(doc.id, "_design/") {
+			continue
+		}
+
+		body := doc.body(winner, false, false)
+		view.Map(body, func(key, value interface{}) {
+			rows = append(rows, viewRow{ID: doc.id, Key: normalize(key), Value: normalize(value)})
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if c := collate(rows[i].Key, rows[j].Key); c != 0 {
+			return c < 0
+		}
+		return rows[i].ID < rows[j].ID
+	})
+
+	reduce := view.Reduce != "" && params["reduce"] != false
+	if reduce && params["include_docs"] == true {
+		return nil, errors.New("`include_docs` is invalid for reduce")
+	}
+
+	rows, offset := selectRows(rows, params, collate)
+
+	result := &viewResult{}
+	if params["update_seq"] == true {
+		result.UpdateSeq = fmt.Sprint(db.seq)
+	}
+
+	if reduce {
+		reduced, err := reduceRows(rows, view.Reduce, groupLevel(params))
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = page(reduced, params)
+		return result, nil
+	}
+
+	total := 0
+	for _, doc := range db.docs {
+		if w := doc.winner(); !w.deleted && !strings.HasPrefix(doc.id, "_design/") {
+			total++
+		}
+	}
+	result.TotalRows = &total
+	result.Rows = page(rows, params)
+	if params["include_docs"] == true {
+		for i, row := range result.Rows {
+			doc := db.docs[row.ID]
+			result.Rows[i].Doc = doc.body(doc.winner(), false, false)
+		}
+	}
+	skipped := offset + min(intParam(params, "skip"), len(rows))
+	result.Offset = &skipped
+	return result, nil
+}
+
+func (db *database) allDocs(params map[string]interface{}) (*viewResult, error) {
+	includeDocs := params["include_docs"] == true
+
+	var rows []viewRow
+	if keys, ok := params["keys"].([]interface{}); ok {
+		// Keys are returned in the requested order, deleted and missing ones included
+		for _, k := range keys {
+			id, _ := k.(string)
+			doc, exists := db.docs[id]
+			if !exists {
+				rows = append(rows, viewRow{Key: k, Error: "not_found"})
+				continue
+			}
+
+			winner := doc.winner()
+			value := map[string]interface{}{"rev": winner.rev}
+			row := viewRow{ID: id, Key: id, Value: value}
+			if winner.deleted {
+				value["deleted"] = true
+			} else if includeDocs {
+				row.Doc = doc.body(winner, false, params["conflicts"] == true)
+			}
+			rows = append(rows, row)
+		}
+
+		total := db.liveDocs()
+		offset := 0
+		return &viewResult{TotalRows: &total, Offset: &offset, Rows: page(rows, params)}, nil
+	}
+
+	for _, doc := range db.docs {
+		winner := doc.winner()
+		if winner.deleted {
+			continue
+		}
+
+		row := viewRow{ID: doc.id, Key: doc.id, Value: map[string]interface{}{"rev": winner.rev}}
+		if includeDocs {
+			row.Doc = doc.body(winner, false, params["conflicts"] == true)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	// _all_docs sorts by raw document ID
+	rawCollate := func(a, b interface{}) int {
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+	rows, offset := selectRows(rows, params, rawCollate)
+
+	total := db.liveDocs()
+	skipped := offset + min(intParam(params, "skip"), len(rows))
+	result := &viewResult{TotalRows: &total, Offset: &skipped, Rows: page(rows, params)}
+	if params["update_seq"] == true {
+		result.UpdateSeq = fmt.Sprint(db.seq)
+	}
+	return result, nil
+}
+
+func (db *database) liveDocs() int {
+	n := 0
+	for _, doc := range db.docs {
+		if !doc.winner().deleted {
+			n++
+		}
+	}
+	return n
+}
+
+// selectRows applies key, keys, startkey, endkey and descending to rows
+// sorted in ascending order. It returns the selected rows and how many rows
+// precede them.
+func selectRows(rows []viewRow, params map[string]interface{}, compare func(a, b interface{}) int) ([]viewRow, int) {
+	if keys, ok := params["keys"].([]interface{}); ok {
+		var selected []viewRow
+		for _, key := range keys {
+			for _, row := range rows {
+				if compare(row.Key, key) == 0 {
+					selected = append(selected, row)
+				}
+			}
+		}
+		return selected, 0
+	}
+
+	descending := params["descending"] == true
+	if descending {
+		reversed := make([]viewRow, len(rows))
+		for i, row := range rows {
+			reversed[len(rows)-1-i] = row
+		}
+		rows = reversed
+	}
+
+	// In descending order the start key is the upper bound
+	order := func(a, b interface{}) int {
+		if descending {
+			return -compare(a, b)
+		}
+		return compare(a, b)
+	}
+
+	startKey, hasStart := param(params, "startkey", "start_key")
+	endKey, hasEnd := param(params, "endkey", "end_key")
+	if key, ok := params["key"]; ok {
+		startKey, endKey, hasStart, hasEnd = key, key, true, true
+	}
+	startDocID, _ := params["startkey_docid"].(string)
+	endDocID, _ := params["endkey_docid"].(string)
+	inclusiveEnd := params["inclusive_end"] != false
+
+	var selected []viewRow
+	offset := 0
+	for _, row := range rows {
+		if hasStart {
+			c := order(row.Key, startKey)
+			if c < 0 || (c == 0 && startDocID != "" && row.ID < startDocID) {
+				offset++
+				continue
+			}
+		}
+		if hasEnd {
+			c := order(row.Key, endKey)
+			if c > 0 || (c == 0 && !inclusiveEnd) || (c == 0 && endDocID != "" && row.ID > endDocID) {
+				continue
+			}
+		}
+		selected = append(selected, row)
+	}
+
+	return selected, offset
+}
+
+// page applies skip and limit
+func page(rows []viewRow, params map[string]interface{}) []viewRow {
+	skip := min(intParam(params, "skip"), len(rows))
+	rows = rows[skip:]
+
+	if limit, ok := params["limit"].(float64); ok && int(limit) < len(rows) {
+		rows = rows[:int(limit)]
+	}
+	if rows == nil {
+		rows = []viewRow{}
+	}
+	return rows
+}
+
+// groupLevel returns how many leading array elements of the key to group
+// by: -1 to group by the whole key, 0 to reduce everything into one row
+func groupLevel(params map[string]interface{}) int {
+	if level, ok := params["group_level"].(float64); ok {
+		return int(level)
+	}
+	if params["group"] == true {
+		return -1
+	}
+	return 0
+}
+
+func reduceRows(rows []viewRow, reduce string, level int) ([]viewRow, error) {
+	var groups []viewRow
+	var values [][]interface{}
+
+	for _, row := range rows {
+		key := groupKey(row.Key, level)
+		if n := len(groups); n > 0 && collate(groups[n-1].Key, key) == 0 {
+			values[n-1] = append(values[n-1], row.Value)
+			continue
+		}
+		groups = append(groups, viewRow{Key: key})
+		values = append(values, []interface{}{row.Value})
+	}
+
+	for i := range groups {
+		value, err := builtinReduce(reduce, values[i])
+		if err != nil {
+			return nil, err
+		}
+		groups[i].Value = value
+	}
+	return groups, nil
+}
+
+func groupKey(key interface{}, level int) interface{} {
+	switch {
+	case level == 0:
+		return nil
+	case level < 0:
+		return key
+	}
+
+	if array, ok := key.([]interface{}); ok && len(array) > level {
+		return array[:level]
+	}
+	return key
+}
+
+func builtinReduce(reduce string, values []interface{}) (interface{}, error) {
+	switch reduce {
+	case "_count":
+		return len(values), nil
+
+	case "_sum", "_stats":
+		stats := map[string]float64{"count": 0, "sumsqr": 0, "sum": 0}
+		for i, v := range values {
+			n, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s can only reduce numbers", reduce)
+			}
+			stats["sum"] += n
+			stats["sumsqr"] += n * n
+			stats["count"]++
+			if i == 0 || n < stats["min"] {
+				stats["min"] = n
+			}
+			if i == 0 || n > stats["max"] {
+				stats["max"] = n
+			}
+		}
+		if reduce == "_sum" {
+			return stats["sum"], nil
+		}
+		return stats, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported reduce function %q", reduce)
+	}
+}
+
+// collate compares two JSON values in CouchDB view order: null, false,
+// true, numbers, strings, arrays, objects. Booleans are ranked by value. Strings are compared by code
+// point rather than with ICU collation.
+func collate(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch a := a.(type) {
+	case float64:
+		switch b := b.(float64); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+
+	case string:
+		return strings.Compare(a, b.(string))
+
+	case []interface{}:
+		b := b.([]interface{})
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := collate(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(a) - len(b)
+
+	case map[string]interface{}:
+		b := b.(map[string]interface{})
+		ka, kb := sortedKeys(a), sortedKeys(b)
+		for i := 0; i < len(ka) && i < len(kb); i++ {
+			if c := strings.Compare(ka[i], kb[i]); c != 0 {
+				return c
+			}
+			if c := collate(a[ka[i]], b[kb[i]]); c != 0 {
+				return c
+			}
+		}
+		return len(ka) - len(kb)
+	}
+
+	return 0
+}
+
+func typeRank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalize converts an emitted Go value into its decoded JSON form
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+func param(params map[string]interface{}, names ...string) (interface{}, bool) {
+	for _, name := range names {
+		if v, ok := params[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func intParam(params map[string]interface{}, name string) int {
+	n, _ := params[name].(float64)
+	return int(n)
+}
@@ -0,0 +1,219 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// CRDT design document and views
+const (
+	CRDTDesignDoc   = "crdt"
+	CRDTCounterView = "counters"
+	CRDTSetView     = "sets"
+)
+
+const (
+	crdtCounterType = "crdt_counter"
+	crdtSetType     = "crdt_set"
+
+	crdtCounterMap = `function(doc) { if (doc.type === "crdt_counter") { emit(doc.counter, doc.value); } }`
+	crdtSetMap     = `function(doc) { if (doc.type === "crdt_set") { emit([doc.set, doc.element], doc.op === "remove" ? [0, 1] : [1, 0]); } }`
+
+	crdtMaxRetries = 5
+)
+
+// CRDTDesignDocument returns the design document backing counters and sets
+func CRDTDesignDocument() *DesignDocument {
+	return &DesignDocument{
+		ID:       "_design/" + CRDTDesignDoc,
+		Language: "javascript",
+		Views: map[string]*View{
+			CRDTCounterView: {Map: crdtCounterMap, Reduce: "_sum"},
+			CRDTSetView:     {Map: crdtSetMap, Reduce: "_sum"},
+		},
+	}
+}
+
+// EnsureCRDTViews installs the views used by counters and sets
+func (db *Database) EnsureCRDTViews(ctx context.Context) error {
+	for name, view := range CRDTDesignDocument().Views {
+		if err := db.ensureView(ctx, CRDTDesignDoc, name, view); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter is a grow/shrink counter where every client writes only its own
+// sub-document, so concurrent writers on different replicas never conflict.
+// The total is the sum of all sub-documents, computed by a _sum reduce.
+type Counter struct {
+	db       *Database
+	name     string
+	clientID string
+}
+
+// Counter returns a handle to the named counter for the given client
+func (db *Database) Counter(name, clientID string) *Counter {
+	return &Counter{
+		db:       db,
+		name:     name,
+		clientID: clientID,
+	}
+}
+
+func (c *Counter) docID() string {
+	return crdtCounterType + ":" + c.name + ":" + c.clientID
+}
+
+// Increment adds delta (which may be negative) to this client's contribution
+func (c *Counter) Increment(ctx context.Context, delta int64) error {
+	for attempt := 0; attempt < crdtMaxRetries; attempt++ {
+		doc, err := c.db.Get(ctx, c.docID())
+		if err != nil {
+			if !isStatus(err, 404) {
+				return err
+			}
+			doc = &Document{ID: c.docID(), Data: map[string]interface{}{}}
+		}
+
+		value, _ := doc.Data["value"].(float64)
+		doc.Data["type"] = crdtCounterType
+		doc.Data["counter"] = c.name
+		doc.Data["client"] = c.clientID
+		doc.Data["value"] = int64(value) + delta
+
+		_, err = c.db.Update(ctx, doc.ID, doc)
+		if err == nil {
+			return nil
+		}
+		if !isStatus(err, 409) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("counter %s: too many conflicts while incrementing", c.name)
+}
+
+// Value returns the counter total across all clients
+func (c *Counter) Value(ctx context.Context) (int64, error) {
+	reduce := true
+	result, err := c.db.View(ctx, CRDTDesignDoc, CRDTCounterView, &ViewOptions{
+		Key:    c.name,
+		Reduce: &reduce,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(result.Rows) == 0 {
+		return 0, nil
+	}
+
+	total, ok := result.Rows[0].Value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("counter %s: unexpected reduce value %v", c.name, result.Rows[0].Value)
+	}
+
+	return int64(total), nil
+}
+
+// Set is a two-phase set: adds and removes are stored as immutable operation
+// documents, so replicas can merge them without conflicts. Once removed, an
+// element cannot be added back.
+type Set struct {
+	db       *Database
+	name     string
+	clientID string
+}
+
+// Set returns a handle to the named set for the given client
+func (db *Database) Set(name, clientID string) *Set {
+	return &Set{
+		db:       db,
+		name:     name,
+		clientID: clientID,
+	}
+}
+
+// Add records the addition of an element
+func (s *Set) Add(ctx context.Context, element string) error {
+	return s.record(ctx, element, "add")
+}
+
+// Remove records the removal of an element
+func (s *Set) Remove(ctx context.Context, element string) error {
+	return s.record(ctx, element, "remove")
+}
+
+func (s *Set) record(ctx context.Context, element, op string) error {
+	id := crdtSetType + ":" + s.name + ":" + op + ":" + element + ":" + s.clientID
+
+	_, err := s.db.Update(ctx, id, map[string]interface{}{
+		"type":    crdtSetType,
+		"set":     s.name,
+		"element": element,
+		"op":      op,
+		"client":  s.clientID,
+	})
+
+	// Operation documents are write-once; an existing one means the
+	// operation was already recorded.
+	if err != nil && !isStatus(err, 409) {
+		return err
+	}
+
+	return nil
+}
+
+// Members returns the elements that have been added and not removed
+func (s *Set) Members(ctx context.Context) ([]string, error) {
+	reduce := true
+	result, err := s.db.View(ctx, CRDTDesignDoc, CRDTSetView, &ViewOptions{
+		StartKey:   []interface{}{s.name},
+		EndKey:     []interface{}{s.name, map[string]interface{}{}},
+		Reduce:     &reduce,
+		GroupLevel: 2,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		key, ok := row.Key.([]interface{})
+		if !ok || len(key) != 2 {
+			continue
+		}
+		counts, ok := row.Value.([]interface{})
+		if !ok || len(counts) != 2 {
+			continue
+		}
+
+		element, _ := key[1].(string)
+		adds, _ := counts[0].(float64)
+		removes, _ := counts[1].(float64)
+
+		if adds > 0 && removes == 0 {
+			members = append(members, element)
+		}
+	}
+
+	return members, nil
+}
+
+// Contains reports whether the element is currently a member of the set
+func (s *Set) Contains(ctx context.Context, element string) (bool, error) {
+	members, err := s.Members(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, member := range members {
+		if member == element {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
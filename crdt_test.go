@@ -0,0 +1,163 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter_Increment(t *testing.T) {
+	var puts, conflicts int
+	var stored map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/crdt_counter:hits:c1", r.URL.Path)
+
+		switch r.Method {
+		case "GET":
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(stored)
+		case "PUT":
+			puts++
+			var doc map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			// Another writer got in first, until conflicts runs out
+			if conflicts > 0 {
+				conflicts--
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+				return
+			}
+			doc["_rev"] = "2-b"
+			stored = doc
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"crdt_counter:hits:c1","rev":"2-b"}`))
+		}
+	}))
+	defer server.Close()
+
+	counter := NewClient(server.URL, nil).DB("db").Counter("hits", "c1")
+	ctx := context.Background()
+
+	require.NoError(t, counter.Increment(ctx, 5))
+	assert.Equal(t, 1, puts)
+	assert.Equal(t, map[string]interface{}{
+		"_id": "crdt_counter:hits:c1", "_rev": "2-b",
+		"type": crdtCounterType, "counter": "hits", "client": "c1", "value": float64(5),
+	}, stored)
+
+	// Conflicts are retried with the document read again
+	puts, conflicts = 0, 2
+	require.NoError(t, counter.Increment(ctx, -2))
+	assert.Equal(t, 3, puts)
+	assert.Equal(t, float64(3), stored["value"])
+
+	puts, conflicts = 0, crdtMaxRetries
+	err := counter.Increment(ctx, 1)
+	assert.ErrorContains(t, err, "too many conflicts")
+	assert.Equal(t, crdtMaxRetries, puts)
+	assert.Equal(t, float64(3), stored["value"])
+}
+
+func TestCounter_Value(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		assert.Equal(t, "/db/_design/crdt/_view/counters", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("reduce"))
+
+		switch r.URL.Query().Get("key") {
+		case `"hits"`:
+			_, _ = w.Write([]byte(`{"rows":[{"key":null,"value":12}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	value, err := db.Counter("hits", "c1").Value(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	value, err = db.Counter("unused", "c1").Value(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, value)
+}
+
+func TestSet(t *testing.T) {
+	var written []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == "PUT" {
+			id := strings.TrimPrefix(r.URL.Path, "/db/")
+			var doc map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			assert.Equal(t, "tags", doc["set"])
+			assert.Equal(t, "c1", doc["client"])
+
+			switch doc["element"] {
+			case "dup":
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+			case "denied":
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":"forbidden","reason":"read only"}`))
+			default:
+				written = append(written, id+" "+doc["op"].(string))
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"ok":true,"id":"` + id + `","rev":"1-a"}`))
+			}
+			return
+		}
+
+		q := r.URL.Query()
+		assert.Equal(t, "/db/_design/crdt/_view/sets", r.URL.Path)
+		assert.Equal(t, "2", q.Get("group_level"))
+		assert.Equal(t, `["tags"]`, q.Get("startkey"))
+		assert.Equal(t, `["tags",{}]`, q.Get("endkey"))
+		// _sum over the [adds, removes] pairs of every element
+		_, _ = w.Write([]byte(`{"rows":[
+			{"key":["tags","go"],"value":[2,0]},
+			{"key":["tags","perl"],"value":[1,1]},
+			{"key":["tags","zig"],"value":[0,1]}
+		]}`))
+	}))
+	defer server.Close()
+
+	set := NewClient(server.URL, nil).DB("db").Set("tags", "c1")
+	ctx := context.Background()
+
+	require.NoError(t, set.Add(ctx, "go"))
+	require.NoError(t, set.Remove(ctx, "perl"))
+	assert.Equal(t, []string{
+		"crdt_set:tags:add:go:c1 add",
+		"crdt_set:tags:remove:perl:c1 remove",
+	}, written)
+
+	// An operation that was recorded before is not an error
+	require.NoError(t, set.Add(ctx, "dup"))
+	assert.Error(t, set.Add(ctx, "denied"))
+
+	members, err := set.Members(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go"}, members)
+
+	ok, err := set.Contains(ctx, "perl")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -1,6 +1,12 @@
 package couchdb
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
 
 // Client methods
 
@@ -44,9 +50,62 @@ func (c *Client) AllDbs(ctx context.Context) ([]string, error) {
 
 // CreateDB creates a new database
 func (c *Client) CreateDB(ctx context.Context, name string) error {
+	return c.createDB(ctx, name, nil)
+}
+
+// CreateDBWithOptions creates a new database with the given sharding,
+// replication and partitioning. If opts.Security is set it is applied
+// once the database exists.
+func (c *Client) CreateDBWithOptions(ctx context.Context, name string, opts *DBCreateOptions) error {
+	if err := c.createDB(ctx, name, opts); err != nil {
+		return err
+	}
+
+	if opts != nil && opts.Security != nil {
+		return c.DB(name).SetSecurity(ctx, opts.Security)
+	}
+
+	return nil
+}
+
+// DBCreateOptions holds options for creating a database
+type DBCreateOptions struct {
+	Q           int  // number of shards, server default if zero
+	N           int  // number of replicas, server default if zero
+	Partitioned bool // create a partitioned database
+
+	// Security, if set, is applied once the database exists
+	Security *SecurityObject
+}
+
+func (o *DBCreateOptions) queryParams() map[string]string {
+	params := make(map[string]string)
+	if o == nil {
+		return params
+	}
+
+	if o.Q > 0 {
+		params["q"] = strconv.Itoa(o.Q)
+	}
+	if o.N > 0 {
+		params["n"] = strconv.Itoa(o.N)
+	}
+	if o.Partitioned {
+		params["partitioned"] = "true"
+	}
+
+	return params
+}
+
+func (c *Client) createDB(ctx context.Context, name string, opts *DBCreateOptions) error {
+	if err := ValidateDBName(name); err != nil {
+		return err
+	}
+
 	resp, err := c.resty.R().
 		SetContext(ctx).
-		Put("/" + name)
+		SetQueryParams(opts.queryParams()).
+		Put(buildPath(name))
 
 	if err != nil {
 		return err
@@ -59,11 +118,64 @@ func (c *Client) CreateDB(ctx context.Context, name string) error {
 	return nil
 }
 
+// EnsureDB creates the database if it does not exist and reports whether
+// it was created. An existing database is checked against the shard count
+// and partitioning in opts, which cannot be changed after creation. If
+// opts.Security is set it is applied unless already in place.
+func (c *Client) EnsureDB(ctx context.Context, name string, opts *DBCreateOptions) (bool, error) {
+	created := true
+	if err := c.createDB(ctx, name, opts); err != nil {
+		if !isStatus(err, http.StatusPreconditionFailed) {
+			return false, err
+		}
+		created = false
+	}
+
+	db := c.DB(name)
+
+	if !created && opts != nil && (opts.Q > 0 || opts.Partitioned) {
+		info, err := db.Info(ctx)
+		if err != nil {
+			return false, err
+		}
+		if opts.Q > 0 && info.Cluster != nil && info.Cluster.Q != opts.Q {
+			return false, fmt.Errorf("database %s has %d shards, expected %d", name, info.Cluster.Q, opts.Q)
+		}
+		if opts.Partitioned && !info.Props.Partitioned {
+			return false, fmt.Errorf("database %s exists but is not partitioned", name)
+		}
+	}
+
+	if opts != nil && opts.Security != nil {
+		current, err := db.GetSecurity(ctx)
+		if err != nil {
+			return created, err
+		}
+		if !reflect.DeepEqual(normalizeSecurity(*current), normalizeSecurity(*opts.Security)) {
+			if err := db.SetSecurity(ctx, opts.Security); err != nil {
+				return created, err
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// normalizeSecurity treats empty and missing lists alike
+func normalizeSecurity(s SecurityObject) SecurityObject {
+	for _, list := range []*[]string{&s.Admins.Names, &s.Admins.Roles, &s.Members.Names, &s.Members.Roles} {
+		if len(*list) == 0 {
+			*list = nil
+		}
+	}
+	return s
+}
+
 // DeleteDB deletes a database
 func (c *Client) DeleteDB(ctx context.Context, name string) error {
 	resp, err := c.resty.R().
 		SetContext(ctx).
-		Delete("/" + name)
+		Delete(buildPath(name))
 
 	if err != nil {
 		return err
@@ -84,7 +196,7 @@ func (db *Database) Info(ctx context.Context) (*DatabaseInfo, error) {
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetResult(&info).
-		Get("/" + db.name)
+		Get(db.path())
 
 	if err != nil {
 		return nil, err
@@ -96,3 +208,77 @@ func (db *Database) Info(ctx context.Context) (*DatabaseInfo, error) {
 
 	return &info, nil
 }
+
+// GetRevsLimit returns how many revisions of each document the database
+// keeps track of, 1000 by default
+func (db *Database) GetRevsLimit(ctx context.Context) (int, error) {
+	var limit int
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetResult(&limit).
+		Get(db.path("_revs_limit"))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.IsError() {
+		return 0, db.client.parseError(resp)
+	}
+
+	return limit, nil
+}
+
+// SetRevsLimit sets how many revisions of each document the database keeps
+// track of. Lowering it saves space on heavily updated documents, at the
+// cost of more conflicts when replicas diverge for longer than the limit.
+func (db *Database) SetRevsLimit(ctx context.Context, limit int) error {
+	if limit <= 0 {
+		return fmt.Errorf("revs limit must be positive, got %d", limit)
+	}
+
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody([]byte(strconv.Itoa(limit))).
+		Put(db.path("_revs_limit"))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	return nil
+}
+
+// DBInfoResult is one entry of a DBsInfo response
+type DBInfoResult struct {
+	Key   string        `json:"key"`
+	Info  *DatabaseInfo `json:"info,omitempty"`
+	Error string        `json:"error,omitempty"` // e.g. "not_found"
+}
+
+// DBsInfo returns information about several databases in one request
+// (CouchDB 2.2+). Results are in the order of names; databases that do not
+// exist are reported through Error rather than failing the request.
+func (c *Client) DBsInfo(ctx context.Context, names []string) ([]DBInfoResult, error) {
+	var results []DBInfoResult
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetBody(map[string][]string{"keys": names}).
+		SetResult(&results).
+		Post("/_dbs_info")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return results, nil
+}
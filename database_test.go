@@ -0,0 +1,113 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureDB(t *testing.T) {
+	exists := false
+	securityWrites := 0
+	security := SecurityObject{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/app":
+			if exists {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				_, _ = w.Write([]byte(`{"error":"file_exists","reason":"The database could not be created, the file already exists."}`))
+				return
+			}
+			assert.Equal(t, "8", r.URL.Query().Get("q"))
+			exists = true
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case r.Method == "GET" && r.URL.Path == "/app":
+			_, _ = w.Write([]byte(`{"db_name":"app","cluster":{"q":8,"n":3,"w":2,"r":2},"props":{}}`))
+		case r.URL.Path == "/app/_security" && r.Method == "GET":
+			_ = json.NewEncoder(w).Encode(security)
+		case r.URL.Path == "/app/_security" && r.Method == "PUT":
+			securityWrites++
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&security))
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+	opts := &DBCreateOptions{Q: 8, Security: &SecurityObject{Members: SecurityMembers{Roles: []string{"app"}}}}
+
+	created, err := client.EnsureDB(ctx, "app", opts)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 1, securityWrites)
+
+	created, err = client.EnsureDB(ctx, "app", opts)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, 1, securityWrites, "matching security must not be rewritten")
+
+	_, err = client.EnsureDB(ctx, "app", &DBCreateOptions{Q: 16})
+	assert.ErrorContains(t, err, "has 8 shards, expected 16")
+
+	_, err = client.EnsureDB(ctx, "app", &DBCreateOptions{Partitioned: true})
+	assert.ErrorContains(t, err, "not partitioned")
+}
+
+func TestCreateDBWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/events", r.URL.Path)
+		assert.Equal(t, "16", r.URL.Query().Get("q"))
+		assert.Equal(t, "2", r.URL.Query().Get("n"))
+		assert.Equal(t, "true", r.URL.Query().Get("partitioned"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	err := client.CreateDBWithOptions(context.Background(), "events", &DBCreateOptions{Q: 16, N: 2, Partitioned: true})
+	require.NoError(t, err)
+}
+
+func TestRevsLimit(t *testing.T) {
+	limit := "1000"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/db/_revs_limit", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			limit = string(body)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(limit + "\n"))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	n, err := db.GetRevsLimit(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, n)
+
+	require.NoError(t, db.SetRevsLimit(ctx, 50))
+	n, err = db.GetRevsLimit(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 50, n)
+
+	assert.Error(t, db.SetRevsLimit(ctx, 0))
+}
@@ -0,0 +1,104 @@
+package couchdb
+
+import "context"
+
+// DeployOptions configures DeployDesignDocSafely
+type DeployOptions struct {
+	// StagingName is the design document the indexes are built under,
+	// defaults to the name followed by "-staging"
+	StagingName string
+
+	// Warm configures the warming of the staged views; Lazy is ignored, as
+	// the swap has to wait for the indexes
+	Warm WarmOptions
+
+	// SkipViewCleanup leaves the index files of replaced views on disk
+	SkipViewCleanup bool
+}
+
+// DeployDesignDocSafely replaces a design document without making queries
+// wait for its views to be rebuilt. The views are first published under a
+// staging design document and warmed; CouchDB shares index files between
+// design documents with identical views, so the live design document then
+// switches to the built indexes in a single write. Finally the staging
+// document is deleted and the old index files are cleaned up.
+//
+// The staging document carries only the views, so validation and other
+// functions take effect with the swap. If warming fails the live design
+// document is left untouched. It reports whether the design document was
+// written; an identical one is left alone.
+func (db *Database) DeployDesignDocSafely(ctx context.Context, name string, ddoc *DesignDocument, opts *DeployOptions) (bool, error) {
+	var o DeployOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.StagingName == "" {
+		o.StagingName = name + "-staging"
+	}
+	o.Warm.Lazy = false
+
+	live, err := db.GetDesignDoc(ctx, name)
+	if err != nil && !isStatus(err, 404) {
+		return false, err
+	}
+	if live != nil && designDocsEqual(live, ddoc) {
+		return false, nil
+	}
+
+	if len(ddoc.Views) > 0 {
+		stagingRev, err := db.stageViews(ctx, o.StagingName, ddoc)
+		if err != nil {
+			return false, err
+		}
+		if err := db.WarmViews(ctx, o.StagingName, &o.Warm); err != nil {
+			return false, err
+		}
+
+		if err := db.swapDesignDoc(ctx, name, ddoc, live); err != nil {
+			return false, err
+		}
+		if err := db.DeleteDesignDoc(ctx, o.StagingName, stagingRev); err != nil && !isStatus(err, 404) {
+			return true, err
+		}
+	} else if err := db.swapDesignDoc(ctx, name, ddoc, live); err != nil {
+		return false, err
+	}
+
+	if o.SkipViewCleanup {
+		return true, nil
+	}
+	return true, db.ViewCleanup(ctx)
+}
+
+// stageViews writes the views of ddoc to the staging design document,
+// replacing one left by an earlier deployment, and returns its revision
+func (db *Database) stageViews(ctx context.Context, stagingName string, ddoc *DesignDocument) (string, error) {
+	staging := &DesignDocument{Language: ddoc.Language, Views: ddoc.Views}
+
+	existing, err := db.GetDesignDoc(ctx, stagingName)
+	if err != nil && !isStatus(err, 404) {
+		return "", err
+	}
+	if existing != nil {
+		staging.Rev = existing.Rev
+	}
+
+	result, err := db.PutDesignDoc(ctx, stagingName, staging)
+	if err != nil {
+		return "", err
+	}
+	return result.Rev, nil
+}
+
+// swapDesignDoc writes ddoc over the live design document
+func (db *Database) swapDesignDoc(ctx context.Context, name string, ddoc *DesignDocument, live *DesignDocument) error {
+	desired := *ddoc
+	desired.ID = ""
+	desired.Rev = ""
+	if live != nil {
+		desired.Rev = live.Rev
+	}
+
+	_, err := db.PutDesignDoc(ctx, name, &desired)
+	return err
+}
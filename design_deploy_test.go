@@ -0,0 +1,153 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deployServer stores design documents and records the requests made
+type deployServer struct {
+	mu       sync.Mutex
+	docs     map[string]map[string]interface{}
+	requests []string
+	failView bool
+}
+
+func (s *deployServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/db/")
+	s.requests = append(s.requests, r.Method+" "+path)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case path == "_view_cleanup":
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"ok":true}`)
+
+	case strings.Contains(path, "/_view/"):
+		if s.failView {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":"timeout","reason":"index build"}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_rows":0,"rows":[]}`)
+
+	case r.Method == http.MethodGet:
+		doc, ok := s.docs[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"not_found","reason":"missing"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(doc)
+
+	case r.Method == http.MethodPut:
+		var doc map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&doc)
+		existing := s.docs[path]
+		if existing != nil && doc["_rev"] != existing["_rev"] {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"error":"conflict","reason":"Document update conflict."}`)
+			return
+		}
+		doc["_rev"] = fmt.Sprintf("%d-x", len(s.requests))
+		s.docs[path] = doc
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"ok":true,"id":%q,"rev":%q}`, path, doc["_rev"])
+
+	case r.Method == http.MethodDelete:
+		delete(s.docs, path)
+		fmt.Fprint(w, `{"ok":true}`)
+	}
+}
+
+func (s *deployServer) takeRequests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+func TestDeployDesignDocSafely(t *testing.T) {
+	fake := &deployServer{docs: map[string]map[string]interface{}{
+		"_design/app": {"_id": "_design/app", "_rev": "1-a", "views": map[string]interface{}{"old": map[string]interface{}{"map": "function (doc) {}"}}},
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	ddoc := &DesignDocument{
+		Views:    map[string]*View{"by_date": {Map: "function (doc) { emit(doc.date); }"}},
+		Validate: "function (newDoc) { if (!newDoc.date) throw({forbidden: 'date'}); }",
+	}
+
+	// A failed warm-up leaves the live design document alone
+	fake.failView = true
+	_, err := db.DeployDesignDocSafely(ctx, "app", ddoc, nil)
+	require.Error(t, err)
+	assert.Equal(t, "1-a", fake.docs["_design/app"]["_rev"])
+	fake.takeRequests()
+
+	fake.failView = false
+	deployed, err := db.DeployDesignDocSafely(ctx, "app", ddoc, nil)
+	require.NoError(t, err)
+	assert.True(t, deployed)
+	assert.Equal(t, []string{
+		"GET _design/app",
+		"GET _design/app-staging",
+		"PUT _design/app-staging",
+		"GET _design/app-staging",
+		"GET _design/app-staging/_view/by_date",
+		"PUT _design/app",
+		"DELETE _design/app-staging",
+		"POST _view_cleanup",
+	}, fake.takeRequests())
+
+	live := fake.docs["_design/app"]
+	assert.Contains(t, live["views"], "by_date")
+	assert.NotContains(t, live["views"], "old")
+	assert.Contains(t, live["validate_doc_update"], "forbidden")
+	assert.NotContains(t, fake.docs, "_design/app-staging")
+
+	// Deploying the same design document again changes nothing
+	deployed, err = db.DeployDesignDocSafely(ctx, "app", ddoc, &DeployOptions{StagingName: "app-next"})
+	require.NoError(t, err)
+	assert.False(t, deployed)
+	assert.Equal(t, []string{"GET _design/app"}, fake.takeRequests())
+}
+
+func TestDeployDesignDocSafely_StagingHasOnlyViews(t *testing.T) {
+	fake := &deployServer{docs: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ddoc := &DesignDocument{
+		Views:    map[string]*View{"all": {Map: "function (doc) { emit(doc._id); }"}},
+		Validate: "function () { throw({forbidden: 'no'}); }",
+		Filters:  map[string]string{"f": "function () { return true; }"},
+	}
+
+	stagingRev, err := db.stageViews(context.Background(), "app-next", ddoc)
+	require.NoError(t, err)
+	assert.NotEmpty(t, stagingRev)
+
+	staging := fake.docs["_design/app-next"]
+	assert.Contains(t, staging["views"], "all")
+	assert.NotContains(t, staging, "validate_doc_update")
+	assert.NotContains(t, staging, "filters")
+}
@@ -0,0 +1,168 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DesignDocDiff describes how a design document on the server differs from
+// the desired one. Added names are only in the desired document, Removed
+// names only on the server.
+type DesignDocDiff struct {
+	Name string
+
+	// Missing is set when the design document does not exist on the server
+	Missing bool
+
+	Views   FunctionDiff
+	Filters FunctionDiff
+	Shows   FunctionDiff
+	Lists   FunctionDiff
+	Updates FunctionDiff
+
+	// ValidateChanged is set when validate_doc_update was added, removed
+	// or changed
+	ValidateChanged bool
+}
+
+// FunctionDiff lists the names of the functions in one section of a design
+// document that differ, each sorted
+type FunctionDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty reports whether the section is the same on both sides
+func (d FunctionDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// InSync reports whether the server copy matches the desired design document
+func (d *DesignDocDiff) InSync() bool {
+	return !d.Missing && !d.ValidateChanged && d.Views.Empty() && d.Filters.Empty() &&
+		d.Shows.Empty() && d.Lists.Empty() && d.Updates.Empty()
+}
+
+// String summarizes the differences, one line per section, e.g. for the
+// output of a CI check
+func (d *DesignDocDiff) String() string {
+	if d.InSync() {
+		return fmt.Sprintf("_design/%s: in sync", d.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "_design/%s:", d.Name)
+	if d.Missing {
+		b.WriteString(" missing on the server")
+	}
+	for _, section := range []struct {
+		name string
+		diff FunctionDiff
+	}{
+		{"views", d.Views},
+		{"filters", d.Filters},
+		{"shows", d.Shows},
+		{"lists", d.Lists},
+		{"updates", d.Updates},
+	} {
+		if section.diff.Empty() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s:", section.name)
+		for _, change := range []struct {
+			sign  string
+			names []string
+		}{{"+", section.diff.Added}, {"-", section.diff.Removed}, {"~", section.diff.Changed}} {
+			for _, name := range change.names {
+				fmt.Fprintf(&b, " %s%s", change.sign, name)
+			}
+		}
+	}
+	if d.ValidateChanged {
+		b.WriteString("\n  validate_doc_update changed")
+	}
+	return b.String()
+}
+
+// DiffDesignDoc compares desired with the design document stored on the
+// server, e.g. to check in CI that the deployed views match source control.
+// A missing design document is reported with everything in desired added.
+func (db *Database) DiffDesignDoc(ctx context.Context, name string, desired *DesignDocument) (*DesignDocDiff, error) {
+	name = strings.TrimPrefix(name, "_design/")
+
+	missing := false
+	current, err := db.GetDesignDoc(ctx, name)
+	if err != nil {
+		if !isStatus(err, 404) {
+			return nil, err
+		}
+		missing = true
+		current = &DesignDocument{}
+	}
+
+	languageChanged := !missing && designLanguage(current) != designLanguage(desired)
+	diff := &DesignDocDiff{
+		Name:            name,
+		Missing:         missing,
+		Views:           diffViews(current.Views, desired.Views, languageChanged),
+		Filters:         diffFunctions(current.Filters, desired.Filters),
+		Shows:           diffFunctions(current.Shows, desired.Shows),
+		Lists:           diffFunctions(current.Lists, desired.Lists),
+		Updates:         diffFunctions(current.Updates, desired.Updates),
+		ValidateChanged: current.Validate != desired.Validate,
+	}
+
+	return diff, nil
+}
+
+// designLanguage returns the language of a design document, which CouchDB
+// defaults to JavaScript
+func designLanguage(d *DesignDocument) string {
+	if d.Language == "" {
+		return "javascript"
+	}
+	return d.Language
+}
+
+// diffViews compares view definitions; a change of language changes every view
+func diffViews(current, desired map[string]*View, languageChanged bool) FunctionDiff {
+	currentSources := make(map[string]string, len(current))
+	for name, view := range current {
+		currentSources[name] = view.Map + "\x00" + view.Reduce
+	}
+	desiredSources := make(map[string]string, len(desired))
+	for name, view := range desired {
+		desiredSources[name] = view.Map + "\x00" + view.Reduce
+		if languageChanged {
+			desiredSources[name] += "\x00language"
+		}
+	}
+	return diffFunctions(currentSources, desiredSources)
+}
+
+// diffFunctions compares two sections of named function sources
+func diffFunctions(current, desired map[string]string) FunctionDiff {
+	var diff FunctionDiff
+	for name, source := range desired {
+		existing, ok := current[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case existing != source:
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range current {
+		if _, ok := desired[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
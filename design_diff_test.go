@@ -0,0 +1,69 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDesignDoc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/db/_design/app" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"_id":"_design/app","_rev":"3-c","language":"javascript",
+			"views":{"by_date":{"map":"function (doc) { emit(doc.date) }"},"by_user":{"map":"function (doc) { emit(doc.user) }","reduce":"_count"},"old":{"map":"function (doc) {}"}},
+			"filters":{"important":"function (doc) { return doc.important }"},
+			"validate_doc_update":"function (newDoc) {}"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	desired := &DesignDocument{
+		Views: map[string]*View{
+			"by_date": {Map: "function (doc) { emit(doc.date) }"},
+			"by_user": {Map: "function (doc) { emit(doc.user) }", Reduce: "_sum"},
+			"by_tag":  {Map: "function (doc) { emit(doc.tag) }"},
+		},
+		Filters:  map[string]string{"important": "function (doc) { return doc.important }"},
+		Validate: "function (newDoc) {}",
+	}
+
+	diff, err := db.DiffDesignDoc(ctx, "_design/app", desired)
+	require.NoError(t, err)
+	assert.False(t, diff.InSync())
+	assert.False(t, diff.Missing)
+	assert.Equal(t, FunctionDiff{Added: []string{"by_tag"}, Removed: []string{"old"}, Changed: []string{"by_user"}}, diff.Views)
+	assert.True(t, diff.Filters.Empty())
+	assert.False(t, diff.ValidateChanged)
+	assert.Equal(t, "_design/app:\n  views: +by_tag -old ~by_user", diff.String())
+
+	// The deployed document compared with itself is in sync
+	desired.Views["by_user"].Reduce = "_count"
+	desired.Views["old"] = &View{Map: "function (doc) {}"}
+	delete(desired.Views, "by_tag")
+	diff, err = db.DiffDesignDoc(ctx, "app", desired)
+	require.NoError(t, err)
+	assert.True(t, diff.InSync(), diff.String())
+
+	desired.Validate = ""
+	desired.Filters = nil
+	diff, err = db.DiffDesignDoc(ctx, "app", desired)
+	require.NoError(t, err)
+	assert.True(t, diff.ValidateChanged)
+	assert.Equal(t, []string{"important"}, diff.Filters.Removed)
+
+	diff, err = db.DiffDesignDoc(ctx, "other", desired)
+	require.NoError(t, err)
+	assert.True(t, diff.Missing)
+	assert.Equal(t, []string{"by_date", "by_user", "old"}, diff.Views.Added)
+}
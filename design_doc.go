@@ -1,6 +1,12 @@
 package couchdb
 
-import "context"
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
 
 // Design Document Methods
 
@@ -10,7 +16,7 @@ func (db *Database) GetDesignDoc(ctx context.Context, name string) (*DesignDocum
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetResult(&designDoc).
-		Get("/" + db.name + "/_design/" + name)
+		Get(db.designPath(name))
 
 	if err != nil {
 		return nil, err
@@ -26,12 +32,17 @@ func (db *Database) GetDesignDoc(ctx context.Context, name string) (*DesignDocum
 // PutDesignDoc creates or updates a design document
 func (db *Database) PutDesignDoc(ctx context.Context, name string, designDoc *DesignDocument) (*Document, error) {
 	if designDoc.ID == "" {
-		designDoc.ID = "_design/" + name
+		designDoc.ID = "_design/" + strings.TrimPrefix(name, "_design/")
 	}
 	if designDoc.Language == "" {
 		designDoc.Language = "javascript"
 	}
 
+	body, err := db.client.guardDocument(designDoc)
+	if err != nil {
+		return nil, err
+	}
+
 	var result struct {
 		ID  string `json:"id"`
 		Rev string `json:"rev"`
@@ -40,9 +51,9 @@ func (db *Database) PutDesignDoc(ctx context.Context, name string, designDoc *De
 
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
-		SetBody(designDoc).
+		SetBody(body).
 		SetResult(&result).
-		Put("/" + db.name + "/_design/" + name)
+		Put(db.designPath(name))
 
 	if err != nil {
 		return nil, err
@@ -60,7 +71,7 @@ func (db *Database) DeleteDesignDoc(ctx context.Context, name, rev string) error
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetQueryParam("rev", rev).
-		Delete("/" + db.name + "/_design/" + name)
+		Delete(db.designPath(name))
 
 	if err != nil {
 		return err
@@ -81,7 +92,7 @@ func (db *Database) ListDesignDocs(ctx context.Context) (*ViewResult, error) {
 		SetQueryParam("startkey", `"_design/"`).
 		SetQueryParam("endkey", `"_design0"`).
 		SetResult(&result).
-		Get("/" + db.name + "/_all_docs")
+		Get(db.path("_all_docs"))
 
 	if err != nil {
 		return nil, err
@@ -93,3 +104,143 @@ func (db *Database) ListDesignDocs(ctx context.Context) (*ViewResult, error) {
 
 	return &result, nil
 }
+
+// ensureView creates or updates a single view in a design document, leaving
+// other views untouched. The document is changed as a map, so fields
+// DesignDocument does not model, such as options, are kept.
+func (db *Database) ensureView(ctx context.Context, designDocName, viewName string, view *View) error {
+	var designDoc map[string]interface{}
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetResult(&designDoc).
+		Get(db.designPath(designDocName))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		if resp.StatusCode() != http.StatusNotFound {
+			return db.client.parseError(resp)
+		}
+		designDoc = map[string]interface{}{"language": "javascript"}
+	}
+
+	views, _ := designDoc["views"].(map[string]interface{})
+	var existing View
+	if raw, ok := views[viewName]; ok && convertDoc(raw, &existing) == nil && existing == *view {
+		return nil
+	}
+
+	if views == nil {
+		views = make(map[string]interface{})
+	}
+	views[viewName] = view
+	designDoc["views"] = views
+
+	body, err := db.client.guardDocument(designDoc)
+	if err != nil {
+		return err
+	}
+
+	resp, err = db.client.resty.R().
+		SetContext(ctx).
+		SetBody(body).
+		Put(db.designPath(designDocName))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	return nil
+}
+
+// UpdateHandlerResult is the response of an update handler
+type UpdateHandlerResult struct {
+	ID          string // from X-Couch-Id, empty if the handler saved nothing
+	Rev         string // from X-Couch-Update-NewRev, empty if the handler saved nothing
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// UpdateHandler invokes an update function. With an empty docID the
+// handler is called with a null document via POST, otherwise the document
+// is passed to it via PUT. Structs and maps are sent as JSON; []byte and
+// string bodies are sent as they are.
+func (db *Database) UpdateHandler(ctx context.Context, designDoc, handlerName, docID string, body interface{}) (*UpdateHandlerResult, error) {
+	req := db.client.resty.R().SetContext(ctx)
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	path := db.designPath(designDoc, "_update", handlerName)
+
+	var resp *resty.Response
+	var err error
+	if docID == "" {
+		resp, err = req.Post(path)
+	} else {
+		resp, err = req.Put(path + "/" + escapeDocID(docID))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &UpdateHandlerResult{
+		ID:          resp.Header().Get("X-Couch-Id"),
+		Rev:         resp.Header().Get("X-Couch-Update-NewRev"),
+		StatusCode:  resp.StatusCode(),
+		ContentType: resp.Header().Get("Content-Type"),
+		Body:        resp.Body(),
+	}, nil
+}
+
+// RewriteResult is the response of a _rewrite route
+type RewriteResult struct {
+	StatusCode  int
+	ContentType string
+	Header      http.Header
+	Body        []byte
+}
+
+// Rewrite sends a request through the _rewrite routes of a design
+// document. path is relative to _rewrite and may include a query string,
+// e.g. "orders?limit=10". Structs and maps are sent as JSON; []byte and
+// string bodies are sent as they are. Error responses of the rewritten
+// target are returned as an *Error.
+func (db *Database) Rewrite(ctx context.Context, designDoc, path, method string, body interface{}) (*RewriteResult, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req := db.client.resty.R().SetContext(ctx)
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	resp, err := req.Execute(method, db.designPath(designDoc, "_rewrite")+"/"+strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &RewriteResult{
+		StatusCode:  resp.StatusCode(),
+		ContentType: resp.Header().Get("Content-Type"),
+		Header:      resp.Header(),
+		Body:        resp.Body(),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureView_KeepsUnmodeledFields(t *testing.T) {
+	stored := map[string]interface{}{
+		"_id":        "_design/expiry",
+		"_rev":       "1-a",
+		"language":   "javascript",
+		"options":    map[string]interface{}{"partitioned": false},
+		"autoupdate": false,
+		"views":      map[string]interface{}{"other": map[string]interface{}{"map": "function (doc) {}"}},
+	}
+	puts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/_design/expiry", r.URL.Path)
+
+		if r.Method == "PUT" {
+			puts++
+			stored = nil
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/expiry","rev":"2-b"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(stored)
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	reaper := db.NewExpiryReaper(nil)
+	require.NoError(t, reaper.EnsureView(context.Background()))
+
+	require.Equal(t, 1, puts)
+	assert.Equal(t, "1-a", stored["_rev"])
+	assert.Equal(t, map[string]interface{}{"partitioned": false}, stored["options"])
+	assert.Equal(t, false, stored["autoupdate"])
+
+	views := stored["views"].(map[string]interface{})
+	assert.Contains(t, views, "other")
+	assert.Equal(t, expiryView.Map, views["by_expiry"].(map[string]interface{})["map"])
+
+	// An identical view is not written again
+	require.NoError(t, reaper.EnsureView(context.Background()))
+	assert.Equal(t, 1, puts)
+}
+
+func TestEnsureView_CreatesDesignDoc(t *testing.T) {
+	var stored map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == "PUT" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/expiry","rev":"1-a"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	require.NoError(t, db.NewExpiryReaper(nil).EnsureView(context.Background()))
+
+	assert.Equal(t, "javascript", stored["language"])
+	assert.Contains(t, stored["views"], "by_expiry")
+	assert.NotContains(t, stored, "_rev")
+}
+
+func TestRewrites(t *testing.T) {
+	var doc DesignDocument
+	require.NoError(t, json.Unmarshal([]byte(`{"rewrites":[{"from":"/orders","to":"_view/orders","method":"GET","query":{"limit":"10"}}]}`), &doc))
+	require.Len(t, doc.Rewrites.Rules, 1)
+	assert.Equal(t, RewriteRule{From: "/orders", To: "_view/orders", Method: "GET", Query: map[string]interface{}{"limit": "10"}}, doc.Rewrites.Rules[0])
+
+	require.NoError(t, json.Unmarshal([]byte(`{"rewrites":"function (req) {}"}`), &doc))
+	assert.Equal(t, &Rewrites{Function: "function (req) {}"}, doc.Rewrites)
+
+	data, err := json.Marshal(DesignDocument{Rewrites: &Rewrites{Function: "function (req) {}"}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rewrites":"function (req) {}"}`, string(data))
+
+	data, err = json.Marshal(DesignDocument{Rewrites: &Rewrites{Rules: []RewriteRule{{From: "/a", To: "b"}}}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"rewrites":[{"from":"/a","to":"b"}]}`, string(data))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_design/api/_rewrite/orders", r.URL.Path)
+		if r.Method == "POST" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	result, err := db.Rewrite(ctx, "api", "/orders?limit=10", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "application/json", result.ContentType)
+	assert.JSONEq(t, `{"rows":[]}`, string(result.Body))
+
+	_, err = db.Rewrite(ctx, "api", "orders", http.MethodPost, map[string]string{"item": "x"})
+	assert.True(t, IsNotFound(err))
+}
+
+func TestUpdateHandler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "/db/_design/app/_update/stamp/doc1", r.URL.Path)
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "alice", body["by"])
+			w.Header().Set("X-Couch-Id", "doc1")
+			w.Header().Set("X-Couch-Update-NewRev", "2-b")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("stamped"))
+		case "POST":
+			assert.Equal(t, "/db/_design/app/_update/stamp", r.URL.Path)
+			_, _ = w.Write([]byte("nothing saved"))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	result, err := db.UpdateHandler(ctx, "app", "stamp", "doc1", map[string]string{"by": "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", result.Rev)
+	assert.Equal(t, http.StatusCreated, result.StatusCode)
+	assert.Equal(t, "stamped", string(result.Body))
+
+	result, err = db.UpdateHandler(ctx, "app", "stamp", "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rev)
+}
@@ -0,0 +1,219 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LoadDesignDocs composes design documents from a directory tree. Each
+// top-level directory is one design document laid out as:
+//
+//	<ddoc>/views/<view>/map.js
+//	<ddoc>/views/<view>/reduce.js       (optional; may name a builtin such as _count)
+//	<ddoc>/filters/<name>.js
+//	<ddoc>/updates/<name>.js
+//	<ddoc>/shows/<name>.js
+//	<ddoc>/lists/<name>.js
+//	<ddoc>/validate_doc_update.js
+//	<ddoc>/rewrites.js                  (a rewrite function, CouchDB 2.0+)
+//
+// Files without a .js extension are ignored. Use fs.Sub to load from a
+// subdirectory of an embed.FS.
+func LoadDesignDocs(fsys fs.FS) (map[string]*DesignDocument, error) {
+	docs := make(map[string]*DesignDocument)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".js" {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		source := strings.TrimSpace(string(data))
+
+		parts := strings.Split(p, "/")
+		if len(parts) < 2 {
+			return fmt.Errorf("design docs: %s is not inside a design document directory", p)
+		}
+
+		name := parts[0]
+		doc, ok := docs[name]
+		if !ok {
+			doc = &DesignDocument{ID: "_design/" + name, Language: "javascript"}
+			docs[name] = doc
+		}
+
+		if err := addDesignFunction(doc, parts[1:], source); err != nil {
+			return fmt.Errorf("design docs: %s: %w", p, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, doc := range docs {
+		for viewName, view := range doc.Views {
+			if view.Map == "" {
+				return nil, fmt.Errorf("design docs: view %s/%s has no map.js", name, viewName)
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+// addDesignFunction places one function source in the design document
+func addDesignFunction(doc *DesignDocument, parts []string, source string) error {
+	if len(parts) == 1 && parts[0] == "validate_doc_update.js" {
+		doc.Validate = source
+		return nil
+	}
+	if len(parts) == 1 && parts[0] == "rewrites.js" {
+		doc.Rewrites = &Rewrites{Function: source}
+		return nil
+	}
+
+	if len(parts) == 3 && parts[0] == "views" {
+		if doc.Views == nil {
+			doc.Views = make(map[string]*View)
+		}
+		view := doc.Views[parts[1]]
+		if view == nil {
+			view = &View{}
+			doc.Views[parts[1]] = view
+		}
+
+		switch parts[2] {
+		case "map.js":
+			view.Map = source
+		case "reduce.js":
+			view.Reduce = source
+		default:
+			return fmt.Errorf("expected map.js or reduce.js")
+		}
+		return nil
+	}
+
+	if len(parts) == 2 {
+		name := strings.TrimSuffix(parts[1], ".js")
+		var section *map[string]string
+
+		switch parts[0] {
+		case "filters":
+			section = &doc.Filters
+		case "updates":
+			section = &doc.Updates
+		case "shows":
+			section = &doc.Shows
+		case "lists":
+			section = &doc.Lists
+		}
+
+		if section != nil {
+			if *section == nil {
+				*section = make(map[string]string)
+			}
+			(*section)[name] = source
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unrecognized design document path")
+}
+
+// SyncDesignDocs deploys the design documents found in fsys, as laid out
+// for LoadDesignDocs. Documents that already match are left untouched, so
+// it is safe to call at every application start. It returns the names of
+// the design documents that were written.
+func (db *Database) SyncDesignDocs(ctx context.Context, fsys fs.FS) ([]string, error) {
+	docs, err := LoadDesignDocs(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var written []string
+	for _, name := range names {
+		changed, err := db.syncDesignDoc(ctx, name, docs[name])
+		if err != nil {
+			return written, err
+		}
+		if changed {
+			written = append(written, name)
+		}
+	}
+
+	return written, nil
+}
+
+// syncDesignDoc writes desired unless the stored design document already matches it
+func (db *Database) syncDesignDoc(ctx context.Context, name string, desired *DesignDocument) (bool, error) {
+	existing, err := db.GetDesignDoc(ctx, name)
+	if err != nil {
+		if !isStatus(err, 404) {
+			return false, err
+		}
+	}
+
+	if existing != nil {
+		if designDocsEqual(existing, desired) {
+			return false, nil
+		}
+		desired.Rev = existing.Rev
+	}
+
+	if _, err := db.PutDesignDoc(ctx, name, desired); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// designDocsEqual compares the functions of two design documents, ignoring IDs
+// and revisions
+func designDocsEqual(a, b *DesignDocument) bool {
+	x, y := *a, *b
+	x.ID, y.ID = "", ""
+	x.Rev, y.Rev = "", ""
+	if x.Language == "" {
+		x.Language = "javascript"
+	}
+	if y.Language == "" {
+		y.Language = "javascript"
+	}
+	return reflect.DeepEqual(normalizeDesignDoc(x), normalizeDesignDoc(y))
+}
+
+// normalizeDesignDoc treats empty and missing sections alike
+func normalizeDesignDoc(d DesignDocument) DesignDocument {
+	if len(d.Views) == 0 {
+		d.Views = nil
+	}
+	if len(d.Indexes) == 0 {
+		d.Indexes = nil
+	}
+	if len(d.Nouveau) == 0 {
+		d.Nouveau = nil
+	}
+	if d.Rewrites != nil && d.Rewrites.Function == "" && len(d.Rewrites.Rules) == 0 {
+		d.Rewrites = nil
+	}
+	for _, section := range []*map[string]string{&d.Shows, &d.Lists, &d.Updates, &d.Filters} {
+		if len(*section) == 0 {
+			*section = nil
+		}
+	}
+	return d
+}
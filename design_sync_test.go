@@ -0,0 +1,88 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncDesignDocs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/views/by_type/map.js":      {Data: []byte("function (doc) { emit(doc.type) }\n")},
+		"app/views/by_type/reduce.js":   {Data: []byte("_count")},
+		"app/filters/important.js":      {Data: []byte("function (doc) { return doc.important }")},
+		"app/validate_doc_update.js":    {Data: []byte("function (newDoc) {}")},
+		"app/rewrites.js":               {Data: []byte("function (req) { return {path: '_view/by_type'} }")},
+		"app/README.md":                 {Data: []byte("ignored")},
+		"audit/updates/stamp.js":        {Data: []byte("function (doc, req) { return [doc, 'ok'] }")},
+		"audit/views/by_user/map.js":    {Data: []byte("function (doc) { emit(doc.user) }")},
+		"audit/views/by_user/reduce.js": {Data: []byte("_sum")},
+	}
+
+	docs, err := LoadDesignDocs(fsys)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, &View{Map: "function (doc) { emit(doc.type) }", Reduce: "_count"}, docs["app"].Views["by_type"])
+	assert.Equal(t, "function (newDoc) {}", docs["app"].Validate)
+	assert.Equal(t, "function (req) { return {path: '_view/by_type'} }", docs["app"].Rewrites.Function)
+	assert.Contains(t, docs["audit"].Updates, "stamp")
+
+	stored := map[string]*DesignDocument{}
+	puts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		name := strings.TrimPrefix(r.URL.Path, "/db/_design/")
+
+		switch r.Method {
+		case "GET":
+			doc, ok := stored[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(doc)
+		case "PUT":
+			puts++
+			var doc DesignDocument
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			doc.Rev = "1-a"
+			stored[name] = &doc
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": doc.ID, "rev": doc.Rev})
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	written, err := db.SyncDesignDocs(ctx, fsys)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "audit"}, written)
+
+	written, err = db.SyncDesignDocs(ctx, fsys)
+	require.NoError(t, err)
+	assert.Empty(t, written, "unchanged design docs must not be rewritten")
+
+	fsys["app/views/by_type/reduce.js"] = &fstest.MapFile{Data: []byte("_stats")}
+	written, err = db.SyncDesignDocs(ctx, fsys)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app"}, written)
+	assert.Equal(t, 3, puts)
+}
+
+func TestLoadDesignDocs_Invalid(t *testing.T) {
+	_, err := LoadDesignDocs(fstest.MapFS{"app/views/x/reduce.js": {Data: []byte("_count")}})
+	assert.ErrorContains(t, err, "has no map.js")
+
+	_, err = LoadDesignDocs(fstest.MapFS{"app/unknown/x.js": {Data: []byte("")}})
+	assert.ErrorContains(t, err, "unrecognized")
+}
@@ -0,0 +1,171 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// Struct tags marking the document ID and revision of application structs,
+// as an alternative to "_id" and "_rev" JSON tags:
+//
+//	type Order struct {
+//		Key     string `couchdb:"id" json:"-"`
+//		Version string `couchdb:"rev" json:"-"`
+//		Total   int    `json:"total"`
+//	}
+//
+// Put, Update, Bulk and DeleteDoc send the tagged fields as _id and _rev and
+// write the new revision back after a successful write; GetInto and
+// Document.Decode fill them from the stored document. Tagged fields must be exported strings and may
+// sit in embedded structs. Tag them json:"-" unless they should also be
+// stored under their own name.
+const (
+	tagID  = "id"
+	tagRev = "rev"
+)
+
+// taggedFields finds the fields tagged couchdb:"id" and couchdb:"rev" in
+// the struct doc holds or points to. The values are invalid when absent.
+func taggedFields(doc interface{}) (id, rev reflect.Value) {
+	v := reflect.ValueOf(doc)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct {
+		findTaggedFields(v, &id, &rev)
+	}
+	return
+}
+
+func findTaggedFields(v reflect.Value, id, rev *reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			findTaggedFields(v.Field(i), id, rev)
+			continue
+		}
+		if !field.IsExported() || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		switch field.Tag.Get("couchdb") {
+		case tagID:
+			if !id.IsValid() {
+				*id = v.Field(i)
+			}
+		case tagRev:
+			if !rev.IsValid() {
+				*rev = v.Field(i)
+			}
+		}
+	}
+}
+
+// documentBody returns the body to send for doc: doc itself, or for
+// structs with tagged fields a map with _id and _rev set from them
+func documentBody(doc interface{}) (interface{}, error) {
+	id, rev := taggedFields(doc)
+	if !id.IsValid() && !rev.IsValid() {
+		return doc, nil
+	}
+
+	var body map[string]interface{}
+	if err := convertDoc(doc, &body); err != nil {
+		return nil, err
+	}
+
+	if id.IsValid() && id.String() != "" {
+		body["_id"] = id.String()
+	}
+	if rev.IsValid() && rev.String() != "" {
+		body["_rev"] = rev.String()
+	}
+
+	return body, nil
+}
+
+// setTaggedFields stores a document ID and revision in the tagged fields of
+// doc, if it points to a struct that has them
+func setTaggedFields(doc interface{}, docID, docRev string) {
+	id, rev := taggedFields(doc)
+	if id.IsValid() && id.CanSet() && docID != "" {
+		id.SetString(docID)
+	}
+	if rev.IsValid() && rev.CanSet() && docRev != "" {
+		rev.SetString(docRev)
+	}
+}
+
+// Decode decodes the document into dest, a pointer to a struct or map,
+// filling couchdb:"id" and couchdb:"rev" fields as well
+func (d *Document) Decode(dest interface{}) error {
+	if err := convertDoc(d, dest); err != nil {
+		return err
+	}
+	setTaggedFields(dest, d.ID, d.Rev)
+	return nil
+}
+
+// DeleteDoc deletes the document identified by the couchdb:"id" and
+// couchdb:"rev" fields of doc, or by its _id and _rev JSON fields, and
+// stores the revision of the deletion in its rev field
+func (db *Database) DeleteDoc(ctx context.Context, doc interface{}) error {
+	docID, docRev, err := documentIdentity(doc)
+	if err != nil {
+		return err
+	}
+	if docID == "" || docRev == "" {
+		return errors.New("delete: document ID and revision are required")
+	}
+
+	if db.softDelete != nil {
+		result, err := db.softDeleteDoc(ctx, docID, docRev, nil)
+		if err != nil {
+			return err
+		}
+		setTaggedFields(doc, "", result.Rev)
+		return nil
+	}
+
+	var result struct {
+		Rev string `json:"rev"`
+	}
+
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetQueryParam("rev", docRev).
+		SetResult(&result).
+		Delete(db.docPath(docID))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	setTaggedFields(doc, "", result.Rev)
+	return nil
+}
+
+// documentIdentity returns the ID and revision a document is written with
+func documentIdentity(doc interface{}) (string, string, error) {
+	body, err := documentBody(doc)
+	if err != nil {
+		return "", "", err
+	}
+
+	var meta DocumentMeta
+	if err := convertDoc(body, &meta); err != nil {
+		return "", "", err
+	}
+	return meta.ID, meta.Rev, nil
+}
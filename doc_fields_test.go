@@ -0,0 +1,74 @@
+package couchdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type taggedVersion struct {
+	Version string `couchdb:"rev" json:"-"`
+}
+
+type taggedOrder struct {
+	Key string `couchdb:"id" json:"-"`
+	taggedVersion
+	Total int `json:"total"`
+}
+
+func TestTaggedFields(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("orders")
+
+	db := NewClient(server.URL, nil).DB("orders")
+	ctx := context.Background()
+
+	order := &taggedOrder{Key: "o1", Total: 10}
+	_, err := db.Put(ctx, order)
+	require.NoError(t, err)
+	assert.Regexp(t, `^1-`, order.Version)
+
+	order.Total = 20
+	_, err = db.Update(ctx, order.Key, order)
+	require.NoError(t, err)
+	assert.Regexp(t, `^2-`, order.Version)
+
+	// A stale revision is rejected
+	stale := &taggedOrder{Key: "o1", taggedVersion: taggedVersion{Version: "1-x"}}
+	_, err = db.Update(ctx, stale.Key, stale)
+	assert.True(t, IsConflict(err))
+
+	var loaded taggedOrder
+	require.NoError(t, db.GetInto(ctx, "o1", &loaded))
+	assert.Equal(t, *order, loaded)
+
+	doc, err := db.Get(ctx, "o1")
+	require.NoError(t, err)
+	assert.NotContains(t, doc.Data, "Key")
+
+	batch := []interface{}{&taggedOrder{Key: "o2"}, &taggedOrder{Key: "o1"}}
+	_, err = db.Bulk(ctx, batch)
+	require.NoError(t, err)
+	assert.Regexp(t, `^1-`, batch[0].(*taggedOrder).Version)
+	assert.Empty(t, batch[1].(*taggedOrder).Version, "conflicting write leaves the struct untouched")
+
+	require.NoError(t, db.DeleteDoc(ctx, order))
+	assert.Regexp(t, `^3-`, order.Version)
+	_, err = db.Get(ctx, "o1")
+	assert.True(t, IsNotFound(err))
+
+	assert.Error(t, db.DeleteDoc(ctx, map[string]interface{}{"_id": "o2"}))
+
+	o2, err := db.Get(ctx, "o2")
+	require.NoError(t, err)
+	var decoded taggedOrder
+	require.NoError(t, o2.Decode(&decoded))
+	assert.Equal(t, taggedOrder{Key: "o2", taggedVersion: taggedVersion{Version: o2.Rev}}, decoded)
+
+	meta := &DocumentMeta{ID: "o2", Rev: o2.Rev}
+	require.NoError(t, db.DeleteDoc(ctx, meta), "_id and _rev fields work too")
+}
@@ -2,7 +2,10 @@ package couchdb
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // ViewReduce is a convenience method to get reduced results from a view
@@ -32,7 +35,7 @@ func (db *Database) Get(ctx context.Context, id string, rev ...string) (*Documen
 	var doc Document
 	resp, err := req.
 		SetResult(&doc).
-		Get("/" + db.name + "/" + id)
+		Get(db.docPath(id))
 
 	if err != nil {
 		return nil, err
@@ -45,8 +48,88 @@ func (db *Database) Get(ctx context.Context, id string, rev ...string) (*Documen
 	return &doc, nil
 }
 
+// GetInto retrieves a document by ID and decodes it into dest, which is
+// typically a pointer to a struct. Embed DocumentMeta, or tag fields with
+// "_id" and "_rev", to capture the document's ID and revision.
+func (db *Database) GetInto(ctx context.Context, id string, dest interface{}, rev ...string) error {
+	req := db.client.resty.R().SetContext(ctx)
+
+	if len(rev) > 0 && rev[0] != "" {
+		req.SetQueryParam("rev", rev[0])
+	}
+
+	resp, err := req.
+		SetResult(dest).
+		Get(db.docPath(id))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	if id, rev := taggedFields(dest); id.IsValid() || rev.IsValid() {
+		var meta DocumentMeta
+		if err := json.Unmarshal(resp.Body(), &meta); err != nil {
+			return err
+		}
+		setTaggedFields(dest, meta.ID, meta.Rev)
+	}
+
+	return nil
+}
+
+// WriteOptions holds options for document writes
+type WriteOptions struct {
+	// W is the number of replicas that must acknowledge the write before
+	// the server responds. Zero uses the cluster default.
+	W int
+
+	// ExpiresAt sets the document's ExpiryField, for removal by an
+	// ExpiryReaper once the time has passed
+	ExpiresAt time.Time
+}
+
+func (o *WriteOptions) queryParams() map[string]string {
+	params := make(map[string]string)
+	if o != nil && o.W > 0 {
+		params["w"] = strconv.Itoa(o.W)
+	}
+	return params
+}
+
+// writeHooks returns the hooks applying the options to the document
+func (o *WriteOptions) writeHooks() []WriteHook {
+	if o == nil || o.ExpiresAt.IsZero() {
+		return nil
+	}
+
+	expiresAt := o.ExpiresAt.Unix()
+	return []WriteHook{func(doc map[string]interface{}) error {
+		doc[ExpiryField] = expiresAt
+		return nil
+	}}
+}
+
 // Put creates or updates a document
 func (db *Database) Put(ctx context.Context, doc interface{}) (*Document, error) {
+	return db.PutWithOptions(ctx, doc, nil)
+}
+
+// PutWithOptions is Put with a write quorum or expiry
+func (db *Database) PutWithOptions(ctx context.Context, doc interface{}, opts *WriteOptions) (*Document, error) {
+	payload, err := db.prepareDoc(doc, -1, append(db.assignID(ctx), opts.writeHooks()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := db.client.guardDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
 	var result struct {
 		ID  string `json:"id"`
 		Rev string `json:"rev"`
@@ -55,9 +138,10 @@ func (db *Database) Put(ctx context.Context, doc interface{}) (*Document, error)
 
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
-		SetBody(doc).
+		SetBody(body).
+		SetQueryParams(opts.queryParams()).
 		SetResult(&result).
-		Post("/" + db.name)
+		Post(db.path())
 
 	if err != nil {
 		return nil, err
@@ -67,11 +151,27 @@ func (db *Database) Put(ctx context.Context, doc interface{}) (*Document, error)
 		return nil, db.client.parseError(resp)
 	}
 
+	setTaggedFields(doc, result.ID, result.Rev)
 	return &Document{ID: result.ID, Rev: result.Rev}, nil
 }
 
 // Update updates a document with a specific ID
 func (db *Database) Update(ctx context.Context, id string, doc interface{}) (*Document, error) {
+	return db.UpdateWithOptions(ctx, id, doc, nil)
+}
+
+// UpdateWithOptions is Update with a write quorum or expiry
+func (db *Database) UpdateWithOptions(ctx context.Context, id string, doc interface{}, opts *WriteOptions) (*Document, error) {
+	payload, err := db.prepareDoc(doc, -1, opts.writeHooks()...)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := db.client.guardDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
 	var result struct {
 		ID  string `json:"id"`
 		Rev string `json:"rev"`
@@ -80,9 +180,10 @@ func (db *Database) Update(ctx context.Context, id string, doc interface{}) (*Do
 
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
-		SetBody(doc).
+		SetBody(body).
+		SetQueryParams(opts.queryParams()).
 		SetResult(&result).
-		Put("/" + db.name + "/" + id)
+		Put(db.docPath(id))
 
 	if err != nil {
 		return nil, err
@@ -92,15 +193,27 @@ func (db *Database) Update(ctx context.Context, id string, doc interface{}) (*Do
 		return nil, db.client.parseError(resp)
 	}
 
+	setTaggedFields(doc, result.ID, result.Rev)
 	return &Document{ID: result.ID, Rev: result.Rev}, nil
 }
 
 // Delete deletes a document
 func (db *Database) Delete(ctx context.Context, id, rev string) error {
+	return db.DeleteWithOptions(ctx, id, rev, nil)
+}
+
+// DeleteWithOptions is Delete with a write quorum
+func (db *Database) DeleteWithOptions(ctx context.Context, id, rev string, opts *WriteOptions) error {
+	if db.softDelete != nil {
+		_, err := db.softDeleteDoc(ctx, id, rev, opts)
+		return err
+	}
+
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
+		SetQueryParams(opts.queryParams()).
 		SetQueryParam("rev", rev).
-		Delete("/" + db.name + "/" + id)
+		Delete(db.docPath(id))
 
 	if err != nil {
 		return err
@@ -113,35 +226,26 @@ func (db *Database) Delete(ctx context.Context, id, rev string) error {
 	return nil
 }
 
-// AllDocs retrieves all documents
-func (db *Database) AllDocs(ctx context.Context, opts *ViewOptions) (*ViewResult, error) {
-	req := db.client.resty.R().SetContext(ctx)
+// Copy copies a document on the server using the COPY method. To overwrite
+// an existing target document, pass its current revision.
+func (db *Database) Copy(ctx context.Context, sourceID, targetID string, targetRev ...string) (*Document, error) {
+	// CouchDB reads the Destination header like a path and query string
+	destination := escapeDocID(targetID)
+	if len(targetRev) > 0 && targetRev[0] != "" {
+		destination += "?rev=" + url.QueryEscape(targetRev[0])
+	}
 
-	if opts != nil {
-		if opts.IncludeDocs {
-			req.SetQueryParam("include_docs", "true")
-		}
-		if opts.Limit > 0 {
-			req.SetQueryParam("limit", fmt.Sprintf("%d", opts.Limit))
-		}
-		if opts.Skip > 0 {
-			req.SetQueryParam("skip", fmt.Sprintf("%d", opts.Skip))
-		}
-		if opts.Descending {
-			req.SetQueryParam("descending", "true")
-		}
-		if opts.StartKey != nil {
-			req.SetQueryParam("startkey", fmt.Sprintf("%v", opts.StartKey))
-		}
-		if opts.EndKey != nil {
-			req.SetQueryParam("endkey", fmt.Sprintf("%v", opts.EndKey))
-		}
+	var result struct {
+		ID  string `json:"id"`
+		Rev string `json:"rev"`
+		OK  bool   `json:"ok"`
 	}
 
-	var result ViewResult
-	resp, err := req.
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetHeader("Destination", destination).
 		SetResult(&result).
-		Get("/" + db.name + "/_all_docs")
+		Execute("COPY", db.docPath(sourceID))
 
 	if err != nil {
 		return nil, err
@@ -151,29 +255,37 @@ func (db *Database) AllDocs(ctx context.Context, opts *ViewOptions) (*ViewResult
 		return nil, db.client.parseError(resp)
 	}
 
-	return &result, nil
+	return &Document{ID: result.ID, Rev: result.Rev}, nil
 }
 
-// Bulk performs bulk operations
-func (db *Database) Bulk(ctx context.Context, docs []interface{}) ([]BulkResult, error) {
-	bulkDocs := BulkDocs{
-		Docs: docs,
-	}
+// AllDocs retrieves all documents. When opts.Keys is set the keys are sent
+// in a POST body, so large key sets are not limited by URL length.
+func (db *Database) AllDocs(ctx context.Context, opts *ViewOptions) (*ViewResult, error) {
+	return db.queryRows(ctx, db.path("_all_docs"), opts)
+}
 
-	var results []BulkResult
-	resp, err := db.client.resty.R().
-		SetContext(ctx).
-		SetBody(bulkDocs).
-		SetResult(&results).
-		Post("/" + db.name + "/_bulk_docs")
+// AllDocsByKeys retrieves the rows for a specific set of document IDs.
+// Rows are returned in the order of keys; IDs that do not exist yield rows
+// with Error set instead of a value.
+func (db *Database) AllDocsByKeys(ctx context.Context, keys []string, opts *ViewOptions) (*ViewResult, error) {
+	if len(keys) == 0 {
+		return &ViewResult{Rows: []ViewRow{}}, nil
+	}
 
-	if err != nil {
-		return nil, err
+	var o ViewOptions
+	if opts != nil {
+		o = *opts
 	}
 
-	if resp.IsError() {
-		return nil, db.client.parseError(resp)
+	o.Keys = make([]interface{}, len(keys))
+	for i, key := range keys {
+		o.Keys[i] = key
 	}
 
-	return results, nil
+	return db.AllDocs(ctx, &o)
+}
+
+// Bulk performs bulk operations
+func (db *Database) Bulk(ctx context.Context, docs []interface{}) (BulkResponse, error) {
+	return db.BulkWithOptions(ctx, docs, nil)
 }
@@ -0,0 +1,90 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopy(t *testing.T) {
+	var destination string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		assert.Equal(t, "COPY", r.Method)
+		assert.Equal(t, "/db/original", r.URL.Path)
+		destination = r.Header.Get("Destination")
+
+		w.WriteHeader(http.StatusCreated)
+		if strings.Contains(destination, "?rev=") {
+			_, _ = w.Write([]byte(`{"ok":true,"id":"existing","rev":"2-b"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"id":"copy","rev":"1-c"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	doc, err := db.Copy(ctx, "original", "copy")
+	require.NoError(t, err)
+	assert.Equal(t, "copy", destination)
+	assert.Equal(t, &Document{ID: "copy", Rev: "1-c"}, doc)
+
+	doc, err = db.Copy(ctx, "original", "existing", "1-a")
+	require.NoError(t, err)
+	assert.Equal(t, "existing?rev=1-a", destination)
+	assert.Equal(t, "2-b", doc.Rev)
+
+	// Reserved characters in the target must not end its ID
+	_, err = db.Copy(ctx, "original", "a b/c?d#e%f")
+	require.NoError(t, err)
+	assert.Equal(t, "a%20b%2Fc%3Fd%23e%25f", destination)
+
+	_, err = db.Copy(ctx, "original", "_design/app", "3-x+y")
+	require.NoError(t, err)
+	assert.Equal(t, "_design/app?rev=3-x%2By", destination)
+}
+
+func TestQuorumOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+
+		switch r.Method {
+		case "GET":
+			assert.Equal(t, "3", q.Get("r"))
+			_, _ = w.Write([]byte(`{"_id":"a","_rev":"1-a"}`))
+		case "PUT", "POST":
+			assert.Equal(t, "2", q.Get("w"))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"a","rev":"2-b"}`))
+		case "DELETE":
+			assert.Equal(t, "2", q.Get("w"))
+			assert.Equal(t, "2-b", q.Get("rev"))
+			_, _ = w.Write([]byte(`{"ok":true,"id":"a","rev":"3-c"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+	write := &WriteOptions{W: 2}
+
+	_, err := db.GetWithOptions(ctx, "a", &GetOptions{R: 3})
+	require.NoError(t, err)
+
+	_, err = db.PutWithOptions(ctx, map[string]interface{}{"v": 1}, write)
+	require.NoError(t, err)
+
+	doc, err := db.UpdateWithOptions(ctx, "a", map[string]interface{}{"_rev": "1-a"}, write)
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", doc.Rev)
+
+	require.NoError(t, db.DeleteWithOptions(ctx, "a", doc.Rev, write))
+}
@@ -0,0 +1,53 @@
+package couchdb
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors matched by errors.Is against a CouchDB *Error with the
+// corresponding HTTP status, e.g. errors.Is(err, ErrNotFound)
+var (
+	ErrBadRequest         = errors.New("couchdb: bad request")
+	ErrUnauthorized       = errors.New("couchdb: unauthorized")
+	ErrForbidden          = errors.New("couchdb: forbidden")
+	ErrNotFound           = errors.New("couchdb: not found")
+	ErrConflict           = errors.New("couchdb: conflict")
+	ErrPreconditionFailed = errors.New("couchdb: precondition failed")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:         ErrBadRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusConflict:           ErrConflict,
+	http.StatusPreconditionFailed: ErrPreconditionFailed,
+}
+
+// Is reports whether target is the sentinel error for e's status
+func (e *Error) Is(target error) bool {
+	sentinel, ok := statusErrors[e.StatusCode]
+	return ok && sentinel == target
+}
+
+// IsNotFound reports whether err is a 404 from CouchDB, such as a missing
+// document or database
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is a 409 revision conflict
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsUnauthorized reports whether err is a 401, i.e. missing or invalid credentials
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403, e.g. a rejected validate_doc_update
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
@@ -0,0 +1,48 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_Sentinels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/db/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+		case "/db/taken":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+
+	_, err := db.Get(context.Background(), "missing")
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+
+	var couchErr *Error
+	require.True(t, errors.As(err, &couchErr))
+	assert.Equal(t, "not_found", couchErr.Type)
+
+	_, err = db.Update(context.Background(), "taken", map[string]interface{}{"_rev": "1-a"})
+	assert.True(t, IsConflict(err))
+	assert.True(t, errors.Is(fmt.Errorf("save: %w", err), ErrConflict))
+	assert.False(t, IsNotFound(err))
+
+	assert.True(t, IsUnauthorized(&Error{StatusCode: http.StatusUnauthorized}))
+	assert.True(t, IsForbidden(&Error{StatusCode: http.StatusForbidden}))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+}
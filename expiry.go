@@ -0,0 +1,150 @@
+package couchdb
+
+import (
+	"context"
+	"time"
+)
+
+// ExpiryField holds the expiry time of a document in Unix seconds
+const ExpiryField = "expires_at"
+
+// expiryView indexes documents by ExpiryField, with their revision as value
+var expiryView = &View{
+	Map: "function (doc) {\n  if (typeof doc." + ExpiryField + " === \"number\") {\n    emit(doc." + ExpiryField + ", doc._rev);\n  }\n}",
+}
+
+// ExpireAfter returns a WriteHook giving every document written without an
+// expiry one ttl from now, e.g. for a database of sessions
+func ExpireAfter(ttl time.Duration) WriteHook {
+	return func(doc map[string]interface{}) error {
+		if _, ok := doc[ExpiryField]; !ok && !deleted(doc) {
+			doc[ExpiryField] = time.Now().Add(ttl).Unix()
+		}
+		return nil
+	}
+}
+
+// ExpiryReaperOptions configures an ExpiryReaper
+type ExpiryReaperOptions struct {
+	DesignDoc string        // design document of the expiry view, defaults to "expiry"
+	Interval  time.Duration // time between passes, defaults to a minute
+	BatchSize int           // documents deleted per request, defaults to 500
+}
+
+// ExpiryReaper deletes documents whose ExpiryField lies in the past.
+// CouchDB has no built-in expiry, so documents are found through a view on
+// that field and deleted in bulk.
+type ExpiryReaper struct {
+	db   *Database
+	opts ExpiryReaperOptions
+	now  func() time.Time
+}
+
+// NewExpiryReaper creates a reaper for db's expired documents
+func (db *Database) NewExpiryReaper(opts *ExpiryReaperOptions) *ExpiryReaper {
+	r := &ExpiryReaper{db: db, now: time.Now}
+	if opts != nil {
+		r.opts = *opts
+	}
+	if r.opts.DesignDoc == "" {
+		r.opts.DesignDoc = "expiry"
+	}
+	if r.opts.Interval <= 0 {
+		r.opts.Interval = time.Minute
+	}
+	if r.opts.BatchSize <= 0 {
+		r.opts.BatchSize = 500
+	}
+
+	return r
+}
+
+// EnsureView creates the expiry view if it does not exist yet. Run calls
+// it before the first pass.
+func (r *ExpiryReaper) EnsureView(ctx context.Context) error {
+	return r.db.ensureView(ctx, r.opts.DesignDoc, "by_expiry", expiryView)
+}
+
+// Reap deletes the documents that have expired and returns how many were
+// deleted. Documents updated since the view was read are left for the
+// next pass.
+func (r *ExpiryReaper) Reap(ctx context.Context) (int, error) {
+	reduce := false
+	now := r.now().Unix()
+	deleted := 0
+
+	// Each page continues after the last row of the previous one, so rows
+	// of conflicting documents are not read again
+	var lastKey interface{}
+	lastID := ""
+
+	for {
+		opts := &ViewOptions{
+			EndKey: now,
+			Limit:  r.opts.BatchSize,
+			Reduce: &reduce,
+		}
+		if lastID != "" {
+			// The last row is still there if its document was not deleted
+			opts.StartKey, opts.StartKeyDocID = lastKey, lastID
+			opts.Limit++
+		}
+
+		result, err := r.db.View(ctx, r.opts.DesignDoc, "by_expiry", opts)
+		if err != nil {
+			return deleted, err
+		}
+
+		rows := result.Rows
+		if lastID != "" && len(rows) > 0 && rows[0].ID == lastID {
+			rows = rows[1:]
+		}
+		if len(rows) == 0 {
+			return deleted, nil
+		}
+
+		docs := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			docs = append(docs, map[string]interface{}{"_id": row.ID, "_rev": row.Value, "_deleted": true})
+		}
+
+		results, err := r.db.Bulk(ctx, docs)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += len(results.Succeeded())
+
+		if len(rows) < r.opts.BatchSize {
+			return deleted, nil
+		}
+		lastKey, lastID = rows[len(rows)-1].Key, rows[len(rows)-1].ID
+	}
+}
+
+// Run reaps expired documents every Interval until ctx is cancelled. A
+// failed pass is logged and retried at the next interval.
+func (r *ExpiryReaper) Run(ctx context.Context) error {
+	if err := r.EnsureView(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.Reap(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.db.client.logger.Warnf("expiry reaper on %s: %v", r.db.name, err)
+		} else if n > 0 {
+			r.db.client.logger.Debugf("expiry reaper deleted %d documents from %s", n, r.db.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
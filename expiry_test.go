@@ -0,0 +1,155 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryReaper(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("sessions")
+	server.AddView("expiry", "by_expiry", couchdbtest.View{
+		Map: func(doc map[string]interface{}, emit func(key, value interface{})) {
+			if expires, ok := doc[ExpiryField].(float64); ok {
+				emit(expires, doc["_rev"])
+			}
+		},
+	})
+
+	db := NewClient(server.URL, nil).DB("sessions")
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, expires := range []time.Duration{-time.Hour, -time.Minute, -time.Second, time.Hour} {
+		_, err := db.PutWithOptions(ctx, map[string]interface{}{"_id": string(rune('a' + i))}, &WriteOptions{ExpiresAt: now.Add(expires)})
+		require.NoError(t, err)
+	}
+	_, err := db.WithWriteHook(ExpireAfter(time.Hour)).Put(ctx, map[string]interface{}{"_id": "e"})
+	require.NoError(t, err)
+	_, err = db.Put(ctx, map[string]interface{}{"_id": "forever"})
+	require.NoError(t, err)
+
+	doc, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, float64(now.Add(-time.Hour).Unix()), doc.Data[ExpiryField])
+
+	reaper := db.NewExpiryReaper(&ExpiryReaperOptions{BatchSize: 2})
+	reaper.now = func() time.Time { return now }
+	require.NoError(t, reaper.EnsureView(ctx))
+
+	deleted, err := reaper.Reap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	all, err := db.AllDocs(ctx, nil)
+	require.NoError(t, err)
+	var ids []string
+	for _, row := range all.Rows {
+		ids = append(ids, row.ID)
+	}
+	assert.Equal(t, []string{"_design/expiry", "d", "e", "forever"}, ids)
+
+	deleted, err = reaper.Reap(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestExpiryReaper_Run(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("sessions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The view is created, and passes failing on the view are retried
+	reaper := NewClient(server.URL, nil).DB("sessions").NewExpiryReaper(&ExpiryReaperOptions{Interval: 10 * time.Millisecond})
+	assert.ErrorIs(t, reaper.Run(ctx), context.DeadlineExceeded)
+
+	_, err := NewClient(server.URL, nil).DB("sessions").GetDesignDoc(context.Background(), "expiry")
+	assert.NoError(t, err)
+}
+
+func TestExpiryReaper_Conflicts(t *testing.T) {
+	type row struct {
+		Key   float64 `json:"key"`
+		ID    string  `json:"id"`
+		Value string  `json:"value"`
+	}
+	rows := []row{{1, "a", "1-a"}, {2, "b", "1-b"}, {3, "c", "1-c"}, {3, "d", "1-d"}, {4, "e", "1-e"}}
+	conflicting := map[string]bool{"a": true, "b": true}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/sessions/_design/expiry/_view/by_expiry":
+			query := r.URL.Query()
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			startKey, _ := strconv.ParseFloat(query.Get("startkey"), 64)
+			startID := strings.Trim(query.Get("startkey_docid"), `"`)
+			endKey, _ := strconv.ParseFloat(query.Get("endkey"), 64)
+
+			page := []row{}
+			for _, row := range rows {
+				if row.Key < startKey || (row.Key == startKey && row.ID < startID) || row.Key > endKey {
+					continue
+				}
+				if len(page) < limit {
+					page = append(page, row)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"rows": page})
+
+		case "/sessions/_bulk_docs":
+			var body struct {
+				Docs []map[string]interface{} `json:"docs"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			var results []map[string]string
+			for _, doc := range body.Docs {
+				id := doc["_id"].(string)
+				if conflicting[id] {
+					results = append(results, map[string]string{"id": id, "error": "conflict", "reason": "Document update conflict."})
+					continue
+				}
+				for i := range rows {
+					if rows[i].ID == id {
+						rows = append(rows[:i], rows[i+1:]...)
+						break
+					}
+				}
+				results = append(results, map[string]string{"id": id, "rev": "2-x"})
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(results)
+		}
+	}))
+	defer server.Close()
+
+	// The first batch only holds conflicting documents, yet the later
+	// expired documents are reaped
+	reaper := NewClient(server.URL, nil).DB("sessions").NewExpiryReaper(&ExpiryReaperOptions{BatchSize: 2})
+	reaper.now = func() time.Time { return time.Unix(3, 0) }
+
+	deleted, err := reaper.Reap(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	var left []string
+	for _, row := range rows {
+		left = append(left, row.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "e"}, left)
+}
@@ -0,0 +1,185 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	Attachments    bool // include attachment content inline, base64 encoded
+	SkipDesignDocs bool // leave out _design/ documents
+}
+
+// Export writes every document of the database to w as newline-delimited
+// JSON, one document per line, and returns the number written. Documents
+// are streamed from _all_docs, so the database is never held in memory.
+// Without Attachments, attachment stubs are left out since they cannot be
+// imported anywhere else.
+func (db *Database) Export(ctx context.Context, w io.Writer, opts *ExportOptions) (int, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+
+	rows, err := db.AllDocsStream(ctx, &ViewOptions{IncludeDocs: true, Attachments: opts.Attachments})
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	enc := json.NewEncoder(w)
+	count := 0
+	for rows.Next() {
+		doc := rows.Row().Doc
+		if doc == nil {
+			continue
+		}
+		if opts.SkipDesignDocs && strings.HasPrefix(doc.ID, "_design/") {
+			continue
+		}
+		if !opts.Attachments {
+			doc.Attachments = nil
+		}
+
+		if err := enc.Encode(doc); err != nil {
+			return count, fmt.Errorf("export %s: %w", doc.ID, err)
+		}
+		count++
+	}
+
+	return count, rows.Err()
+}
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	BatchSize int // documents per _bulk_docs request, defaults to 500
+
+	// KeepRevisions stores the documents with the revisions they carry
+	// (new_edits=false), so a restored database matches the original.
+	// Otherwise _rev is dropped and the documents are written as new,
+	// which only succeeds if they do not exist yet.
+	KeepRevisions bool
+}
+
+// Import reads newline-delimited JSON documents from r, as written by
+// Export, and bulk-loads them into the database. It returns the number of
+// documents written; documents rejected by the server are reported in a
+// *BulkError, with Index being the line number counted from zero, or -1
+// with KeepRevisions, where the server does not report positions.
+func (db *Database) Import(ctx context.Context, r io.Reader, opts *ImportOptions) (int, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	bw := newBatchWriter(db, batchSize, opts.KeepRevisions)
+	dec := json.NewDecoder(r)
+
+	for line := 1; ; line++ {
+		var doc map[string]interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return bw.written, fmt.Errorf("import line %d: %w", line, err)
+		}
+
+		if !opts.KeepRevisions {
+			delete(doc, "_rev")
+		}
+		if err := bw.add(ctx, doc); err != nil {
+			return bw.written, err
+		}
+	}
+
+	return bw.close(ctx)
+}
+
+// batchWriter writes documents in _bulk_docs batches, collecting the
+// per-document failures of all batches
+type batchWriter struct {
+	db        *Database
+	batchSize int
+	newEdits  *bool
+
+	batch    []interface{}
+	offset   int // documents sent so far
+	written  int
+	failures []BulkFailure
+
+	// onBatch is called after each batch with the documents sent so far
+	onBatch func(sent, written int)
+}
+
+// newBatchWriter creates a writer; keepRevisions sends new_edits=false
+func newBatchWriter(db *Database, batchSize int, keepRevisions bool) *batchWriter {
+	bw := &batchWriter{db: db, batchSize: batchSize}
+	if keepRevisions {
+		bw.newEdits = new(bool)
+	}
+	return bw
+}
+
+func (bw *batchWriter) add(ctx context.Context, doc interface{}) error {
+	bw.batch = append(bw.batch, doc)
+	if len(bw.batch) >= bw.batchSize {
+		return bw.flush(ctx)
+	}
+	return nil
+}
+
+func (bw *batchWriter) flush(ctx context.Context) error {
+	if len(bw.batch) == 0 {
+		return nil
+	}
+
+	results, err := bw.db.bulkDocs(ctx, bw.batch, bw.offset, bw.newEdits)
+	if err != nil {
+		return err
+	}
+
+	// With new_edits=false only failures are reported
+	failed := 0
+	for i, result := range results {
+		if result.Error == "" {
+			continue
+		}
+		failed++
+		index := bw.offset + i
+		if bw.newEdits != nil {
+			index = -1
+		}
+		bw.failures = append(bw.failures, BulkFailure{Index: index, ID: result.ID, Error: result.Error, Reason: result.Reason})
+	}
+
+	bw.written += len(bw.batch) - failed
+	bw.offset += len(bw.batch)
+	bw.batch = bw.batch[:0]
+
+	if bw.onBatch != nil {
+		bw.onBatch(bw.offset, bw.written)
+	}
+	return nil
+}
+
+// close flushes the last batch and returns the documents written, with a
+// *BulkError if any were rejected
+func (bw *batchWriter) close(ctx context.Context) (int, error) {
+	if err := bw.flush(ctx); err != nil {
+		return bw.written, err
+	}
+
+	if len(bw.failures) > 0 {
+		return bw.written, &BulkError{Total: bw.offset, Failures: bw.failures}
+	}
+	return bw.written, nil
+}
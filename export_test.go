@@ -0,0 +1,140 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_all_docs", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("include_docs"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_rows":3,"offset":0,"rows":[
+			{"id":"_design/app","key":"_design/app","value":{"rev":"1-d"},"doc":{"_id":"_design/app","_rev":"1-d","views":{}}},
+			{"id":"a","key":"a","value":{"rev":"1-a"},"doc":{"_id":"a","_rev":"1-a","n":1,"_attachments":{"f.txt":{"stub":true,"length":3}}}},
+			{"id":"b","key":"b","value":{"rev":"2-b"},"doc":{"_id":"b","_rev":"2-b","n":2}}
+		]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	n, err := NewClient(server.URL, nil).DB("db").Export(context.Background(), &buf, &ExportOptions{SkipDesignDocs: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"_id":"a","_rev":"1-a","n":1}`, lines[0])
+	assert.JSONEq(t, `{"_id":"b","_rev":"2-b","n":2}`, lines[1])
+}
+
+func TestImport(t *testing.T) {
+	var requests []BulkDocs
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_bulk_docs", r.URL.Path)
+		var body struct {
+			Docs     []map[string]interface{} `json:"docs"`
+			NewEdits *bool                    `json:"new_edits"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		docs := make([]interface{}, len(body.Docs))
+		results := make([]BulkResult, len(body.Docs))
+		for i, doc := range body.Docs {
+			docs[i] = doc
+			results[i] = BulkResult{ID: doc["_id"].(string), Rev: "1-x"}
+			if doc["_id"] == "c" {
+				results[i] = BulkResult{ID: "c", Error: "conflict", Reason: "Document update conflict."}
+			}
+		}
+		requests = append(requests, BulkDocs{Docs: docs, NewEdits: body.NewEdits})
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	input := `{"_id":"a","_rev":"1-a","n":1}
+{"_id":"b","_rev":"2-b","n":2}
+{"_id":"c","_rev":"1-c","n":3}
+`
+	db := NewClient(server.URL, nil).DB("db")
+	n, err := db.Import(context.Background(), strings.NewReader(input), &ImportOptions{BatchSize: 2})
+	assert.Equal(t, 2, n)
+
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	require.Len(t, bulkErr.Conflicts(), 1)
+	assert.Equal(t, 2, bulkErr.Conflicts()[0].Index)
+
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[0].Docs, 2)
+	assert.Nil(t, requests[0].NewEdits)
+	assert.NotContains(t, requests[0].Docs[0], "_rev")
+
+	_, err = db.Import(context.Background(), strings.NewReader(`{"_id":"a"}`+"\n"+`{not json`), nil)
+	assert.ErrorContains(t, err, "import line 2")
+}
+
+func TestCopyDatabase(t *testing.T) {
+	var written []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/src/_all_docs":
+			_, _ = w.Write([]byte(`{"total_rows":4,"offset":0,"rows":[
+				{"id":"_design/app","key":"_design/app","value":{"rev":"1-d"},"doc":{"_id":"_design/app","_rev":"1-d","views":{}}},
+				{"id":"a","key":"a","value":{"rev":"1-a"},"doc":{"_id":"a","_rev":"1-a","type":"user"}},
+				{"id":"b","key":"b","value":{"rev":"1-b"},"doc":{"_id":"b","_rev":"1-b","type":"log"}},
+				{"id":"c","key":"c","value":{"rev":"3-c"},"doc":{"_id":"c","_rev":"3-c","type":"user"}}
+			]}`))
+		case "/dst/_bulk_docs":
+			var body struct {
+				Docs []map[string]interface{} `json:"docs"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			written = append(written, body.Docs...)
+
+			results := make([]BulkResult, len(body.Docs))
+			for i, doc := range body.Docs {
+				results[i] = BulkResult{ID: doc["_id"].(string), Rev: "1-x"}
+			}
+			_ = json.NewEncoder(w).Encode(results)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+
+	var progress []CopyProgress
+	n, err := CopyDatabase(context.Background(), client.DB("src"), client.DB("dst"), &CopyOptions{
+		BatchSize:      1,
+		SkipDesignDocs: true,
+		Filter:         func(doc *Document) bool { return doc.Data["type"] == "user" },
+		TransformID:    func(id string) string { return "user:" + id },
+		Transform: func(doc *Document) error {
+			doc.Data["migrated"] = true
+			return nil
+		},
+		Progress: func(p CopyProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	require.Len(t, written, 2)
+	assert.Equal(t, map[string]interface{}{"_id": "user:a", "type": "user", "migrated": true}, written[0])
+	assert.Equal(t, "user:c", written[1]["_id"])
+	assert.Equal(t, []CopyProgress{{Read: 2, Sent: 1, Written: 1}, {Read: 4, Sent: 2, Written: 2}}, progress)
+}
@@ -0,0 +1,79 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldViewName returns the conventional name of a view indexing fields,
+// e.g. "by_type" or "by_address.city_name"
+func FieldViewName(fields ...string) string {
+	return "by_" + strings.Join(fields, "_")
+}
+
+// FieldView generates a view emitting the given fields as its key: the
+// field value for a single field, otherwise an array of them. Dotted names
+// address nested fields. Documents lacking any of the fields are skipped.
+func FieldView(fields ...string) *View {
+	var conditions, keys []string
+	for _, field := range fields {
+		expr := "doc"
+		for _, part := range strings.Split(field, ".") {
+			quoted, _ := json.Marshal(part)
+			expr += "[" + string(quoted) + "]"
+			conditions = append(conditions, expr+" !== undefined")
+		}
+		keys = append(keys, expr)
+	}
+
+	key := keys[0]
+	if len(keys) > 1 {
+		key = "[" + strings.Join(keys, ", ") + "]"
+	}
+
+	return &View{
+		Map: fmt.Sprintf("function (doc) {\n  if (%s) {\n    emit(%s, null);\n  }\n}",
+			strings.Join(conditions, " && "), key),
+	}
+}
+
+// EnsureFieldView adds a FieldView named FieldViewName(fields...) to the
+// default design document set with WithDesignDoc, creating the design
+// document if needed. Other views are left untouched. It returns the view
+// name for use with Q.
+func (db *Database) EnsureFieldView(ctx context.Context, fields ...string) (string, error) {
+	if db.designDoc == "" {
+		return "", errors.New("field view: no design document, see Database.WithDesignDoc")
+	}
+	if len(fields) == 0 {
+		return "", errors.New("field view: no fields")
+	}
+
+	name := FieldViewName(fields...)
+	view := FieldView(fields...)
+
+	ddoc, err := db.GetDesignDoc(ctx, db.designDoc)
+	if err != nil {
+		if !isStatus(err, 404) {
+			return "", err
+		}
+		ddoc = &DesignDocument{Language: "javascript"}
+	}
+
+	if existing := ddoc.Views[name]; existing != nil && *existing == *view {
+		return name, nil
+	}
+
+	if ddoc.Views == nil {
+		ddoc.Views = make(map[string]*View)
+	}
+	ddoc.Views[name] = view
+
+	if _, err := db.PutDesignDoc(ctx, db.designDoc, ddoc); err != nil {
+		return "", err
+	}
+	return name, nil
+}
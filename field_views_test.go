@@ -0,0 +1,71 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldView(t *testing.T) {
+	assert.Equal(t, "by_type", FieldViewName("type"))
+	assert.Equal(t,
+		"function (doc) {\n  if (doc[\"type\"] !== undefined) {\n    emit(doc[\"type\"], null);\n  }\n}",
+		FieldView("type").Map)
+	assert.Equal(t,
+		"function (doc) {\n  if (doc[\"type\"] !== undefined && doc[\"address\"] !== undefined && doc[\"address\"][\"city\"] !== undefined) {\n"+
+			"    emit([doc[\"type\"], doc[\"address\"][\"city\"]], null);\n  }\n}",
+		FieldView("type", "address.city").Map)
+}
+
+func TestDefaultDesignDoc(t *testing.T) {
+	var stored *DesignDocument
+	writes := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/db/_design/app" && r.Method == "GET":
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			stored.Rev = "1-a"
+			_ = json.NewEncoder(w).Encode(stored)
+		case r.URL.Path == "/db/_design/app" && r.Method == "PUT":
+			writes++
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_design/app","rev":"1-a"}`))
+		case r.URL.Path == "/db/_design/app/_view/by_type":
+			assert.Equal(t, `"user"`, r.URL.Query().Get("key"))
+			_, _ = w.Write([]byte(`{"total_rows":1,"offset":0,"rows":[{"id":"u1","key":"user","value":null}]}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db").WithDesignDoc("app")
+	ctx := context.Background()
+
+	name, err := db.EnsureFieldView(ctx, "type")
+	require.NoError(t, err)
+	assert.Equal(t, "by_type", name)
+
+	_, err = db.EnsureFieldView(ctx, "type")
+	require.NoError(t, err)
+	assert.Equal(t, 1, writes, "an unchanged view must not be rewritten")
+
+	result, err := db.Q("by_type").Key("user").Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", result.Rows[0].ID)
+
+	_, err = NewClient(server.URL, nil).DB("db").Q("by_type").Run(ctx)
+	assert.ErrorContains(t, err, "no design document")
+}
@@ -0,0 +1,227 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Selector is a Mango selector, e.g. Selector{"type": "user", "age": Selector{"$gt": 21}}
+type Selector map[string]interface{}
+
+// SortField is a single Mango sort criterion
+type SortField struct {
+	Field     string
+	Direction string // "asc" or "desc"; empty means ascending
+}
+
+// MarshalJSON implements json.Marshaler
+func (s SortField) MarshalJSON() ([]byte, error) {
+	direction := s.Direction
+	if direction == "" {
+		direction = "asc"
+	}
+	return json.Marshal(map[string]string{s.Field: direction})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *SortField) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*s = SortField{Field: field, Direction: "asc"}
+		return nil
+	}
+
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	for field, direction := range m {
+		*s = SortField{Field: field, Direction: direction}
+	}
+	return nil
+}
+
+// FindQuery represents a Mango query
+type FindQuery struct {
+	Selector       Selector    `json:"selector"`
+	Fields         []string    `json:"fields,omitempty"`
+	Sort           []SortField `json:"sort,omitempty"`
+	Limit          int         `json:"limit,omitempty"`
+	Skip           int         `json:"skip,omitempty"`
+	Bookmark       string      `json:"bookmark,omitempty"`
+	UseIndex       interface{} `json:"use_index,omitempty"` // "ddoc" or ["ddoc", "index"]
+	Conflicts      bool        `json:"conflicts,omitempty"`
+	R              int         `json:"r,omitempty"`
+	Update         *bool       `json:"update,omitempty"`
+	Stable         bool        `json:"stable,omitempty"`
+	ExecutionStats bool        `json:"execution_stats,omitempty"`
+}
+
+// FindResult represents the result of a Mango query
+type FindResult struct {
+	Docs           []*Document     `json:"docs"`
+	Bookmark       string          `json:"bookmark,omitempty"`
+	Warning        string          `json:"warning,omitempty"`
+	ExecutionStats *ExecutionStats `json:"execution_stats,omitempty"`
+}
+
+// ExecutionStats holds Mango query execution statistics
+type ExecutionStats struct {
+	TotalKeysExamined       int64   `json:"total_keys_examined"`
+	TotalDocsExamined       int64   `json:"total_docs_examined"`
+	TotalQuorumDocsExamined int64   `json:"total_quorum_docs_examined"`
+	ResultsReturned         int64   `json:"results_returned"`
+	ExecutionTimeMs         float64 `json:"execution_time_ms"`
+}
+
+// Find executes a Mango query
+func (db *Database) Find(ctx context.Context, query *FindQuery) (*FindResult, error) {
+	if query.Selector == nil {
+		query.Selector = Selector{}
+	}
+	query = db.excludeDeleted(query)
+
+	var result FindResult
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(query).
+		SetResult(&result).
+		Post(db.path("_find"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &result, nil
+}
+
+// ExplainResult describes how the server would execute a Mango query
+type ExplainResult struct {
+	DBName      string                 `json:"dbname"`
+	Index       ExplainIndex           `json:"index"`
+	Partitioned bool                   `json:"partitioned"`
+	Selector    map[string]interface{} `json:"selector"`
+	Opts        map[string]interface{} `json:"opts"`
+	Limit       int                    `json:"limit"`
+	Skip        int                    `json:"skip"`
+	Fields      interface{}            `json:"fields"` // a field list or "all_fields"
+	MRArgs      map[string]interface{} `json:"mrargs"` // arguments passed to the underlying view
+	Covering    bool                   `json:"covering"`
+
+	// IndexCandidates lists the indexes considered and why they were or
+	// were not chosen (CouchDB 3.3+)
+	IndexCandidates []IndexCandidate `json:"index_candidates,omitempty"`
+}
+
+// ExplainIndex identifies an index selected by the query planner
+type ExplainIndex struct {
+	DDoc        string                 `json:"ddoc"` // empty for _all_docs
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`        // "json", "text", "nouveau" or "special" for _all_docs
+	Partitioned interface{}            `json:"partitioned"` // true, false or "db_default"
+	Def         map[string]interface{} `json:"def"`
+}
+
+// IndexCandidate is an index the query planner considered
+type IndexCandidate struct {
+	Index    ExplainIndex `json:"index"`
+	Analysis struct {
+		Usable  bool `json:"usable"`
+		Reasons []struct {
+			Name string `json:"name"` // e.g. "field_mismatch", "less_overlap"
+		} `json:"reasons"`
+		Ranking  int   `json:"ranking"`
+		Covering *bool `json:"covering"`
+	} `json:"analysis"`
+}
+
+// FullScan reports whether the query would scan every document through
+// _all_docs instead of using an index
+func (r *ExplainResult) FullScan() bool {
+	return r.Index.Type == "special"
+}
+
+// Explain returns the query plan for a Mango query without running it
+func (db *Database) Explain(ctx context.Context, query *FindQuery) (*ExplainResult, error) {
+	if query.Selector == nil {
+		query.Selector = Selector{}
+	}
+
+	var result ExplainResult
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(query).
+		SetResult(&result).
+		Post(db.path("_explain"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &result, nil
+}
+
+// MangoIndex defines a Mango index for CreateIndex
+type MangoIndex struct {
+	Fields        []string // indexed fields, in order
+	PartialFilter Selector // only index documents matching this selector
+	DesignDoc     string   // design document to store the index in; generated when empty
+	Name          string   // generated when empty
+	Type          string   // "json" (default) or "text"
+	Partitioned   *bool    // partitioned databases only; defaults to the database setting
+}
+
+// CreateIndexResult reports the outcome of CreateIndex
+type CreateIndexResult struct {
+	Result string `json:"result"` // "created" or "exists"
+	ID     string `json:"id"`     // design document ID
+	Name   string `json:"name"`
+}
+
+// CreateIndex creates a Mango index. Creating an index that already exists
+// is not an error; Result is "exists" then.
+func (db *Database) CreateIndex(ctx context.Context, index *MangoIndex) (*CreateIndexResult, error) {
+	def := map[string]interface{}{"fields": index.Fields}
+	if index.PartialFilter != nil {
+		def["partial_filter_selector"] = index.PartialFilter
+	}
+
+	body := map[string]interface{}{"index": def}
+	if index.DesignDoc != "" {
+		body["ddoc"] = index.DesignDoc
+	}
+	if index.Name != "" {
+		body["name"] = index.Name
+	}
+	if index.Type != "" {
+		body["type"] = index.Type
+	}
+	if index.Partitioned != nil {
+		body["partitioned"] = *index.Partitioned
+	}
+
+	var result CreateIndexResult
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&result).
+		Post(db.path("_index"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,201 @@
+package couchdb
+
+import "context"
+
+// Selector helpers compose Mango selectors, e.g.
+//
+//	And(Eq("type", "user"), Gte("age", 21), Or(In("role", "admin", "editor"), Exists("invite", true)))
+
+// Eq matches documents whose field equals value
+func Eq(field string, value interface{}) Selector {
+	return fieldOp(field, "$eq", value)
+}
+
+// Ne matches documents whose field is not equal to value
+func Ne(field string, value interface{}) Selector {
+	return fieldOp(field, "$ne", value)
+}
+
+// Gt matches documents whose field is greater than value
+func Gt(field string, value interface{}) Selector {
+	return fieldOp(field, "$gt", value)
+}
+
+// Gte matches documents whose field is greater than or equal to value
+func Gte(field string, value interface{}) Selector {
+	return fieldOp(field, "$gte", value)
+}
+
+// Lt matches documents whose field is less than value
+func Lt(field string, value interface{}) Selector {
+	return fieldOp(field, "$lt", value)
+}
+
+// Lte matches documents whose field is less than or equal to value
+func Lte(field string, value interface{}) Selector {
+	return fieldOp(field, "$lte", value)
+}
+
+// In matches documents whose field equals one of values
+func In(field string, values ...interface{}) Selector {
+	return fieldOp(field, "$in", values)
+}
+
+// Nin matches documents whose field equals none of values
+func Nin(field string, values ...interface{}) Selector {
+	return fieldOp(field, "$nin", values)
+}
+
+// All matches array fields containing all of values
+func All(field string, values ...interface{}) Selector {
+	return fieldOp(field, "$all", values)
+}
+
+// Exists matches documents that have (or, if exists is false, lack) the field
+func Exists(field string, exists bool) Selector {
+	return fieldOp(field, "$exists", exists)
+}
+
+// Type matches documents whose field has the given JSON type: "null",
+// "boolean", "number", "string", "array" or "object"
+func Type(field, jsonType string) Selector {
+	return fieldOp(field, "$type", jsonType)
+}
+
+// Regex matches string fields against a regular expression
+func Regex(field, pattern string) Selector {
+	return fieldOp(field, "$regex", pattern)
+}
+
+// Size matches array fields with the given length
+func Size(field string, length int) Selector {
+	return fieldOp(field, "$size", length)
+}
+
+// Mod matches numeric fields where field % divisor == remainder
+func Mod(field string, divisor, remainder int) Selector {
+	return fieldOp(field, "$mod", []int{divisor, remainder})
+}
+
+// ElemMatch matches array fields with at least one element matching selector
+func ElemMatch(field string, selector Selector) Selector {
+	return fieldOp(field, "$elemMatch", selector)
+}
+
+// AllMatch matches array fields whose elements all match selector
+func AllMatch(field string, selector Selector) Selector {
+	return fieldOp(field, "$allMatch", selector)
+}
+
+// And matches documents matching all of selectors
+func And(selectors ...Selector) Selector {
+	return Selector{"$and": selectors}
+}
+
+// Or matches documents matching any of selectors
+func Or(selectors ...Selector) Selector {
+	return Selector{"$or": selectors}
+}
+
+// Nor matches documents matching none of selectors
+func Nor(selectors ...Selector) Selector {
+	return Selector{"$nor": selectors}
+}
+
+// Not matches documents not matching selector
+func Not(selector Selector) Selector {
+	return Selector{"$not": selector}
+}
+
+func fieldOp(field, op string, value interface{}) Selector {
+	return Selector{field: Selector{op: value}}
+}
+
+// FindBuilder helps build Mango queries
+type FindBuilder struct {
+	conditions []Selector
+	query      FindQuery
+}
+
+// NewFindQuery creates a Mango query builder matching all of selectors
+func (db *Database) NewFindQuery(selectors ...Selector) *FindBuilder {
+	return &FindBuilder{conditions: selectors}
+}
+
+// Where adds conditions that documents must also match
+func (fb *FindBuilder) Where(selectors ...Selector) *FindBuilder {
+	fb.conditions = append(fb.conditions, selectors...)
+	return fb
+}
+
+// Fields limits the returned fields
+func (fb *FindBuilder) Fields(fields ...string) *FindBuilder {
+	fb.query.Fields = fields
+	return fb
+}
+
+// Sort adds a sort criterion; direction is "asc" or "desc"
+func (fb *FindBuilder) Sort(field, direction string) *FindBuilder {
+	fb.query.Sort = append(fb.query.Sort, SortField{Field: field, Direction: direction})
+	return fb
+}
+
+// Limit sets the maximum number of results
+func (fb *FindBuilder) Limit(limit int) *FindBuilder {
+	fb.query.Limit = limit
+	return fb
+}
+
+// Skip sets the number of results to skip
+func (fb *FindBuilder) Skip(skip int) *FindBuilder {
+	fb.query.Skip = skip
+	return fb
+}
+
+// Bookmark continues from a previous result page
+func (fb *FindBuilder) Bookmark(bookmark string) *FindBuilder {
+	fb.query.Bookmark = bookmark
+	return fb
+}
+
+// UseIndex selects the index to use: a design document, optionally followed by an index name
+func (fb *FindBuilder) UseIndex(designDoc string, name ...string) *FindBuilder {
+	if len(name) > 0 {
+		fb.query.UseIndex = []string{designDoc, name[0]}
+	} else {
+		fb.query.UseIndex = designDoc
+	}
+	return fb
+}
+
+// ExecutionStats includes execution statistics in the result
+func (fb *FindBuilder) ExecutionStats(include bool) *FindBuilder {
+	fb.query.ExecutionStats = include
+	return fb
+}
+
+// Query returns the built query
+func (fb *FindBuilder) Query() *FindQuery {
+	query := fb.query
+
+	switch len(fb.conditions) {
+	case 0:
+		query.Selector = Selector{}
+	case 1:
+		query.Selector = fb.conditions[0]
+	default:
+		query.Selector = And(fb.conditions...)
+	}
+
+	return &query
+}
+
+// Execute runs the query
+func (fb *FindBuilder) Execute(ctx context.Context, db *Database) (*FindResult, error) {
+	return db.Find(ctx, fb.Query())
+}
+
+// Explain returns the query plan without running the query
+func (fb *FindBuilder) Explain(ctx context.Context, db *Database) (*ExplainResult, error) {
+	return db.Explain(ctx, fb.Query())
+}
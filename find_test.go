@@ -0,0 +1,141 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_find", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"docs":[{"_id":"u1","_rev":"1-a","name":"alice"}],"bookmark":"g1","execution_stats":{"total_docs_examined":3,"results_returned":1}}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	result, err := db.Find(context.Background(), &FindQuery{
+		Selector:       Selector{"type": "user", "age": Selector{"$gt": 21}},
+		Fields:         []string{"_id", "name"},
+		Sort:           []SortField{{Field: "name"}, {Field: "age", Direction: "desc"}},
+		Limit:          10,
+		ExecutionStats: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"type": "user", "age": map[string]interface{}{"$gt": float64(21)}}, body["selector"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "asc"}, map[string]interface{}{"age": "desc"}}, body["sort"])
+	assert.Equal(t, float64(10), body["limit"])
+	assert.Equal(t, true, body["execution_stats"])
+
+	require.Len(t, result.Docs, 1)
+	assert.Equal(t, "u1", result.Docs[0].ID)
+	assert.Equal(t, "alice", result.Docs[0].Data["name"])
+	assert.Equal(t, "g1", result.Bookmark)
+	require.NotNil(t, result.ExecutionStats)
+	assert.Equal(t, int64(3), result.ExecutionStats.TotalDocsExamined)
+}
+
+func TestExplain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/movies/_explain", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"dbname": "movies",
+			"index": {"ddoc": "_design/by-year", "name": "year-index", "type": "json",
+				"partitioned": "db_default", "def": {"fields": [{"year": "asc"}]}},
+			"partitioned": false,
+			"selector": {"year": {"$gt": 2010}},
+			"opts": {"use_index": [], "bookmark": "nil"},
+			"limit": 25, "skip": 0, "fields": "all_fields",
+			"mrargs": {"start_key": [2010], "end_key": ["<MAX>"], "include_docs": true},
+			"covering": false,
+			"index_candidates": [{"index": {"ddoc": null, "name": "_all_docs", "type": "special", "def": {"fields": [{"_id": "asc"}]}},
+				"analysis": {"usable": true, "reasons": [{"name": "unfavored_type"}], "ranking": 1, "covering": null}}]
+		}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("movies")
+	plan, err := db.Explain(context.Background(), &FindQuery{Selector: Selector{"year": map[string]interface{}{"$gt": 2010}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "_design/by-year", plan.Index.DDoc)
+	assert.Equal(t, "year-index", plan.Index.Name)
+	assert.False(t, plan.FullScan())
+	assert.Equal(t, "all_fields", plan.Fields)
+	assert.Equal(t, true, plan.MRArgs["include_docs"])
+	require.Len(t, plan.IndexCandidates, 1)
+	assert.Equal(t, "unfavored_type", plan.IndexCandidates[0].Analysis.Reasons[0].Name)
+}
+
+func TestCreateIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/movies/_index", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"index": map[string]interface{}{
+				"fields":                  []interface{}{"type", "year"},
+				"partial_filter_selector": map[string]interface{}{"draft": map[string]interface{}{"$ne": true}},
+			},
+			"ddoc": "movies",
+			"name": "by-type-year",
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"result":"created","id":"_design/movies","name":"by-type-year"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("movies")
+	result, err := db.CreateIndex(context.Background(), &MangoIndex{
+		Fields:        []string{"type", "year"},
+		PartialFilter: Ne("draft", true),
+		DesignDoc:     "movies",
+		Name:          "by-type-year",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CreateIndexResult{Result: "created", ID: "_design/movies", Name: "by-type-year"}, result)
+}
+
+func TestFindBuilder(t *testing.T) {
+	query := (&Database{}).NewFindQuery(Eq("type", "user")).
+		Where(Or(In("role", "admin", "editor"), ElemMatch("tags", Selector{"$regex": "^go"})), Gte("age", 21)).
+		Fields("_id", "name").
+		Sort("age", "desc").
+		Limit(10).
+		UseIndex("users", "by-age").
+		Query()
+
+	data, err := json.Marshal(query)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"selector": {"$and": [
+			{"type": {"$eq": "user"}},
+			{"$or": [{"role": {"$in": ["admin", "editor"]}}, {"tags": {"$elemMatch": {"$regex": "^go"}}}]},
+			{"age": {"$gte": 21}}
+		]},
+		"fields": ["_id", "name"],
+		"sort": [{"age": "desc"}],
+		"limit": 10,
+		"use_index": ["users", "by-age"]
+	}`, string(data))
+
+	single := (&Database{}).NewFindQuery(Exists("email", true)).Query()
+	assert.Equal(t, Selector{"email": Selector{"$exists": true}}, single.Selector)
+}
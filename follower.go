@@ -0,0 +1,253 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EventSink receives changes from a ChangesFollower. Implementations typically
+// publish to a message broker such as Kafka, NATS or SQS.
+type EventSink interface {
+	Publish(ctx context.Context, change *Change) error
+}
+
+// EventSinkFunc adapts a function to the EventSink interface
+type EventSinkFunc func(ctx context.Context, change *Change) error
+
+// Publish implements EventSink
+func (f EventSinkFunc) Publish(ctx context.Context, change *Change) error {
+	return f(ctx, change)
+}
+
+// FlushingEventSink is an EventSink that buffers published changes, e.g. to
+// send them in batches. The follower calls Flush before every checkpoint;
+// it must return only once all changes published so far are stored.
+type FlushingEventSink interface {
+	EventSink
+	Flush(ctx context.Context) error
+}
+
+// FollowerOptions configures a ChangesFollower
+type FollowerOptions struct {
+	// ChangesOptions selects the changes to follow, e.g. with a Selector or
+	// Filter. Feed, Since, Limit and Timeout are managed by the follower.
+	ChangesOptions
+
+	CheckpointKey string        // defaults to "follower-<db>"
+	BatchSize     int           // changes per request and checkpoint, defaults to 100
+	PollTimeout   time.Duration // longpoll wait, defaults to 20 seconds; keep below the client timeout
+
+	// Continuous follows the continuous feed instead of polling. A checkpoint
+	// is then saved after BatchSize changes or CheckpointInterval (default
+	// 5s), whichever comes first.
+	Continuous         bool
+	CheckpointInterval time.Duration
+
+	// OnCheckpoint is called after each checkpoint with the number of
+	// changes it covers
+	OnCheckpoint func(changes int, seq string)
+}
+
+// ChangesFollower tails a database's changes feed and publishes every change
+// to an EventSink. Delivery is at-least-once: the checkpoint only advances
+// past changes the sink accepted, so a change may be published again after a
+// failure or restart, but is never skipped.
+type ChangesFollower struct {
+	db    *Database
+	sink  EventSink
+	store CheckpointStore
+	opts  FollowerOptions
+}
+
+// NewChangesFollower creates a follower publishing db's changes to sink,
+// checkpointing progress in store, or in a _local document if store is nil
+func (db *Database) NewChangesFollower(sink EventSink, store CheckpointStore, opts *FollowerOptions) *ChangesFollower {
+	f := &ChangesFollower{
+		db:    db,
+		sink:  sink,
+		store: store,
+	}
+
+	if opts != nil {
+		f.opts = *opts
+	}
+	if f.store == nil {
+		f.store = NewLocalCheckpointStore(db)
+	}
+	if f.opts.CheckpointKey == "" {
+		f.opts.CheckpointKey = "follower-" + db.name
+	}
+	if f.opts.BatchSize <= 0 {
+		f.opts.BatchSize = 100
+	}
+	if f.opts.PollTimeout <= 0 {
+		f.opts.PollTimeout = 20 * time.Second
+	}
+	if f.opts.CheckpointInterval <= 0 {
+		f.opts.CheckpointInterval = 5 * time.Second
+	}
+
+	return f
+}
+
+// ChangeHandler processes a single change
+type ChangeHandler func(ctx context.Context, change *Change) error
+
+// NewChangesProcessor creates a follower of db's continuous changes feed
+// calling handler for every change, the building block of feed-driven
+// workers. Progress is checkpointed in store, or in a _local document if
+// store is nil, so the worker resumes where it left off after a restart.
+func (db *Database) NewChangesProcessor(handler ChangeHandler, store CheckpointStore, opts *FollowerOptions) *ChangesFollower {
+	var o FollowerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.CheckpointKey == "" {
+		o.CheckpointKey = "processor-" + db.name
+	}
+	o.Continuous = true
+
+	return db.NewChangesFollower(EventSinkFunc(handler), store, &o)
+}
+
+// followerState tracks the published and the checkpointed sequence
+type followerState struct {
+	saved     string
+	processed string
+	pending   int
+}
+
+// Run follows the feed until ctx is cancelled or an error occurs. Progress
+// made before the error is checkpointed, so Run can simply be called again.
+func (f *ChangesFollower) Run(ctx context.Context) error {
+	since, err := f.store.Load(ctx, f.opts.CheckpointKey)
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %w", err)
+	}
+
+	state := &followerState{saved: since, processed: since}
+	if f.opts.Continuous {
+		err = f.runContinuous(ctx, state)
+	} else {
+		err = f.runLongpoll(ctx, state)
+	}
+
+	// The final checkpoint must be saved even though ctx may be cancelled
+	return errors.Join(err, f.checkpoint(context.WithoutCancel(ctx), state))
+}
+
+// runLongpoll polls for batches of changes, checkpointing after each
+func (f *ChangesFollower) runLongpoll(ctx context.Context, state *followerState) error {
+	for {
+		changesOpts := f.opts.ChangesOptions
+		changesOpts.Feed = "longpoll"
+		changesOpts.Since = state.processed
+		changesOpts.Limit = f.opts.BatchSize
+		changesOpts.Timeout = int(f.opts.PollTimeout / time.Millisecond)
+
+		changes, err := f.db.GetChanges(ctx, &changesOpts)
+		if err != nil {
+			return err
+		}
+
+		for i := range changes.Results {
+			if err := f.publish(ctx, state, &changes.Results[i]); err != nil {
+				return err
+			}
+		}
+
+		// last_seq also skips changes left out by a filter
+		if changes.LastSeq != "" {
+			state.processed = changes.LastSeq.String()
+		}
+
+		if err := f.checkpoint(ctx, state); err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// runContinuous consumes the continuous feed, which reconnects by itself,
+// checkpointing every BatchSize changes or CheckpointInterval
+func (f *ChangesFollower) runContinuous(ctx context.Context, state *followerState) error {
+	feedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changesOpts := f.opts.ChangesOptions
+	changesOpts.Feed = "continuous"
+	changesOpts.Since = state.processed
+	feed := f.db.ChangesFeed(feedCtx, &ChangesFeedOptions{ChangesOptions: changesOpts})
+
+	ticker := time.NewTicker(f.opts.CheckpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case change, ok := <-feed.Changes():
+			if !ok {
+				if err := feed.Err(); err != nil {
+					return err
+				}
+				return ctx.Err()
+			}
+
+			if err := f.publish(ctx, state, &change); err != nil {
+				return err
+			}
+			if state.pending >= f.opts.BatchSize {
+				if err := f.checkpoint(ctx, state); err != nil {
+					return err
+				}
+			}
+
+		case <-ticker.C:
+			if err := f.checkpoint(ctx, state); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// publish hands a change to the sink and records it as processed
+func (f *ChangesFollower) publish(ctx context.Context, state *followerState, change *Change) error {
+	if err := f.sink.Publish(ctx, change); err != nil {
+		return fmt.Errorf("publish change %s: %w", change.ID, err)
+	}
+
+	state.processed = change.Seq.String()
+	state.pending++
+	return nil
+}
+
+// checkpoint flushes a buffering sink and saves the processed sequence
+func (f *ChangesFollower) checkpoint(ctx context.Context, state *followerState) error {
+	if state.processed == state.saved {
+		return nil
+	}
+
+	if flusher, ok := f.sink.(FlushingEventSink); ok {
+		if err := flusher.Flush(ctx); err != nil {
+			// The buffered changes are unconfirmed, so they are not
+			// checkpointed, not even by the final checkpoint of Run
+			n := state.pending
+			state.processed, state.pending = state.saved, 0
+			return fmt.Errorf("send %d changes after %s: %w", n, state.saved, err)
+		}
+	}
+
+	if err := f.store.Save(ctx, f.opts.CheckpointKey, state.processed); err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+
+	if f.opts.OnCheckpoint != nil {
+		f.opts.OnCheckpoint(state.pending, state.processed)
+	}
+	state.saved, state.pending = state.processed, 0
+	return nil
+}
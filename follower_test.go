@@ -0,0 +1,190 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesFollower_AtLeastOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/_changes", r.URL.Path)
+
+		results := []map[string]interface{}{
+			{"seq": "1-a", "id": "doc-1", "changes": []map[string]string{{"rev": "1-x"}}},
+			{"seq": "2-b", "id": "doc-2", "changes": []map[string]string{{"rev": "1-y"}}},
+		}
+		if r.URL.Query().Get("since") == "1-a" {
+			results = results[1:]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "last_seq": "2-b"})
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	store := NewMemoryCheckpointStore()
+	sinkErr := errors.New("broker unavailable")
+
+	var published []string
+	failing := EventSinkFunc(func(_ context.Context, change *Change) error {
+		if change.ID == "doc-2" {
+			return sinkErr
+		}
+		published = append(published, change.ID)
+		return nil
+	})
+
+	err := db.NewChangesFollower(failing, store, &FollowerOptions{CheckpointKey: "test"}).Run(context.Background())
+	require.ErrorIs(t, err, sinkErr)
+	assert.Equal(t, []string{"doc-1"}, published)
+
+	seq, err := store.Load(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", seq)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	recovering := EventSinkFunc(func(_ context.Context, change *Change) error {
+		published = append(published, change.ID)
+		cancel()
+		return nil
+	})
+
+	err = db.NewChangesFollower(recovering, store, &FollowerOptions{CheckpointKey: "test"}).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"doc-1", "doc-2"}, published)
+}
+
+func TestChangesProcessor_ResumesFromCheckpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "continuous", r.URL.Query().Get("feed"))
+		w.Header().Set("Content-Type", "application/json")
+
+		lines := []string{
+			`{"seq":"1-a","id":"doc-1","changes":[{"rev":"1-x"}]}`,
+			`{"seq":"2-b","id":"doc-2","changes":[{"rev":"1-y"}]}`,
+		}
+		if r.URL.Query().Get("since") == "1-a" {
+			lines = lines[1:]
+		}
+		for _, line := range lines {
+			_, _ = w.Write([]byte(line + "\n"))
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	store := NewMemoryCheckpointStore()
+	opts := &FollowerOptions{CheckpointKey: "test"}
+	handlerErr := errors.New("downstream unavailable")
+
+	var handled []string
+	err := db.NewChangesProcessor(func(_ context.Context, change *Change) error {
+		if change.ID == "doc-2" {
+			return handlerErr
+		}
+		handled = append(handled, change.ID)
+		return nil
+	}, store, opts).Run(context.Background())
+	require.ErrorIs(t, err, handlerErr)
+
+	seq, err := store.Load(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", seq)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = db.NewChangesProcessor(func(_ context.Context, change *Change) error {
+		handled = append(handled, change.ID)
+		cancel()
+		return nil
+	}, store, opts).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"doc-1", "doc-2"}, handled)
+
+	seq, err = store.Load(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", seq)
+}
+
+// batchingSink buffers changes until Flush, like a batching broker producer
+type batchingSink struct {
+	buffered []string
+	flushed  []string
+	failures int
+}
+
+func (s *batchingSink) Publish(_ context.Context, change *Change) error {
+	s.buffered = append(s.buffered, change.ID)
+	return nil
+}
+
+func (s *batchingSink) Flush(context.Context) error {
+	if s.failures > 0 {
+		s.failures--
+		s.buffered = nil
+		return errors.New("broker unavailable")
+	}
+	s.flushed = append(s.flushed, s.buffered...)
+	s.buffered = nil
+	return nil
+}
+
+func TestChangesFollower_FlushingSink(t *testing.T) {
+	local := map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/db/_local/follower-db" && r.Method == http.MethodGet:
+			if len(local) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(local)
+		case r.URL.Path == "/db/_local/follower-db":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&local))
+			_, _ = w.Write([]byte(`{"ok":true,"id":"_local/follower-db","rev":"0-1"}`))
+		case r.URL.Path == "/db/_changes":
+			// The filter left out a change after doc-2, so last_seq is ahead
+			if r.URL.Query().Get("since") == "3-c" {
+				_, _ = w.Write([]byte(`{"results":[],"last_seq":"3-c"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[
+				{"seq":"1-a","id":"doc-1","changes":[{"rev":"1-x"}]},
+				{"seq":"2-b","id":"doc-2","changes":[{"rev":"1-y"}]}],"last_seq":"3-c"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	sink := &batchingSink{failures: 1}
+
+	// A failed flush leaves the checkpoint where it was
+	err := db.NewChangesFollower(sink, nil, nil).Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broker unavailable")
+	assert.Empty(t, local)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var checkpoints []string
+	err = db.NewChangesFollower(sink, nil, &FollowerOptions{
+		OnCheckpoint: func(changes int, seq string) {
+			checkpoints = append(checkpoints, fmt.Sprintf("%d@%s", changes, seq))
+			cancel()
+		},
+	}).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"doc-1", "doc-2"}, sink.flushed)
+	assert.Equal(t, []string{"2@3-c"}, checkpoints)
+	assert.Equal(t, "3-c", local["seq"])
+}
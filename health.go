@@ -0,0 +1,86 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UpStatus is the response of the _up endpoint
+type UpStatus struct {
+	Status string                 `json:"status"` // "ok", "nolb" or "maintenance_mode"
+	Seeds  map[string]interface{} `json:"seeds,omitempty"`
+}
+
+// Ping checks that the server is reachable and answering requests
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		Head("/")
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return &Error{StatusCode: resp.StatusCode(), Type: "ping", Reason: resp.Status()}
+	}
+
+	return nil
+}
+
+// Up reports whether the node is ready to serve requests. A node in
+// maintenance mode answers with an error whose Type is the status, along
+// with the status itself.
+func (c *Client) Up(ctx context.Context) (*UpStatus, error) {
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		Get("/_up")
+
+	if err != nil {
+		return nil, err
+	}
+
+	var status UpStatus
+	if err := json.Unmarshal(resp.Body(), &status); err != nil && !resp.IsError() {
+		return nil, fmt.Errorf("decode _up response: %w", err)
+	}
+
+	if resp.IsError() {
+		if status.Status == "" {
+			return nil, c.parseError(resp)
+		}
+		return &status, &Error{StatusCode: resp.StatusCode(), Type: status.Status, Reason: "node is not up"}
+	}
+
+	return &status, nil
+}
+
+// WaitUntilReady polls _up every interval until the node reports ok, e.g.
+// while a service starts next to CouchDB. Connection errors and nodes in
+// maintenance mode are retried; when ctx ends the last failure is returned
+// along with the context error.
+func (c *Client) WaitUntilReady(ctx context.Context, interval time.Duration) error {
+	var last error
+	err := poll(ctx, interval, func() (bool, error) {
+		status, err := c.Up(ctx)
+		if err != nil {
+			// A check cut short by ctx says nothing about the node
+			if ctx.Err() == nil {
+				last = err
+			}
+			return false, nil
+		}
+		if status.Status != "ok" {
+			last = fmt.Errorf("node status is %s", status.Status)
+			return false, nil
+		}
+		return true, nil
+	})
+
+	if err != nil && last != nil {
+		return fmt.Errorf("%w (last check: %w)", err, last)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecks(t *testing.T) {
+	var checks atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/":
+		case r.URL.Path == "/_up":
+			if checks.Add(1) < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"status":"maintenance_mode"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"ok","seeds":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	require.NoError(t, client.Ping(ctx))
+
+	status, err := client.Up(ctx)
+	assert.True(t, IsNotFound(err))
+	require.NotNil(t, status)
+	assert.Equal(t, "maintenance_mode", status.Status)
+
+	require.NoError(t, client.WaitUntilReady(ctx, 10*time.Millisecond))
+	assert.Equal(t, int32(3), checks.Load())
+
+	status, err = client.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", status.Status)
+}
+
+func TestWaitUntilReady_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":"maintenance_mode"}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := NewClient(server.URL, nil).WaitUntilReady(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, IsNotFound(err))
+}
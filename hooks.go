@@ -0,0 +1,103 @@
+package couchdb
+
+import (
+	"slices"
+	"time"
+)
+
+// WriteHook modifies or rejects a document before Put, Update or Bulk sends
+// it. The document is given as its JSON object; changes are sent but not
+// copied back into the caller's value.
+type WriteHook func(doc map[string]interface{}) error
+
+// WithWriteHook returns a handle on the same database that runs hook on
+// every document it writes, after the hooks already registered and before
+// its validator
+func (db *Database) WithWriteHook(hook WriteHook) *Database {
+	handle := *db
+	handle.writeHooks = append(slices.Clip(db.writeHooks), hook)
+	return &handle
+}
+
+// prepareDoc turns a document into the body to send: tagged struct fields
+// are mapped to _id and _rev, then the write hooks and the validator run.
+// Index is the position in a bulk request, or -1; extra hooks run after
+// the handle's.
+func (db *Database) prepareDoc(doc interface{}, index int, extra ...WriteHook) (interface{}, error) {
+	body, err := documentBody(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := append(slices.Clip(db.writeHooks), extra...)
+	if len(hooks) > 0 {
+		var m map[string]interface{}
+		if err := convertDoc(body, &m); err != nil {
+			return nil, err
+		}
+		for _, hook := range hooks {
+			if err := hook(m); err != nil {
+				return nil, err
+			}
+		}
+		body = m
+	}
+
+	if err := db.validate(body, index); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// TimestampOptions configures Timestamps
+type TimestampOptions struct {
+	CreatedField string // defaults to "created_at"
+	UpdatedField string // defaults to "updated_at"
+	Layout       string // time format, defaults to time.RFC3339Nano
+
+	// Now returns the current time, defaults to time.Now in UTC
+	Now func() time.Time
+}
+
+// Timestamps returns a WriteHook that sets the created field on documents
+// without a revision, unless already present, and the updated field on
+// every write. Deletions are left alone.
+func Timestamps(opts *TimestampOptions) WriteHook {
+	o := TimestampOptions{CreatedField: "created_at", UpdatedField: "updated_at", Layout: time.RFC3339Nano}
+	if opts != nil {
+		if opts.CreatedField != "" {
+			o.CreatedField = opts.CreatedField
+		}
+		if opts.UpdatedField != "" {
+			o.UpdatedField = opts.UpdatedField
+		}
+		if opts.Layout != "" {
+			o.Layout = opts.Layout
+		}
+		o.Now = opts.Now
+	}
+	if o.Now == nil {
+		o.Now = func() time.Time { return time.Now().UTC() }
+	}
+
+	return func(doc map[string]interface{}) error {
+		if deleted(doc) {
+			return nil
+		}
+
+		now := o.Now().Format(o.Layout)
+		if rev, _ := doc["_rev"].(string); rev == "" {
+			if _, ok := doc[o.CreatedField]; !ok {
+				doc[o.CreatedField] = now
+			}
+		}
+		doc[o.UpdatedField] = now
+		return nil
+	}
+}
+
+// WithTimestamps returns a handle on the same database that stamps the
+// documents it writes, see Timestamps
+func (db *Database) WithTimestamps(opts *TimestampOptions) *Database {
+	return db.WithWriteHook(Timestamps(opts))
+}
@@ -0,0 +1,83 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimestamps(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("db")
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plain := NewClient(server.URL, nil).DB("db")
+	db := plain.WithTimestamps(&TimestampOptions{
+		UpdatedField: "modified",
+		Layout:       time.DateTime,
+		Now:          func() time.Time { return now },
+	})
+	ctx := context.Background()
+
+	created, err := db.Put(ctx, map[string]interface{}{"_id": "a"})
+	require.NoError(t, err)
+
+	doc, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01 12:00:00", doc.Data["created_at"])
+	assert.Equal(t, "2024-05-01 12:00:00", doc.Data["modified"])
+
+	now = now.Add(time.Hour)
+	_, err = db.Update(ctx, "a", map[string]interface{}{"_rev": created.Rev, "created_at": doc.Data["created_at"]})
+	require.NoError(t, err)
+
+	doc, err = db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01 12:00:00", doc.Data["created_at"])
+	assert.Equal(t, "2024-05-01 13:00:00", doc.Data["modified"])
+
+	_, err = db.Bulk(ctx, []interface{}{map[string]interface{}{"_id": "b"}, &taggedOrder{Key: "c"}})
+	require.NoError(t, err)
+	doc, err = db.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-01 13:00:00", doc.Data["created_at"])
+
+	// Other handles are unaffected
+	_, err = plain.Put(ctx, map[string]interface{}{"_id": "d"})
+	require.NoError(t, err)
+	doc, err = plain.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.NotContains(t, doc.Data, "created_at")
+}
+
+func TestWithWriteHook(t *testing.T) {
+	var order []string
+	hook := func(name string) WriteHook {
+		return func(doc map[string]interface{}) error {
+			order = append(order, name)
+			if name == "a" && doc["reject"] == true {
+				return errors.New("rejected")
+			}
+			return nil
+		}
+	}
+
+	base := NewClient("http://localhost:1", nil).DB("db").WithWriteHook(hook("first"))
+	a := base.WithWriteHook(hook("a"))
+	_ = base.WithWriteHook(hook("b"))
+
+	_, err := a.Put(context.Background(), map[string]interface{}{"reject": true})
+	assert.EqualError(t, err, "rejected")
+	assert.Equal(t, []string{"first", "a"}, order)
+
+	ts := Timestamps(nil)
+	tombstone := map[string]interface{}{"_id": "x", "_rev": "1-a", "_deleted": true}
+	require.NoError(t, ts(tombstone))
+	assert.NotContains(t, tombstone, "updated_at")
+}
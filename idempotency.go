@@ -0,0 +1,129 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Idempotency defaults
+const (
+	DefaultIdempotencyField     = "idempotency_key"
+	DefaultIdempotencyDesignDoc = "idempotency"
+	DefaultIdempotencyView      = "by_key"
+)
+
+// IdempotencyOptions configures how idempotency tokens are stored and looked up
+type IdempotencyOptions struct {
+	Field     string // document field holding the token
+	DesignDoc string // design document containing the lookup view
+	ViewName  string // view emitting the token as key
+}
+
+func (o *IdempotencyOptions) withDefaults() *IdempotencyOptions {
+	opts := IdempotencyOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Field == "" {
+		opts.Field = DefaultIdempotencyField
+	}
+	if opts.DesignDoc == "" {
+		opts.DesignDoc = DefaultIdempotencyDesignDoc
+	}
+	if opts.ViewName == "" {
+		opts.ViewName = DefaultIdempotencyView
+	}
+	return &opts
+}
+
+// EnsureIdempotencyIndex creates the design document used to look up idempotency tokens
+func (db *Database) EnsureIdempotencyIndex(ctx context.Context, opts *IdempotencyOptions) error {
+	opts = opts.withDefaults()
+
+	fieldBytes, _ := json.Marshal(opts.Field)
+	mapFn := fmt.Sprintf("function(doc) { if (doc[%s]) { emit(doc[%s], null); } }", fieldBytes, fieldBytes)
+
+	return db.ensureView(ctx, opts.DesignDoc, opts.ViewName, &View{Map: mapFn})
+}
+
+// FindByIdempotencyKey returns the document previously created with the given token, or nil if none exists
+func (db *Database) FindByIdempotencyKey(ctx context.Context, key string, opts *IdempotencyOptions) (*Document, error) {
+	opts = opts.withDefaults()
+
+	result, err := db.View(ctx, opts.DesignDoc, opts.ViewName, &ViewOptions{
+		Key:   key,
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Rows) == 0 {
+		return nil, nil
+	}
+
+	return db.Get(ctx, result.Rows[0].ID)
+}
+
+// PutIdempotent creates a document stamped with an idempotency token. If a document
+// carrying the same token already exists, or the write fails ambiguously but the
+// document was in fact stored, the existing document is returned instead of a duplicate.
+func (db *Database) PutIdempotent(ctx context.Context, key string, doc interface{}, opts *IdempotencyOptions) (*Document, error) {
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key is required")
+	}
+	opts = opts.withDefaults()
+
+	existing, err := db.FindByIdempotencyKey(ctx, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return &Document{ID: existing.ID, Rev: existing.Rev}, nil
+	}
+
+	data, err := toMap(doc)
+	if err != nil {
+		return nil, err
+	}
+	data[opts.Field] = key
+
+	result, err := db.Put(ctx, data)
+	if err == nil {
+		return result, nil
+	}
+
+	// A CouchDB error means the server rejected the write; anything else is
+	// ambiguous and the document may have been stored before the failure.
+	var couchErr *Error
+	if errors.As(err, &couchErr) {
+		return nil, err
+	}
+
+	existing, lookupErr := db.FindByIdempotencyKey(ctx, key, opts)
+	if lookupErr != nil || existing == nil {
+		return nil, err
+	}
+
+	return &Document{ID: existing.ID, Rev: existing.Rev}, nil
+}
+
+// toMap converts an arbitrary document value into a generic JSON object
+func toMap(doc interface{}) (map[string]interface{}, error) {
+	docBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(docBytes, &data); err != nil {
+		return nil, fmt.Errorf("document must encode to a JSON object: %w", err)
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	return data, nil
+}
@@ -0,0 +1,56 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutIdempotent(t *testing.T) {
+	var created map[string]interface{}
+	stored := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/db/_design/idempotency/_view/by_key":
+			rows := []ViewRow{}
+			if stored {
+				rows = append(rows, ViewRow{ID: "doc-1", Key: "token-1"})
+			}
+			_ = json.NewEncoder(w).Encode(ViewResult{Rows: rows})
+		case r.Method == "POST" && r.URL.Path == "/db":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			stored = true
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": "doc-1", "rev": "1-a"})
+		case r.Method == "GET" && r.URL.Path == "/db/doc-1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "doc-1", "_rev": "1-a"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	doc, err := db.PutIdempotent(ctx, "token-1", map[string]interface{}{"name": "test"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "doc-1", doc.ID)
+	assert.Equal(t, "token-1", created[DefaultIdempotencyField])
+	assert.Equal(t, "test", created["name"])
+
+	created = nil
+	doc, err = db.PutIdempotent(ctx, "token-1", map[string]interface{}{"name": "test"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "doc-1", doc.ID)
+	assert.Equal(t, "1-a", doc.Rev)
+	assert.Nil(t, created, "retry must not create a second document")
+}
@@ -0,0 +1,164 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator creates the IDs of new documents, see WithIDGenerator
+type IDGenerator interface {
+	NewID(ctx context.Context) (string, error)
+}
+
+// IDGeneratorFunc adapts a function to IDGenerator
+type IDGeneratorFunc func(ctx context.Context) (string, error)
+
+// NewID implements IDGenerator
+func (f IDGeneratorFunc) NewID(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithIDGenerator returns a handle on the same database that gives
+// documents written by Put or Bulk without an _id one from gen. Without a
+// generator CouchDB assigns random UUIDs, which are spread over the whole
+// ID index; ordered IDs such as ULIDs keep new documents close together
+// and make inserts and replication cheaper.
+func (db *Database) WithIDGenerator(gen IDGenerator) *Database {
+	handle := *db
+	handle.idGenerator = gen
+	return &handle
+}
+
+// assignID returns the write hook giving new documents an ID, or nil
+// without a generator
+func (db *Database) assignID(ctx context.Context) []WriteHook {
+	if db.idGenerator == nil {
+		return nil
+	}
+
+	return []WriteHook{func(doc map[string]interface{}) error {
+		if id, _ := doc["_id"].(string); id != "" {
+			return nil
+		}
+		id, err := db.idGenerator.NewID(ctx)
+		if err != nil {
+			return fmt.Errorf("generate document ID: %w", err)
+		}
+		doc["_id"] = id
+		return nil
+	}}
+}
+
+// UUIDv4 returns a generator of random version 4 UUIDs, created locally and
+// written as 32 hex digits like the UUIDs of CouchDB
+func UUIDv4() IDGenerator {
+	return IDGeneratorFunc(func(context.Context) (string, error) {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return "", err
+		}
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		return hex.EncodeToString(b[:]), nil
+	})
+}
+
+// crockford is the Base32 alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID returns a generator of ULIDs: 26 characters that sort by creation
+// time, to the millisecond, followed by 80 random bits. IDs created within
+// the same millisecond increment the random part, so IDs from one
+// generator always sort in creation order.
+func ULID() IDGenerator {
+	var mu sync.Mutex
+	var lastMS uint64
+	var entropy [10]byte
+
+	return IDGeneratorFunc(func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ms := uint64(time.Now().UnixMilli())
+		if ms > lastMS {
+			lastMS = ms
+			if _, err := rand.Read(entropy[:]); err != nil {
+				return "", err
+			}
+		} else if !increment(entropy[:]) {
+			// The random part overflowed, borrow the next millisecond
+			lastMS++
+			if _, err := rand.Read(entropy[:]); err != nil {
+				return "", err
+			}
+		}
+
+		var raw [16]byte
+		binary.BigEndian.PutUint16(raw[0:2], uint16(lastMS>>32))
+		binary.BigEndian.PutUint32(raw[2:6], uint32(lastMS))
+		copy(raw[6:], entropy[:])
+		return encodeULID(raw), nil
+	})
+}
+
+// increment adds one to a big-endian number, reporting false on overflow
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID writes 128 bits as 26 Crockford Base32 characters
+func encodeULID(raw [16]byte) string {
+	hi := binary.BigEndian.Uint64(raw[0:8])
+	lo := binary.BigEndian.Uint64(raw[8:16])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// Sequential returns a generator of prefix followed by 14 hex digits of the
+// time in microseconds, strictly increasing for the generator, and 8 random
+// hex digits keeping IDs from different processes apart. The IDs sort by
+// creation time, and the prefix groups documents of one kind, e.g.
+// Sequential("order:").
+func Sequential(prefix string) IDGenerator {
+	var mu sync.Mutex
+	var last int64
+
+	return IDGeneratorFunc(func(context.Context) (string, error) {
+		var suffix [4]byte
+		if _, err := rand.Read(suffix[:]); err != nil {
+			return "", err
+		}
+
+		mu.Lock()
+		now := max(time.Now().UnixMicro(), last+1)
+		last = now
+		mu.Unlock()
+
+		return fmt.Sprintf("%s%014x%s", prefix, now, hex.EncodeToString(suffix[:])), nil
+	})
+}
+
+// ServerUUIDs returns a generator of UUIDs from the server's _uuids
+// endpoint, fetched batch at a time so that most documents need no extra
+// request, see UUIDSource. The server's uuids/algorithm setting decides
+// their format.
+func ServerUUIDs(c *Client, batch int) IDGenerator {
+	return c.NewUUIDSource(&UUIDSourceOptions{BatchSize: batch})
+}
@@ -0,0 +1,133 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerators(t *testing.T) {
+	ctx := context.Background()
+
+	generate := func(gen IDGenerator, n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			id, err := gen.NewID(ctx)
+			require.NoError(t, err)
+			ids[i] = id
+		}
+		return ids
+	}
+
+	for _, id := range generate(UUIDv4(), 10) {
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{12}4[0-9a-f]{3}[89ab][0-9a-f]{15}$`), id)
+	}
+
+	// Many IDs fall into the same millisecond or microsecond and must still
+	// be unique and sorted
+	ulids := generate(ULID(), 1000)
+	assert.True(t, sort.StringsAreSorted(ulids))
+	assert.Len(t, unique(ulids), 1000)
+	for _, id := range ulids {
+		assert.Regexp(t, regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`), id)
+	}
+
+	sequential := generate(Sequential("order:"), 1000)
+	assert.True(t, sort.StringsAreSorted(sequential))
+	assert.Len(t, unique(sequential), 1000)
+	assert.Regexp(t, regexp.MustCompile(`^order:[0-9a-f]{22}$`), sequential[0])
+}
+
+func TestEncodeULID(t *testing.T) {
+	var raw [16]byte
+	assert.Equal(t, "00000000000000000000000000", encodeULID(raw))
+
+	for i := range raw {
+		raw[i] = 0xff
+	}
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(raw))
+}
+
+func TestServerUUIDs(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "3", r.URL.Query().Get("count"))
+		w.Header().Set("Content-Type", "application/json")
+		uuids := make([]string, 3)
+		for i := range uuids {
+			uuids[i] = fmt.Sprintf("uuid-%d-%d", requests, i)
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"uuids": uuids})
+	}))
+	defer server.Close()
+
+	gen := ServerUUIDs(NewClient(server.URL, nil), 3)
+
+	var ids []string
+	for range 4 {
+		id, err := gen.NewID(context.Background())
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	assert.Equal(t, []string{"uuid-1-0", "uuid-1-1", "uuid-1-2", "uuid-2-0"}, ids)
+	assert.Equal(t, 2, requests)
+}
+
+func TestWithIDGenerator(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("orders")
+
+	next := 0
+	db := NewClient(server.URL, nil).DB("orders").WithIDGenerator(IDGeneratorFunc(func(context.Context) (string, error) {
+		next++
+		return fmt.Sprintf("order-%d", next), nil
+	}))
+	ctx := context.Background()
+
+	result, err := db.Put(ctx, map[string]interface{}{"total": 10})
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", result.ID)
+
+	order := &taggedOrder{Total: 20}
+	_, err = db.Put(ctx, order)
+	require.NoError(t, err)
+	assert.Equal(t, "order-2", order.Key)
+
+	// Given IDs are kept
+	result, err = db.Put(ctx, map[string]interface{}{"_id": "mine"})
+	require.NoError(t, err)
+	assert.Equal(t, "mine", result.ID)
+
+	results, err := db.Bulk(ctx, []interface{}{
+		map[string]interface{}{"total": 30},
+		map[string]interface{}{"_id": "also-mine"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "order-3", results[0].ID)
+	assert.Equal(t, "also-mine", results[1].ID)
+
+	// Updates never get a new ID
+	_, err = db.Update(ctx, "mine", map[string]interface{}{"_rev": result.Rev, "total": 40})
+	require.NoError(t, err)
+	assert.Equal(t, 3, next)
+}
+
+func unique(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
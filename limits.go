@@ -0,0 +1,115 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SizeLimitError is returned when a serialized body exceeds a configured size guard
+type SizeLimitError struct {
+	DocID string // offending document ID, if known
+	Index int    // position in a bulk batch, or -1
+	Size  int64  // serialized size in bytes
+	Limit int64  // configured limit in bytes
+	Whole bool   // true if the whole request body, rather than one document, is too large
+}
+
+// Error implements error
+func (e *SizeLimitError) Error() string {
+	if e.Whole {
+		return fmt.Sprintf("request body of %d bytes exceeds limit of %d bytes", e.Size, e.Limit)
+	}
+
+	doc := e.DocID
+	if doc == "" {
+		doc = "(new document)"
+	}
+	if e.Index >= 0 {
+		return fmt.Sprintf("document %s at index %d is %d bytes, exceeding limit of %d bytes", doc, e.Index, e.Size, e.Limit)
+	}
+	return fmt.Sprintf("document %s is %d bytes, exceeding limit of %d bytes", doc, e.Size, e.Limit)
+}
+
+// guardDocument checks a single-document request body against the size
+// guards. When guards are configured it returns the serialized body so the
+// document is not encoded twice; otherwise doc is returned unchanged.
+func (c *Client) guardDocument(doc interface{}) (interface{}, error) {
+	if c.maxDocumentSize <= 0 && c.maxRequestSize <= 0 {
+		return doc, nil
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkDocumentSize(body, -1); err != nil {
+		return nil, err
+	}
+	if err := c.checkRequestSize(body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// guardBulk checks every document of a bulk request and the request as a whole
+func (c *Client) guardBulk(bulkDocs interface{}, docs []interface{}) (interface{}, error) {
+	if c.maxDocumentSize <= 0 && c.maxRequestSize <= 0 {
+		return bulkDocs, nil
+	}
+
+	if c.maxDocumentSize > 0 {
+		for i, doc := range docs {
+			body, err := json.Marshal(doc)
+			if err != nil {
+				return nil, err
+			}
+			if err := c.checkDocumentSize(body, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	body, err := json.Marshal(bulkDocs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRequestSize(body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (c *Client) checkDocumentSize(body []byte, index int) error {
+	if c.maxDocumentSize <= 0 || int64(len(body)) <= c.maxDocumentSize {
+		return nil
+	}
+
+	var doc struct {
+		ID string `json:"_id"`
+	}
+	_ = json.Unmarshal(body, &doc)
+
+	return &SizeLimitError{
+		DocID: doc.ID,
+		Index: index,
+		Size:  int64(len(body)),
+		Limit: c.maxDocumentSize,
+	}
+}
+
+func (c *Client) checkRequestSize(body []byte) error {
+	if c.maxRequestSize <= 0 || int64(len(body)) <= c.maxRequestSize {
+		return nil
+	}
+
+	return &SizeLimitError{
+		Index: -1,
+		Size:  int64(len(body)),
+		Limit: c.maxRequestSize,
+		Whole: true,
+	}
+}
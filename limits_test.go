@@ -0,0 +1,56 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeGuards(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, &ClientOptions{MaxDocumentSize: 100, MaxRequestSize: 150}).DB("db")
+	ctx := context.Background()
+	big := strings.Repeat("x", 100)
+
+	_, err := db.Put(ctx, map[string]interface{}{"_id": "big", "body": big})
+	var sizeErr *SizeLimitError
+	require.True(t, errors.As(err, &sizeErr))
+	assert.Equal(t, "big", sizeErr.DocID)
+	assert.Equal(t, -1, sizeErr.Index)
+	assert.Equal(t, int64(100), sizeErr.Limit)
+
+	_, err = db.Bulk(ctx, []interface{}{
+		map[string]interface{}{"_id": "small"},
+		map[string]interface{}{"_id": "big", "body": big},
+	})
+	require.True(t, errors.As(err, &sizeErr))
+	assert.Equal(t, 1, sizeErr.Index)
+	assert.Equal(t, "big", sizeErr.DocID)
+
+	medium := strings.Repeat("x", 70)
+	_, err = db.Bulk(ctx, []interface{}{
+		map[string]interface{}{"a": medium},
+		map[string]interface{}{"b": medium},
+	})
+	require.True(t, errors.As(err, &sizeErr))
+	assert.True(t, sizeErr.Whole)
+
+	assert.Zero(t, requests, "oversized requests must not be sent")
+
+	_, err = db.Bulk(ctx, []interface{}{map[string]interface{}{"_id": "small"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
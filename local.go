@@ -0,0 +1,33 @@
+package couchdb
+
+import (
+	"context"
+	"strings"
+)
+
+const localPrefix = "_local/"
+
+// GetLocal retrieves a local document. Local documents are not replicated
+// and do not appear in _all_docs or the changes feed. The ID may be given
+// with or without the "_local/" prefix.
+func (db *Database) GetLocal(ctx context.Context, id string) (*Document, error) {
+	return db.Get(ctx, localID(id))
+}
+
+// PutLocal creates or updates a local document. Updates must carry the
+// current _rev of the document.
+func (db *Database) PutLocal(ctx context.Context, id string, doc interface{}) (*Document, error) {
+	return db.Update(ctx, localID(id), doc)
+}
+
+// DeleteLocal deletes a local document
+func (db *Database) DeleteLocal(ctx context.Context, id, rev string) error {
+	return db.Delete(ctx, localID(id), rev)
+}
+
+func localID(id string) string {
+	if strings.HasPrefix(id, localPrefix) {
+		return id
+	}
+	return localPrefix + id
+}
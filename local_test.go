@@ -0,0 +1,64 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCheckpointStore(t *testing.T) {
+	docs := map[string]map[string]interface{}{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.True(t, strings.HasPrefix(r.URL.Path, "/db/_local/"), r.URL.Path)
+		id := strings.TrimPrefix(r.URL.Path, "/db/")
+
+		switch r.Method {
+		case "GET":
+			doc, ok := docs[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(doc)
+		case "PUT":
+			var doc map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			doc["_id"], doc["_rev"] = id, "0-1"
+			docs[id] = doc
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": id, "rev": "0-1"})
+		case "DELETE":
+			delete(docs, id)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	store := NewLocalCheckpointStore(db)
+	ctx := context.Background()
+
+	seq, err := store.Load(ctx, "worker")
+	require.NoError(t, err)
+	assert.Empty(t, seq)
+
+	require.NoError(t, store.Save(ctx, "worker", "42-abc"))
+	require.NoError(t, store.Save(ctx, "worker", "43-def"))
+
+	seq, err = store.Load(ctx, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "43-def", seq)
+
+	doc, err := db.GetLocal(ctx, "_local/worker")
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteLocal(ctx, "worker", doc.Rev))
+	assert.Empty(t, docs)
+}
@@ -0,0 +1,27 @@
+package couchdb
+
+import (
+	"log"
+	"os"
+)
+
+// stdLogger is the default Logger, writing to stderr through the log package
+type stdLogger struct {
+	l *log.Logger
+}
+
+func newStdLogger() *stdLogger {
+	return &stdLogger{l: log.New(os.Stderr, "couchdb ", log.LstdFlags)}
+}
+
+func (s *stdLogger) Errorf(format string, v ...interface{}) {
+	s.l.Printf("ERROR "+format, v...)
+}
+
+func (s *stdLogger) Warnf(format string, v ...interface{}) {
+	s.l.Printf("WARN "+format, v...)
+}
+
+func (s *stdLogger) Debugf(format string, v ...interface{}) {
+	s.l.Printf("DEBUG "+format, v...)
+}
@@ -0,0 +1,111 @@
+package couchdb
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsCollector receives a callback for every HTTP request the client
+// makes, including each retry attempt. Implementations must be safe for
+// concurrent use. See the prometheus subpackage for a ready-made collector.
+type MetricsCollector interface {
+	// RequestStarted is called before a request is sent
+	RequestStarted(info RequestInfo)
+
+	// RequestFinished is called once the response headers arrive or the
+	// request fails. Status is zero when err is set. For streamed responses
+	// the duration does not include reading the body.
+	RequestFinished(info RequestInfo, status int, duration time.Duration, err error)
+}
+
+// RequestInfo identifies the operation a request performs
+type RequestInfo struct {
+	Method string
+
+	// Operation is a low-cardinality name for the endpoint, e.g. "document",
+	// "_find", "_view" or "_session"
+	Operation string
+
+	// Database is the target database, empty for server-level endpoints
+	Database string
+}
+
+// metricsTransport reports every round trip to a MetricsCollector
+type metricsTransport struct {
+	next    http.RoundTripper
+	metrics MetricsCollector
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	info := requestInfo(req)
+
+	t.metrics.RequestStarted(info)
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	t.metrics.RequestFinished(info, status, time.Since(start), err)
+
+	return resp, err
+}
+
+// requestInfo classifies a request by its URL path
+func requestInfo(req *http.Request) RequestInfo {
+	info := RequestInfo{Method: req.Method}
+
+	segments := strings.Split(strings.Trim(req.URL.EscapedPath(), "/"), "/")
+
+	switch {
+	case segments[0] == "":
+		info.Operation = "server"
+	case strings.HasPrefix(segments[0], "_"):
+		info.Operation = segments[0]
+	default:
+		info.Database = segments[0]
+		info.Operation = databaseOperation(segments[1:])
+	}
+
+	return info
+}
+
+// designOperations are the design document endpoints used as operation
+// labels. Anything else below a design document is an attachment, whose
+// name must not become a label value.
+var designOperations = map[string]bool{
+	"_view":    true,
+	"_show":    true,
+	"_list":    true,
+	"_update":  true,
+	"_rewrite": true,
+	"_info":    true,
+	"_search":  true,
+	"_nouveau": true,
+}
+
+func databaseOperation(segments []string) string {
+	switch {
+	case len(segments) == 0:
+		return "database"
+	case segments[0] == "_design":
+		if len(segments) < 3 {
+			return "design_doc"
+		}
+		if designOperations[segments[2]] {
+			return segments[2]
+		}
+		return "design_attachment"
+	case segments[0] == "_local":
+		return "local_doc"
+	case strings.HasPrefix(segments[0], "_"):
+		return segments[0]
+	case len(segments) > 1:
+		return "attachment"
+	default:
+		return "document"
+	}
+}
@@ -0,0 +1,78 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestInfo(t *testing.T) {
+	tests := []struct {
+		path      string
+		operation string
+		database  string
+	}{
+		{"/", "server", ""},
+		{"/_session", "_session", ""},
+		{"/_node/_local/_config/admins", "_node", ""},
+		{"/db", "database", "db"},
+		{"/db/doc1", "document", "db"},
+		{"/db/doc1/photo.jpg", "attachment", "db"},
+		{"/db/_find", "_find", "db"},
+		{"/db/_local/checkpoint", "local_doc", "db"},
+		{"/db/_design/app", "design_doc", "db"},
+		{"/db/_design/app/_view/by_name", "_view", "db"},
+		{"/db/_design/app/_search/people", "_search", "db"},
+		{"/db/_design/app/_rewrite/some/path", "_rewrite", "db"},
+		{"/db/_design/app/logo.png", "design_attachment", "db"},
+		{"/db/_design/app/_attachments", "design_attachment", "db"},
+	}
+
+	for _, tt := range tests {
+		info := requestInfo(httptest.NewRequest("GET", tt.path, nil))
+		assert.Equal(t, tt.operation, info.Operation, tt.path)
+		assert.Equal(t, tt.database, info.Database, tt.path)
+	}
+}
+
+// recordingMetrics collects the operations of finished requests
+type recordingMetrics struct {
+	mu         sync.Mutex
+	operations map[string]int
+}
+
+func (m *recordingMetrics) RequestStarted(RequestInfo) {}
+
+func (m *recordingMetrics) RequestFinished(info RequestInfo, _ int, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operations[info.Operation]++
+}
+
+func TestMetrics_DesignAttachmentsShareOneLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{operations: map[string]int{}}
+	db := NewClient(server.URL, &ClientOptions{Metrics: metrics}).DB("db")
+	ctx := context.Background()
+
+	for _, name := range []string{"index.html", "app.js", "style.css"} {
+		body, _, err := db.GetAttachment(ctx, "_design/app", name)
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+	}
+	_, err := db.View(ctx, "app", "by_name", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"design_attachment": 3, "_view": 1}, metrics.operations)
+}
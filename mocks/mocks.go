@@ -0,0 +1,1492 @@
+// Package mocks provides mocks of the couchdb client for unit tests of
+// code that depends on couchdb.ClientAPI or couchdb.DatabaseAPI. Every
+// method calls the function field of the same name with a Func suffix;
+// calling a method whose function is not set panics.
+//
+//	db := &mocks.Database{
+//		GetFunc: func(ctx context.Context, id string, rev ...string) (*couchdb.Document, error) {
+//			return &couchdb.Document{ID: id}, nil
+//		},
+//	}
+//	svc := NewUserService(db)
+package mocks
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+var (
+	_ couchdb.ClientAPI   = (*Client)(nil)
+	_ couchdb.DatabaseAPI = (*Database)(nil)
+)
+
+// Client is a mock of couchdb.ClientAPI
+type Client struct {
+	ActiveTasksFunc         func(context.Context) ([]couchdb.ActiveTask, error)
+	ActiveTasksOfTypeFunc   func(context.Context, string) ([]couchdb.ActiveTask, error)
+	AllDbsFunc              func(context.Context) ([]string, error)
+	CancelReplicationFunc   func(context.Context, string) error
+	ConfigureCORSFunc       func(context.Context, couchdb.CORSConfig) error
+	CreateAdminFunc         func(context.Context, string, string) error
+	CreateDBFunc            func(context.Context, string) error
+	CreateDBWithOptionsFunc func(context.Context, string, *couchdb.DBCreateOptions) error
+	CreateReplicationFunc   func(context.Context, *couchdb.ReplicationSpec) (*couchdb.Document, error)
+	DBFunc                  func(string) *couchdb.Database
+	DBsInfoFunc             func(context.Context, []string) ([]couchdb.DBInfoResult, error)
+	DeleteConfigKeyFunc     func(context.Context, string, string, string) (string, error)
+	DeleteDBFunc            func(context.Context, string) error
+	EnsureDBFunc            func(context.Context, string, *couchdb.DBCreateOptions) (bool, error)
+	GetConfigFunc           func(context.Context, string) (map[string]map[string]string, error)
+	GetConfigKeyFunc        func(context.Context, string, string, string) (string, error)
+	GetConfigSectionFunc    func(context.Context, string, string) (map[string]string, error)
+	GetReplicationFunc      func(context.Context, string) (*couchdb.ReplicationSpec, error)
+	GetSchedulerDocFunc     func(context.Context, string, string) (*couchdb.SchedulerDoc, error)
+	GetSmooshChannelFunc    func(context.Context, string, string) (*couchdb.SmooshChannel, error)
+	GetSmooshConfigFunc     func(context.Context, string) (*couchdb.SmooshConfig, error)
+	InfoFunc                func(context.Context) (*couchdb.ServerInfo, error)
+	IsAdminPartyFunc        func(context.Context) (bool, error)
+	ListReplicationsFunc    func(context.Context) ([]*couchdb.ReplicationSpec, error)
+	LoginFunc               func(context.Context, string, string) (*couchdb.UserContext, error)
+	LogoutFunc              func(context.Context) error
+	MembershipFunc          func(context.Context) (*couchdb.Membership, error)
+	NewTenancyFunc          func(*couchdb.TenancyOptions) *couchdb.Tenancy
+	NewUUIDSourceFunc       func(*couchdb.UUIDSourceOptions) *couchdb.UUIDSource
+	NodeInfoFunc            func(context.Context, string) (*couchdb.NodeInfo, error)
+	NodePrometheusFunc      func(context.Context, string) ([]byte, error)
+	NodeStatsFunc           func(context.Context, string) (map[string]couchdb.Stat, error)
+	NodeStatsPathFunc       func(context.Context, string, ...string) (map[string]couchdb.Stat, error)
+	NodeSystemFunc          func(context.Context, string) (*couchdb.SystemStats, error)
+	PingFunc                func(context.Context) error
+	ReplicateFunc           func(context.Context, string, string, *couchdb.ReplicateOptions) (*couchdb.ReplicationResult, error)
+	SchedulerDocsFunc       func(context.Context, string) ([]couchdb.SchedulerDoc, error)
+	SchedulerJobsFunc       func(context.Context) ([]couchdb.SchedulerJob, error)
+	ServerVersionFunc       func(context.Context) (string, error)
+	SessionFunc             func(context.Context) (*couchdb.SessionInfo, error)
+	SetConfigKeyFunc        func(context.Context, string, string, string, string) (string, error)
+	SetSmooshChannelFunc    func(context.Context, string, string, *couchdb.SmooshChannel) error
+	SetSmooshConfigFunc     func(context.Context, string, *couchdb.SmooshConfig) error
+	UUIDFunc                func(context.Context) (string, error)
+	UUIDsFunc               func(context.Context, int) ([]string, error)
+	UpFunc                  func(context.Context) (*couchdb.UpStatus, error)
+	UsersFunc               func() *couchdb.Users
+	WaitForReplicationFunc  func(context.Context, string, *couchdb.WaitOptions) (*couchdb.SchedulerDoc, error)
+	WaitUntilReadyFunc      func(context.Context, time.Duration) error
+}
+
+// Database is a mock of couchdb.DatabaseAPI
+type Database struct {
+	AddAdminFunc               func(context.Context, string) error
+	AddAdminRoleFunc           func(context.Context, string) error
+	AddMemberFunc              func(context.Context, string) error
+	AddMemberRoleFunc          func(context.Context, string) error
+	AllDocsFunc                func(context.Context, *couchdb.ViewOptions) (*couchdb.ViewResult, error)
+	AllDocsByKeysFunc          func(context.Context, []string, *couchdb.ViewOptions) (*couchdb.ViewResult, error)
+	AllDocsStreamFunc          func(context.Context, *couchdb.ViewOptions) (*couchdb.ViewRows, error)
+	AttachmentInfoFunc         func(context.Context, string, string, ...string) (*couchdb.AttachmentMeta, error)
+	BulkFunc                   func(context.Context, []interface{}) (couchdb.BulkResponse, error)
+	BulkGetFunc                func(context.Context, []couchdb.BulkGetRequest, *couchdb.BulkGetOptions) (*couchdb.BulkGetResult, error)
+	BulkWithOptionsFunc        func(context.Context, []interface{}, *couchdb.BulkOptions) (couchdb.BulkResponse, error)
+	ChangesFunc                func(context.Context, map[string]interface{}) (map[string]interface{}, error)
+	ChangesFeedFunc            func(context.Context, *couchdb.ChangesFeedOptions) *couchdb.ChangesFeed
+	CompactFunc                func(context.Context) error
+	CompactAllFunc             func(context.Context, *couchdb.CompactAllOptions) error
+	CompactDesignDocFunc       func(context.Context, string) error
+	CopyFunc                   func(context.Context, string, string, ...string) (*couchdb.Document, error)
+	CounterFunc                func(string, string) *couchdb.Counter
+	CreateIndexFunc            func(context.Context, *couchdb.MangoIndex) (*couchdb.CreateIndexResult, error)
+	DeleteFunc                 func(context.Context, string, string) error
+	DeleteAttachmentFunc       func(context.Context, string, string, string) (*couchdb.Document, error)
+	DeleteDesignDocFunc        func(context.Context, string, string) error
+	DeleteDocFunc              func(context.Context, interface{}) error
+	DeleteLocalFunc            func(context.Context, string, string) error
+	DeleteWithOptionsFunc      func(context.Context, string, string, *couchdb.WriteOptions) error
+	DeployDesignDocSafelyFunc  func(context.Context, string, *couchdb.DesignDocument, *couchdb.DeployOptions) (bool, error)
+	DiffDesignDocFunc          func(context.Context, string, *couchdb.DesignDocument) (*couchdb.DesignDocDiff, error)
+	EnsureCRDTViewsFunc        func(context.Context) error
+	EnsureFieldViewFunc        func(context.Context, ...string) (string, error)
+	EnsureIdempotencyIndexFunc func(context.Context, *couchdb.IdempotencyOptions) error
+	ExplainFunc                func(context.Context, *couchdb.FindQuery) (*couchdb.ExplainResult, error)
+	ExportFunc                 func(context.Context, io.Writer, *couchdb.ExportOptions) (int, error)
+	FindFunc                   func(context.Context, *couchdb.FindQuery) (*couchdb.FindResult, error)
+	FindByIdempotencyKeyFunc   func(context.Context, string, *couchdb.IdempotencyOptions) (*couchdb.Document, error)
+	GetFunc                    func(context.Context, string, ...string) (*couchdb.Document, error)
+	GetAttachmentFunc          func(context.Context, string, string, ...string) (io.ReadCloser, *couchdb.AttachmentMeta, error)
+	GetChangesFunc             func(context.Context, *couchdb.ChangesOptions) (*couchdb.ChangesResponse, error)
+	GetConflictsFunc           func(context.Context, string) (*couchdb.ConflictSet, error)
+	GetDesignDocFunc           func(context.Context, string) (*couchdb.DesignDocument, error)
+	GetIntoFunc                func(context.Context, string, interface{}, ...string) error
+	GetLocalFunc               func(context.Context, string) (*couchdb.Document, error)
+	GetMultipartFunc           func(context.Context, string, *couchdb.GetOptions) (*couchdb.MultipartDocument, error)
+	GetOpenRevsFunc            func(context.Context, string, []string, *couchdb.GetOptions) ([]couchdb.OpenRev, error)
+	GetOpenRevsMultipartFunc   func(context.Context, string, []string, *couchdb.GetOptions) (*couchdb.OpenRevsReader, error)
+	GetRevsLimitFunc           func(context.Context) (int, error)
+	GetSecurityFunc            func(context.Context) (*couchdb.SecurityObject, error)
+	GetWithOptionsFunc         func(context.Context, string, *couchdb.GetOptions) (*couchdb.Document, error)
+	ImportFunc                 func(context.Context, io.Reader, *couchdb.ImportOptions) (int, error)
+	InfoFunc                   func(context.Context) (*couchdb.DatabaseInfo, error)
+	ListDesignDocsFunc         func(context.Context) (*couchdb.ViewResult, error)
+	MissingRevsFunc            func(context.Context, map[string][]string) (map[string][]string, error)
+	NameFunc                   func() string
+	NewBulkLoaderFunc          func(context.Context, *couchdb.BulkLoaderOptions) *couchdb.BulkLoader
+	NewChangesFollowerFunc     func(couchdb.EventSink, couchdb.CheckpointStore, *couchdb.FollowerOptions) *couchdb.ChangesFollower
+	NewChangesProcessorFunc    func(couchdb.ChangeHandler, couchdb.CheckpointStore, *couchdb.FollowerOptions) *couchdb.ChangesFollower
+	NewExpiryReaperFunc        func(*couchdb.ExpiryReaperOptions) *couchdb.ExpiryReaper
+	NewFindQueryFunc           func(...couchdb.Selector) *couchdb.FindBuilder
+	NewOutboxDispatcherFunc    func(couchdb.OutboxHandler, couchdb.CheckpointStore, *couchdb.FollowerOptions) *couchdb.OutboxDispatcher
+	NewViewCacheFunc           func(*couchdb.ViewCacheOptions) *couchdb.ViewCache
+	NewViewQueryFunc           func(string, string) *couchdb.ViewBuilder
+	NewViewWarmerFunc          func(*couchdb.ViewWarmerOptions) *couchdb.ViewWarmer
+	PaginateAllDocsFunc        func(*couchdb.ViewOptions, int) *couchdb.Paginator
+	PaginateFindFunc           func(*couchdb.FindQuery, int) *couchdb.Paginator
+	PaginateViewFunc           func(string, string, *couchdb.ViewOptions, int) *couchdb.Paginator
+	PurgeSeqFunc               func(context.Context) (string, error)
+	PurgedSinceFunc            func(context.Context, string) (bool, string, error)
+	PutFunc                    func(context.Context, interface{}) (*couchdb.Document, error)
+	PutAttachmentFunc          func(context.Context, string, string, string, string, []byte) (*couchdb.Document, error)
+	PutAttachmentStreamFunc    func(context.Context, string, string, string, string, io.Reader, int64) (*couchdb.Document, error)
+	PutDesignDocFunc           func(context.Context, string, *couchdb.DesignDocument) (*couchdb.Document, error)
+	PutIdempotentFunc          func(context.Context, string, interface{}, *couchdb.IdempotencyOptions) (*couchdb.Document, error)
+	PutLocalFunc               func(context.Context, string, interface{}) (*couchdb.Document, error)
+	PutMultipartFunc           func(context.Context, interface{}, ...*couchdb.AttachmentUpload) (*couchdb.Document, error)
+	PutWithOptionsFunc         func(context.Context, interface{}, *couchdb.WriteOptions) (*couchdb.Document, error)
+	QFunc                      func(string) *couchdb.ViewBuilder
+	RemoveAdminFunc            func(context.Context, string) error
+	RemoveAdminRoleFunc        func(context.Context, string) error
+	RemoveMemberFunc           func(context.Context, string) error
+	RemoveMemberRoleFunc       func(context.Context, string) error
+	ResolveConflictFunc        func(context.Context, string, interface{}, ...string) (couchdb.BulkResponse, error)
+	RestoreFunc                func(context.Context, string) (*couchdb.Document, error)
+	RevsDiffFunc               func(context.Context, map[string][]string) (map[string]couchdb.RevsDiffResult, error)
+	RewriteFunc                func(context.Context, string, string, string, interface{}) (*couchdb.RewriteResult, error)
+	SearchFunc                 func(context.Context, string, string, *couchdb.SearchQuery) (*couchdb.SearchResult, error)
+	SetFunc                    func(string, string) *couchdb.Set
+	SetRevsLimitFunc           func(context.Context, int) error
+	SetSecurityFunc            func(context.Context, *couchdb.SecurityObject) error
+	SubscribeFunc              func(context.Context, couchdb.SubscribeOptions) *couchdb.Subscription
+	SyncDesignDocsFunc         func(context.Context, fs.FS) ([]string, error)
+	UpdateFunc                 func(context.Context, string, interface{}) (*couchdb.Document, error)
+	UpdateHandlerFunc          func(context.Context, string, string, string, interface{}) (*couchdb.UpdateHandlerResult, error)
+	UpdateWithOptionsFunc      func(context.Context, string, interface{}, *couchdb.WriteOptions) (*couchdb.Document, error)
+	UpsertFunc                 func(context.Context, string, couchdb.UpsertFunc) (*couchdb.Document, error)
+	UpsertWithOptionsFunc      func(context.Context, string, couchdb.UpsertFunc, *couchdb.UpsertOptions) (*couchdb.Document, error)
+	ViewFunc                   func(context.Context, string, string, *couchdb.ViewOptions) (*couchdb.ViewResult, error)
+	ViewAllFunc                func(context.Context, string, string, bool) (*couchdb.ViewResult, error)
+	ViewByKeyFunc              func(context.Context, string, string, interface{}) (*couchdb.ViewResult, error)
+	ViewByKeyRangeFunc         func(context.Context, string, string, interface{}, interface{}) (*couchdb.ViewResult, error)
+	ViewCleanupFunc            func(context.Context) error
+	ViewInfoFunc               func(context.Context, string, string) (map[string]interface{}, error)
+	ViewReduceFunc             func(context.Context, string, string, int) (*couchdb.ViewResult, error)
+	ViewStreamFunc             func(context.Context, string, string, *couchdb.ViewOptions) (*couchdb.ViewRows, error)
+	ViewWithKeysFunc           func(context.Context, string, string, []interface{}, *couchdb.ViewOptions) (*couchdb.ViewResult, error)
+	WaitForCompactionFunc      func(context.Context, time.Duration, func(couchdb.CompactionProgress)) error
+	WaitForViewCompactionFunc  func(context.Context, string, time.Duration, func(couchdb.CompactionProgress)) error
+	WarmViewsFunc              func(context.Context, string, *couchdb.WarmOptions) error
+	WithDesignDocFunc          func(string) *couchdb.Database
+	WithIDGeneratorFunc        func(couchdb.IDGenerator) *couchdb.Database
+	WithSoftDeleteFunc         func(*couchdb.SoftDeleteOptions) *couchdb.Database
+	WithTimestampsFunc         func(*couchdb.TimestampOptions) *couchdb.Database
+	WithValidatorFunc          func(couchdb.DocValidator) *couchdb.Database
+	WithWriteHookFunc          func(couchdb.WriteHook) *couchdb.Database
+	WriteWithEventsFunc        func(context.Context, []interface{}, ...*couchdb.OutboxEvent) (couchdb.BulkResponse, error)
+}
+
+// ActiveTasks calls ActiveTasksFunc
+func (m *Client) ActiveTasks(ctx context.Context) ([]couchdb.ActiveTask, error) {
+	if m.ActiveTasksFunc == nil {
+		panic("mocks: unexpected call to Client.ActiveTasks")
+	}
+	return m.ActiveTasksFunc(ctx)
+}
+
+// ActiveTasksOfType calls ActiveTasksOfTypeFunc
+func (m *Client) ActiveTasksOfType(ctx context.Context, taskType string) ([]couchdb.ActiveTask, error) {
+	if m.ActiveTasksOfTypeFunc == nil {
+		panic("mocks: unexpected call to Client.ActiveTasksOfType")
+	}
+	return m.ActiveTasksOfTypeFunc(ctx, taskType)
+}
+
+// AllDbs calls AllDbsFunc
+func (m *Client) AllDbs(ctx context.Context) ([]string, error) {
+	if m.AllDbsFunc == nil {
+		panic("mocks: unexpected call to Client.AllDbs")
+	}
+	return m.AllDbsFunc(ctx)
+}
+
+// CancelReplication calls CancelReplicationFunc
+func (m *Client) CancelReplication(ctx context.Context, id string) error {
+	if m.CancelReplicationFunc == nil {
+		panic("mocks: unexpected call to Client.CancelReplication")
+	}
+	return m.CancelReplicationFunc(ctx, id)
+}
+
+// ConfigureCORS calls ConfigureCORSFunc
+func (m *Client) ConfigureCORS(ctx context.Context, cfg couchdb.CORSConfig) error {
+	if m.ConfigureCORSFunc == nil {
+		panic("mocks: unexpected call to Client.ConfigureCORS")
+	}
+	return m.ConfigureCORSFunc(ctx, cfg)
+}
+
+// CreateAdmin calls CreateAdminFunc
+func (m *Client) CreateAdmin(ctx context.Context, user string, pass string) error {
+	if m.CreateAdminFunc == nil {
+		panic("mocks: unexpected call to Client.CreateAdmin")
+	}
+	return m.CreateAdminFunc(ctx, user, pass)
+}
+
+// CreateDB calls CreateDBFunc
+func (m *Client) CreateDB(ctx context.Context, name string) error {
+	if m.CreateDBFunc == nil {
+		panic("mocks: unexpected call to Client.CreateDB")
+	}
+	return m.CreateDBFunc(ctx, name)
+}
+
+// CreateDBWithOptions calls CreateDBWithOptionsFunc
+func (m *Client) CreateDBWithOptions(ctx context.Context, name string, opts *couchdb.DBCreateOptions) error {
+	if m.CreateDBWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Client.CreateDBWithOptions")
+	}
+	return m.CreateDBWithOptionsFunc(ctx, name, opts)
+}
+
+// CreateReplication calls CreateReplicationFunc
+func (m *Client) CreateReplication(ctx context.Context, spec *couchdb.ReplicationSpec) (*couchdb.Document, error) {
+	if m.CreateReplicationFunc == nil {
+		panic("mocks: unexpected call to Client.CreateReplication")
+	}
+	return m.CreateReplicationFunc(ctx, spec)
+}
+
+// DB calls DBFunc
+func (m *Client) DB(name string) *couchdb.Database {
+	if m.DBFunc == nil {
+		panic("mocks: unexpected call to Client.DB")
+	}
+	return m.DBFunc(name)
+}
+
+// DBsInfo calls DBsInfoFunc
+func (m *Client) DBsInfo(ctx context.Context, names []string) ([]couchdb.DBInfoResult, error) {
+	if m.DBsInfoFunc == nil {
+		panic("mocks: unexpected call to Client.DBsInfo")
+	}
+	return m.DBsInfoFunc(ctx, names)
+}
+
+// DeleteConfigKey calls DeleteConfigKeyFunc
+func (m *Client) DeleteConfigKey(ctx context.Context, node string, section string, key string) (string, error) {
+	if m.DeleteConfigKeyFunc == nil {
+		panic("mocks: unexpected call to Client.DeleteConfigKey")
+	}
+	return m.DeleteConfigKeyFunc(ctx, node, section, key)
+}
+
+// DeleteDB calls DeleteDBFunc
+func (m *Client) DeleteDB(ctx context.Context, name string) error {
+	if m.DeleteDBFunc == nil {
+		panic("mocks: unexpected call to Client.DeleteDB")
+	}
+	return m.DeleteDBFunc(ctx, name)
+}
+
+// EnsureDB calls EnsureDBFunc
+func (m *Client) EnsureDB(ctx context.Context, name string, opts *couchdb.DBCreateOptions) (bool, error) {
+	if m.EnsureDBFunc == nil {
+		panic("mocks: unexpected call to Client.EnsureDB")
+	}
+	return m.EnsureDBFunc(ctx, name, opts)
+}
+
+// GetConfig calls GetConfigFunc
+func (m *Client) GetConfig(ctx context.Context, node string) (map[string]map[string]string, error) {
+	if m.GetConfigFunc == nil {
+		panic("mocks: unexpected call to Client.GetConfig")
+	}
+	return m.GetConfigFunc(ctx, node)
+}
+
+// GetConfigKey calls GetConfigKeyFunc
+func (m *Client) GetConfigKey(ctx context.Context, node string, section string, key string) (string, error) {
+	if m.GetConfigKeyFunc == nil {
+		panic("mocks: unexpected call to Client.GetConfigKey")
+	}
+	return m.GetConfigKeyFunc(ctx, node, section, key)
+}
+
+// GetConfigSection calls GetConfigSectionFunc
+func (m *Client) GetConfigSection(ctx context.Context, node string, section string) (map[string]string, error) {
+	if m.GetConfigSectionFunc == nil {
+		panic("mocks: unexpected call to Client.GetConfigSection")
+	}
+	return m.GetConfigSectionFunc(ctx, node, section)
+}
+
+// GetReplication calls GetReplicationFunc
+func (m *Client) GetReplication(ctx context.Context, id string) (*couchdb.ReplicationSpec, error) {
+	if m.GetReplicationFunc == nil {
+		panic("mocks: unexpected call to Client.GetReplication")
+	}
+	return m.GetReplicationFunc(ctx, id)
+}
+
+// GetSchedulerDoc calls GetSchedulerDocFunc
+func (m *Client) GetSchedulerDoc(ctx context.Context, replicatorDB string, docID string) (*couchdb.SchedulerDoc, error) {
+	if m.GetSchedulerDocFunc == nil {
+		panic("mocks: unexpected call to Client.GetSchedulerDoc")
+	}
+	return m.GetSchedulerDocFunc(ctx, replicatorDB, docID)
+}
+
+// GetSmooshChannel calls GetSmooshChannelFunc
+func (m *Client) GetSmooshChannel(ctx context.Context, node string, channel string) (*couchdb.SmooshChannel, error) {
+	if m.GetSmooshChannelFunc == nil {
+		panic("mocks: unexpected call to Client.GetSmooshChannel")
+	}
+	return m.GetSmooshChannelFunc(ctx, node, channel)
+}
+
+// GetSmooshConfig calls GetSmooshConfigFunc
+func (m *Client) GetSmooshConfig(ctx context.Context, node string) (*couchdb.SmooshConfig, error) {
+	if m.GetSmooshConfigFunc == nil {
+		panic("mocks: unexpected call to Client.GetSmooshConfig")
+	}
+	return m.GetSmooshConfigFunc(ctx, node)
+}
+
+// Info calls InfoFunc
+func (m *Client) Info(ctx context.Context) (*couchdb.ServerInfo, error) {
+	if m.InfoFunc == nil {
+		panic("mocks: unexpected call to Client.Info")
+	}
+	return m.InfoFunc(ctx)
+}
+
+// IsAdminParty calls IsAdminPartyFunc
+func (m *Client) IsAdminParty(ctx context.Context) (bool, error) {
+	if m.IsAdminPartyFunc == nil {
+		panic("mocks: unexpected call to Client.IsAdminParty")
+	}
+	return m.IsAdminPartyFunc(ctx)
+}
+
+// ListReplications calls ListReplicationsFunc
+func (m *Client) ListReplications(ctx context.Context) ([]*couchdb.ReplicationSpec, error) {
+	if m.ListReplicationsFunc == nil {
+		panic("mocks: unexpected call to Client.ListReplications")
+	}
+	return m.ListReplicationsFunc(ctx)
+}
+
+// Login calls LoginFunc
+func (m *Client) Login(ctx context.Context, username string, password string) (*couchdb.UserContext, error) {
+	if m.LoginFunc == nil {
+		panic("mocks: unexpected call to Client.Login")
+	}
+	return m.LoginFunc(ctx, username, password)
+}
+
+// Logout calls LogoutFunc
+func (m *Client) Logout(ctx context.Context) error {
+	if m.LogoutFunc == nil {
+		panic("mocks: unexpected call to Client.Logout")
+	}
+	return m.LogoutFunc(ctx)
+}
+
+// Membership calls MembershipFunc
+func (m *Client) Membership(ctx context.Context) (*couchdb.Membership, error) {
+	if m.MembershipFunc == nil {
+		panic("mocks: unexpected call to Client.Membership")
+	}
+	return m.MembershipFunc(ctx)
+}
+
+// NewTenancy calls NewTenancyFunc
+func (m *Client) NewTenancy(opts *couchdb.TenancyOptions) *couchdb.Tenancy {
+	if m.NewTenancyFunc == nil {
+		panic("mocks: unexpected call to Client.NewTenancy")
+	}
+	return m.NewTenancyFunc(opts)
+}
+
+// NewUUIDSource calls NewUUIDSourceFunc
+func (m *Client) NewUUIDSource(opts *couchdb.UUIDSourceOptions) *couchdb.UUIDSource {
+	if m.NewUUIDSourceFunc == nil {
+		panic("mocks: unexpected call to Client.NewUUIDSource")
+	}
+	return m.NewUUIDSourceFunc(opts)
+}
+
+// NodeInfo calls NodeInfoFunc
+func (m *Client) NodeInfo(ctx context.Context, node string) (*couchdb.NodeInfo, error) {
+	if m.NodeInfoFunc == nil {
+		panic("mocks: unexpected call to Client.NodeInfo")
+	}
+	return m.NodeInfoFunc(ctx, node)
+}
+
+// NodePrometheus calls NodePrometheusFunc
+func (m *Client) NodePrometheus(ctx context.Context, node string) ([]byte, error) {
+	if m.NodePrometheusFunc == nil {
+		panic("mocks: unexpected call to Client.NodePrometheus")
+	}
+	return m.NodePrometheusFunc(ctx, node)
+}
+
+// NodeStats calls NodeStatsFunc
+func (m *Client) NodeStats(ctx context.Context, node string) (map[string]couchdb.Stat, error) {
+	if m.NodeStatsFunc == nil {
+		panic("mocks: unexpected call to Client.NodeStats")
+	}
+	return m.NodeStatsFunc(ctx, node)
+}
+
+// NodeStatsPath calls NodeStatsPathFunc
+func (m *Client) NodeStatsPath(ctx context.Context, node string, path ...string) (map[string]couchdb.Stat, error) {
+	if m.NodeStatsPathFunc == nil {
+		panic("mocks: unexpected call to Client.NodeStatsPath")
+	}
+	return m.NodeStatsPathFunc(ctx, node, path...)
+}
+
+// NodeSystem calls NodeSystemFunc
+func (m *Client) NodeSystem(ctx context.Context, node string) (*couchdb.SystemStats, error) {
+	if m.NodeSystemFunc == nil {
+		panic("mocks: unexpected call to Client.NodeSystem")
+	}
+	return m.NodeSystemFunc(ctx, node)
+}
+
+// Ping calls PingFunc
+func (m *Client) Ping(ctx context.Context) error {
+	if m.PingFunc == nil {
+		panic("mocks: unexpected call to Client.Ping")
+	}
+	return m.PingFunc(ctx)
+}
+
+// Replicate calls ReplicateFunc
+func (m *Client) Replicate(ctx context.Context, source string, target string, opts *couchdb.ReplicateOptions) (*couchdb.ReplicationResult, error) {
+	if m.ReplicateFunc == nil {
+		panic("mocks: unexpected call to Client.Replicate")
+	}
+	return m.ReplicateFunc(ctx, source, target, opts)
+}
+
+// SchedulerDocs calls SchedulerDocsFunc
+func (m *Client) SchedulerDocs(ctx context.Context, replicatorDB string) ([]couchdb.SchedulerDoc, error) {
+	if m.SchedulerDocsFunc == nil {
+		panic("mocks: unexpected call to Client.SchedulerDocs")
+	}
+	return m.SchedulerDocsFunc(ctx, replicatorDB)
+}
+
+// SchedulerJobs calls SchedulerJobsFunc
+func (m *Client) SchedulerJobs(ctx context.Context) ([]couchdb.SchedulerJob, error) {
+	if m.SchedulerJobsFunc == nil {
+		panic("mocks: unexpected call to Client.SchedulerJobs")
+	}
+	return m.SchedulerJobsFunc(ctx)
+}
+
+// ServerVersion calls ServerVersionFunc
+func (m *Client) ServerVersion(ctx context.Context) (string, error) {
+	if m.ServerVersionFunc == nil {
+		panic("mocks: unexpected call to Client.ServerVersion")
+	}
+	return m.ServerVersionFunc(ctx)
+}
+
+// Session calls SessionFunc
+func (m *Client) Session(ctx context.Context) (*couchdb.SessionInfo, error) {
+	if m.SessionFunc == nil {
+		panic("mocks: unexpected call to Client.Session")
+	}
+	return m.SessionFunc(ctx)
+}
+
+// SetConfigKey calls SetConfigKeyFunc
+func (m *Client) SetConfigKey(ctx context.Context, node string, section string, key string, value string) (string, error) {
+	if m.SetConfigKeyFunc == nil {
+		panic("mocks: unexpected call to Client.SetConfigKey")
+	}
+	return m.SetConfigKeyFunc(ctx, node, section, key, value)
+}
+
+// SetSmooshChannel calls SetSmooshChannelFunc
+func (m *Client) SetSmooshChannel(ctx context.Context, node string, channel string, ch *couchdb.SmooshChannel) error {
+	if m.SetSmooshChannelFunc == nil {
+		panic("mocks: unexpected call to Client.SetSmooshChannel")
+	}
+	return m.SetSmooshChannelFunc(ctx, node, channel, ch)
+}
+
+// SetSmooshConfig calls SetSmooshConfigFunc
+func (m *Client) SetSmooshConfig(ctx context.Context, node string, cfg *couchdb.SmooshConfig) error {
+	if m.SetSmooshConfigFunc == nil {
+		panic("mocks: unexpected call to Client.SetSmooshConfig")
+	}
+	return m.SetSmooshConfigFunc(ctx, node, cfg)
+}
+
+// UUID calls UUIDFunc
+func (m *Client) UUID(ctx context.Context) (string, error) {
+	if m.UUIDFunc == nil {
+		panic("mocks: unexpected call to Client.UUID")
+	}
+	return m.UUIDFunc(ctx)
+}
+
+// UUIDs calls UUIDsFunc
+func (m *Client) UUIDs(ctx context.Context, count int) ([]string, error) {
+	if m.UUIDsFunc == nil {
+		panic("mocks: unexpected call to Client.UUIDs")
+	}
+	return m.UUIDsFunc(ctx, count)
+}
+
+// Up calls UpFunc
+func (m *Client) Up(ctx context.Context) (*couchdb.UpStatus, error) {
+	if m.UpFunc == nil {
+		panic("mocks: unexpected call to Client.Up")
+	}
+	return m.UpFunc(ctx)
+}
+
+// Users calls UsersFunc
+func (m *Client) Users() *couchdb.Users {
+	if m.UsersFunc == nil {
+		panic("mocks: unexpected call to Client.Users")
+	}
+	return m.UsersFunc()
+}
+
+// WaitForReplication calls WaitForReplicationFunc
+func (m *Client) WaitForReplication(ctx context.Context, replicationID string, opts *couchdb.WaitOptions) (*couchdb.SchedulerDoc, error) {
+	if m.WaitForReplicationFunc == nil {
+		panic("mocks: unexpected call to Client.WaitForReplication")
+	}
+	return m.WaitForReplicationFunc(ctx, replicationID, opts)
+}
+
+// WaitUntilReady calls WaitUntilReadyFunc
+func (m *Client) WaitUntilReady(ctx context.Context, interval time.Duration) error {
+	if m.WaitUntilReadyFunc == nil {
+		panic("mocks: unexpected call to Client.WaitUntilReady")
+	}
+	return m.WaitUntilReadyFunc(ctx, interval)
+}
+
+// AddAdmin calls AddAdminFunc
+func (m *Database) AddAdmin(ctx context.Context, name string) error {
+	if m.AddAdminFunc == nil {
+		panic("mocks: unexpected call to Database.AddAdmin")
+	}
+	return m.AddAdminFunc(ctx, name)
+}
+
+// AddAdminRole calls AddAdminRoleFunc
+func (m *Database) AddAdminRole(ctx context.Context, role string) error {
+	if m.AddAdminRoleFunc == nil {
+		panic("mocks: unexpected call to Database.AddAdminRole")
+	}
+	return m.AddAdminRoleFunc(ctx, role)
+}
+
+// AddMember calls AddMemberFunc
+func (m *Database) AddMember(ctx context.Context, name string) error {
+	if m.AddMemberFunc == nil {
+		panic("mocks: unexpected call to Database.AddMember")
+	}
+	return m.AddMemberFunc(ctx, name)
+}
+
+// AddMemberRole calls AddMemberRoleFunc
+func (m *Database) AddMemberRole(ctx context.Context, role string) error {
+	if m.AddMemberRoleFunc == nil {
+		panic("mocks: unexpected call to Database.AddMemberRole")
+	}
+	return m.AddMemberRoleFunc(ctx, role)
+}
+
+// AllDocs calls AllDocsFunc
+func (m *Database) AllDocs(ctx context.Context, opts *couchdb.ViewOptions) (*couchdb.ViewResult, error) {
+	if m.AllDocsFunc == nil {
+		panic("mocks: unexpected call to Database.AllDocs")
+	}
+	return m.AllDocsFunc(ctx, opts)
+}
+
+// AllDocsByKeys calls AllDocsByKeysFunc
+func (m *Database) AllDocsByKeys(ctx context.Context, keys []string, opts *couchdb.ViewOptions) (*couchdb.ViewResult, error) {
+	if m.AllDocsByKeysFunc == nil {
+		panic("mocks: unexpected call to Database.AllDocsByKeys")
+	}
+	return m.AllDocsByKeysFunc(ctx, keys, opts)
+}
+
+// AllDocsStream calls AllDocsStreamFunc
+func (m *Database) AllDocsStream(ctx context.Context, opts *couchdb.ViewOptions) (*couchdb.ViewRows, error) {
+	if m.AllDocsStreamFunc == nil {
+		panic("mocks: unexpected call to Database.AllDocsStream")
+	}
+	return m.AllDocsStreamFunc(ctx, opts)
+}
+
+// AttachmentInfo calls AttachmentInfoFunc
+func (m *Database) AttachmentInfo(ctx context.Context, docID string, name string, rev ...string) (*couchdb.AttachmentMeta, error) {
+	if m.AttachmentInfoFunc == nil {
+		panic("mocks: unexpected call to Database.AttachmentInfo")
+	}
+	return m.AttachmentInfoFunc(ctx, docID, name, rev...)
+}
+
+// Bulk calls BulkFunc
+func (m *Database) Bulk(ctx context.Context, docs []interface{}) (couchdb.BulkResponse, error) {
+	if m.BulkFunc == nil {
+		panic("mocks: unexpected call to Database.Bulk")
+	}
+	return m.BulkFunc(ctx, docs)
+}
+
+// BulkGet calls BulkGetFunc
+func (m *Database) BulkGet(ctx context.Context, requests []couchdb.BulkGetRequest, opts *couchdb.BulkGetOptions) (*couchdb.BulkGetResult, error) {
+	if m.BulkGetFunc == nil {
+		panic("mocks: unexpected call to Database.BulkGet")
+	}
+	return m.BulkGetFunc(ctx, requests, opts)
+}
+
+// BulkWithOptions calls BulkWithOptionsFunc
+func (m *Database) BulkWithOptions(ctx context.Context, docs []interface{}, opts *couchdb.BulkOptions) (couchdb.BulkResponse, error) {
+	if m.BulkWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.BulkWithOptions")
+	}
+	return m.BulkWithOptionsFunc(ctx, docs, opts)
+}
+
+// Changes calls ChangesFunc
+func (m *Database) Changes(ctx context.Context, opts map[string]interface{}) (map[string]interface{}, error) {
+	if m.ChangesFunc == nil {
+		panic("mocks: unexpected call to Database.Changes")
+	}
+	return m.ChangesFunc(ctx, opts)
+}
+
+// ChangesFeed calls ChangesFeedFunc
+func (m *Database) ChangesFeed(ctx context.Context, opts *couchdb.ChangesFeedOptions) *couchdb.ChangesFeed {
+	if m.ChangesFeedFunc == nil {
+		panic("mocks: unexpected call to Database.ChangesFeed")
+	}
+	return m.ChangesFeedFunc(ctx, opts)
+}
+
+// Compact calls CompactFunc
+func (m *Database) Compact(ctx context.Context) error {
+	if m.CompactFunc == nil {
+		panic("mocks: unexpected call to Database.Compact")
+	}
+	return m.CompactFunc(ctx)
+}
+
+// CompactAll calls CompactAllFunc
+func (m *Database) CompactAll(ctx context.Context, opts *couchdb.CompactAllOptions) error {
+	if m.CompactAllFunc == nil {
+		panic("mocks: unexpected call to Database.CompactAll")
+	}
+	return m.CompactAllFunc(ctx, opts)
+}
+
+// CompactDesignDoc calls CompactDesignDocFunc
+func (m *Database) CompactDesignDoc(ctx context.Context, designDoc string) error {
+	if m.CompactDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.CompactDesignDoc")
+	}
+	return m.CompactDesignDocFunc(ctx, designDoc)
+}
+
+// Copy calls CopyFunc
+func (m *Database) Copy(ctx context.Context, sourceID string, targetID string, targetRev ...string) (*couchdb.Document, error) {
+	if m.CopyFunc == nil {
+		panic("mocks: unexpected call to Database.Copy")
+	}
+	return m.CopyFunc(ctx, sourceID, targetID, targetRev...)
+}
+
+// Counter calls CounterFunc
+func (m *Database) Counter(name string, clientID string) *couchdb.Counter {
+	if m.CounterFunc == nil {
+		panic("mocks: unexpected call to Database.Counter")
+	}
+	return m.CounterFunc(name, clientID)
+}
+
+// CreateIndex calls CreateIndexFunc
+func (m *Database) CreateIndex(ctx context.Context, index *couchdb.MangoIndex) (*couchdb.CreateIndexResult, error) {
+	if m.CreateIndexFunc == nil {
+		panic("mocks: unexpected call to Database.CreateIndex")
+	}
+	return m.CreateIndexFunc(ctx, index)
+}
+
+// Delete calls DeleteFunc
+func (m *Database) Delete(ctx context.Context, id string, rev string) error {
+	if m.DeleteFunc == nil {
+		panic("mocks: unexpected call to Database.Delete")
+	}
+	return m.DeleteFunc(ctx, id, rev)
+}
+
+// DeleteAttachment calls DeleteAttachmentFunc
+func (m *Database) DeleteAttachment(ctx context.Context, docID string, rev string, name string) (*couchdb.Document, error) {
+	if m.DeleteAttachmentFunc == nil {
+		panic("mocks: unexpected call to Database.DeleteAttachment")
+	}
+	return m.DeleteAttachmentFunc(ctx, docID, rev, name)
+}
+
+// DeleteDesignDoc calls DeleteDesignDocFunc
+func (m *Database) DeleteDesignDoc(ctx context.Context, name string, rev string) error {
+	if m.DeleteDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.DeleteDesignDoc")
+	}
+	return m.DeleteDesignDocFunc(ctx, name, rev)
+}
+
+// DeleteDoc calls DeleteDocFunc
+func (m *Database) DeleteDoc(ctx context.Context, doc interface{}) error {
+	if m.DeleteDocFunc == nil {
+		panic("mocks: unexpected call to Database.DeleteDoc")
+	}
+	return m.DeleteDocFunc(ctx, doc)
+}
+
+// DeleteLocal calls DeleteLocalFunc
+func (m *Database) DeleteLocal(ctx context.Context, id string, rev string) error {
+	if m.DeleteLocalFunc == nil {
+		panic("mocks: unexpected call to Database.DeleteLocal")
+	}
+	return m.DeleteLocalFunc(ctx, id, rev)
+}
+
+// DeleteWithOptions calls DeleteWithOptionsFunc
+func (m *Database) DeleteWithOptions(ctx context.Context, id string, rev string, opts *couchdb.WriteOptions) error {
+	if m.DeleteWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.DeleteWithOptions")
+	}
+	return m.DeleteWithOptionsFunc(ctx, id, rev, opts)
+}
+
+// DeployDesignDocSafely calls DeployDesignDocSafelyFunc
+func (m *Database) DeployDesignDocSafely(ctx context.Context, name string, ddoc *couchdb.DesignDocument, opts *couchdb.DeployOptions) (bool, error) {
+	if m.DeployDesignDocSafelyFunc == nil {
+		panic("mocks: unexpected call to Database.DeployDesignDocSafely")
+	}
+	return m.DeployDesignDocSafelyFunc(ctx, name, ddoc, opts)
+}
+
+// DiffDesignDoc calls DiffDesignDocFunc
+func (m *Database) DiffDesignDoc(ctx context.Context, name string, desired *couchdb.DesignDocument) (*couchdb.DesignDocDiff, error) {
+	if m.DiffDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.DiffDesignDoc")
+	}
+	return m.DiffDesignDocFunc(ctx, name, desired)
+}
+
+// EnsureCRDTViews calls EnsureCRDTViewsFunc
+func (m *Database) EnsureCRDTViews(ctx context.Context) error {
+	if m.EnsureCRDTViewsFunc == nil {
+		panic("mocks: unexpected call to Database.EnsureCRDTViews")
+	}
+	return m.EnsureCRDTViewsFunc(ctx)
+}
+
+// EnsureFieldView calls EnsureFieldViewFunc
+func (m *Database) EnsureFieldView(ctx context.Context, fields ...string) (string, error) {
+	if m.EnsureFieldViewFunc == nil {
+		panic("mocks: unexpected call to Database.EnsureFieldView")
+	}
+	return m.EnsureFieldViewFunc(ctx, fields...)
+}
+
+// EnsureIdempotencyIndex calls EnsureIdempotencyIndexFunc
+func (m *Database) EnsureIdempotencyIndex(ctx context.Context, opts *couchdb.IdempotencyOptions) error {
+	if m.EnsureIdempotencyIndexFunc == nil {
+		panic("mocks: unexpected call to Database.EnsureIdempotencyIndex")
+	}
+	return m.EnsureIdempotencyIndexFunc(ctx, opts)
+}
+
+// Explain calls ExplainFunc
+func (m *Database) Explain(ctx context.Context, query *couchdb.FindQuery) (*couchdb.ExplainResult, error) {
+	if m.ExplainFunc == nil {
+		panic("mocks: unexpected call to Database.Explain")
+	}
+	return m.ExplainFunc(ctx, query)
+}
+
+// Export calls ExportFunc
+func (m *Database) Export(ctx context.Context, w io.Writer, opts *couchdb.ExportOptions) (int, error) {
+	if m.ExportFunc == nil {
+		panic("mocks: unexpected call to Database.Export")
+	}
+	return m.ExportFunc(ctx, w, opts)
+}
+
+// Find calls FindFunc
+func (m *Database) Find(ctx context.Context, query *couchdb.FindQuery) (*couchdb.FindResult, error) {
+	if m.FindFunc == nil {
+		panic("mocks: unexpected call to Database.Find")
+	}
+	return m.FindFunc(ctx, query)
+}
+
+// FindByIdempotencyKey calls FindByIdempotencyKeyFunc
+func (m *Database) FindByIdempotencyKey(ctx context.Context, key string, opts *couchdb.IdempotencyOptions) (*couchdb.Document, error) {
+	if m.FindByIdempotencyKeyFunc == nil {
+		panic("mocks: unexpected call to Database.FindByIdempotencyKey")
+	}
+	return m.FindByIdempotencyKeyFunc(ctx, key, opts)
+}
+
+// Get calls GetFunc
+func (m *Database) Get(ctx context.Context, id string, rev ...string) (*couchdb.Document, error) {
+	if m.GetFunc == nil {
+		panic("mocks: unexpected call to Database.Get")
+	}
+	return m.GetFunc(ctx, id, rev...)
+}
+
+// GetAttachment calls GetAttachmentFunc
+func (m *Database) GetAttachment(ctx context.Context, docID string, name string, rev ...string) (io.ReadCloser, *couchdb.AttachmentMeta, error) {
+	if m.GetAttachmentFunc == nil {
+		panic("mocks: unexpected call to Database.GetAttachment")
+	}
+	return m.GetAttachmentFunc(ctx, docID, name, rev...)
+}
+
+// GetChanges calls GetChangesFunc
+func (m *Database) GetChanges(ctx context.Context, opts *couchdb.ChangesOptions) (*couchdb.ChangesResponse, error) {
+	if m.GetChangesFunc == nil {
+		panic("mocks: unexpected call to Database.GetChanges")
+	}
+	return m.GetChangesFunc(ctx, opts)
+}
+
+// GetConflicts calls GetConflictsFunc
+func (m *Database) GetConflicts(ctx context.Context, id string) (*couchdb.ConflictSet, error) {
+	if m.GetConflictsFunc == nil {
+		panic("mocks: unexpected call to Database.GetConflicts")
+	}
+	return m.GetConflictsFunc(ctx, id)
+}
+
+// GetDesignDoc calls GetDesignDocFunc
+func (m *Database) GetDesignDoc(ctx context.Context, name string) (*couchdb.DesignDocument, error) {
+	if m.GetDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.GetDesignDoc")
+	}
+	return m.GetDesignDocFunc(ctx, name)
+}
+
+// GetInto calls GetIntoFunc
+func (m *Database) GetInto(ctx context.Context, id string, dest interface{}, rev ...string) error {
+	if m.GetIntoFunc == nil {
+		panic("mocks: unexpected call to Database.GetInto")
+	}
+	return m.GetIntoFunc(ctx, id, dest, rev...)
+}
+
+// GetLocal calls GetLocalFunc
+func (m *Database) GetLocal(ctx context.Context, id string) (*couchdb.Document, error) {
+	if m.GetLocalFunc == nil {
+		panic("mocks: unexpected call to Database.GetLocal")
+	}
+	return m.GetLocalFunc(ctx, id)
+}
+
+// GetMultipart calls GetMultipartFunc
+func (m *Database) GetMultipart(ctx context.Context, id string, opts *couchdb.GetOptions) (*couchdb.MultipartDocument, error) {
+	if m.GetMultipartFunc == nil {
+		panic("mocks: unexpected call to Database.GetMultipart")
+	}
+	return m.GetMultipartFunc(ctx, id, opts)
+}
+
+// GetOpenRevs calls GetOpenRevsFunc
+func (m *Database) GetOpenRevs(ctx context.Context, id string, revs []string, opts *couchdb.GetOptions) ([]couchdb.OpenRev, error) {
+	if m.GetOpenRevsFunc == nil {
+		panic("mocks: unexpected call to Database.GetOpenRevs")
+	}
+	return m.GetOpenRevsFunc(ctx, id, revs, opts)
+}
+
+// GetOpenRevsMultipart calls GetOpenRevsMultipartFunc
+func (m *Database) GetOpenRevsMultipart(ctx context.Context, id string, revs []string, opts *couchdb.GetOptions) (*couchdb.OpenRevsReader, error) {
+	if m.GetOpenRevsMultipartFunc == nil {
+		panic("mocks: unexpected call to Database.GetOpenRevsMultipart")
+	}
+	return m.GetOpenRevsMultipartFunc(ctx, id, revs, opts)
+}
+
+// GetRevsLimit calls GetRevsLimitFunc
+func (m *Database) GetRevsLimit(ctx context.Context) (int, error) {
+	if m.GetRevsLimitFunc == nil {
+		panic("mocks: unexpected call to Database.GetRevsLimit")
+	}
+	return m.GetRevsLimitFunc(ctx)
+}
+
+// GetSecurity calls GetSecurityFunc
+func (m *Database) GetSecurity(ctx context.Context) (*couchdb.SecurityObject, error) {
+	if m.GetSecurityFunc == nil {
+		panic("mocks: unexpected call to Database.GetSecurity")
+	}
+	return m.GetSecurityFunc(ctx)
+}
+
+// GetWithOptions calls GetWithOptionsFunc
+func (m *Database) GetWithOptions(ctx context.Context, id string, opts *couchdb.GetOptions) (*couchdb.Document, error) {
+	if m.GetWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.GetWithOptions")
+	}
+	return m.GetWithOptionsFunc(ctx, id, opts)
+}
+
+// Import calls ImportFunc
+func (m *Database) Import(ctx context.Context, r io.Reader, opts *couchdb.ImportOptions) (int, error) {
+	if m.ImportFunc == nil {
+		panic("mocks: unexpected call to Database.Import")
+	}
+	return m.ImportFunc(ctx, r, opts)
+}
+
+// Info calls InfoFunc
+func (m *Database) Info(ctx context.Context) (*couchdb.DatabaseInfo, error) {
+	if m.InfoFunc == nil {
+		panic("mocks: unexpected call to Database.Info")
+	}
+	return m.InfoFunc(ctx)
+}
+
+// ListDesignDocs calls ListDesignDocsFunc
+func (m *Database) ListDesignDocs(ctx context.Context) (*couchdb.ViewResult, error) {
+	if m.ListDesignDocsFunc == nil {
+		panic("mocks: unexpected call to Database.ListDesignDocs")
+	}
+	return m.ListDesignDocsFunc(ctx)
+}
+
+// MissingRevs calls MissingRevsFunc
+func (m *Database) MissingRevs(ctx context.Context, revs map[string][]string) (map[string][]string, error) {
+	if m.MissingRevsFunc == nil {
+		panic("mocks: unexpected call to Database.MissingRevs")
+	}
+	return m.MissingRevsFunc(ctx, revs)
+}
+
+// Name calls NameFunc
+func (m *Database) Name() string {
+	if m.NameFunc == nil {
+		panic("mocks: unexpected call to Database.Name")
+	}
+	return m.NameFunc()
+}
+
+// NewBulkLoader calls NewBulkLoaderFunc
+func (m *Database) NewBulkLoader(ctx context.Context, opts *couchdb.BulkLoaderOptions) *couchdb.BulkLoader {
+	if m.NewBulkLoaderFunc == nil {
+		panic("mocks: unexpected call to Database.NewBulkLoader")
+	}
+	return m.NewBulkLoaderFunc(ctx, opts)
+}
+
+// NewChangesFollower calls NewChangesFollowerFunc
+func (m *Database) NewChangesFollower(sink couchdb.EventSink, store couchdb.CheckpointStore, opts *couchdb.FollowerOptions) *couchdb.ChangesFollower {
+	if m.NewChangesFollowerFunc == nil {
+		panic("mocks: unexpected call to Database.NewChangesFollower")
+	}
+	return m.NewChangesFollowerFunc(sink, store, opts)
+}
+
+// NewChangesProcessor calls NewChangesProcessorFunc
+func (m *Database) NewChangesProcessor(handler couchdb.ChangeHandler, store couchdb.CheckpointStore, opts *couchdb.FollowerOptions) *couchdb.ChangesFollower {
+	if m.NewChangesProcessorFunc == nil {
+		panic("mocks: unexpected call to Database.NewChangesProcessor")
+	}
+	return m.NewChangesProcessorFunc(handler, store, opts)
+}
+
+// NewExpiryReaper calls NewExpiryReaperFunc
+func (m *Database) NewExpiryReaper(opts *couchdb.ExpiryReaperOptions) *couchdb.ExpiryReaper {
+	if m.NewExpiryReaperFunc == nil {
+		panic("mocks: unexpected call to Database.NewExpiryReaper")
+	}
+	return m.NewExpiryReaperFunc(opts)
+}
+
+// NewFindQuery calls NewFindQueryFunc
+func (m *Database) NewFindQuery(selectors ...couchdb.Selector) *couchdb.FindBuilder {
+	if m.NewFindQueryFunc == nil {
+		panic("mocks: unexpected call to Database.NewFindQuery")
+	}
+	return m.NewFindQueryFunc(selectors...)
+}
+
+// NewOutboxDispatcher calls NewOutboxDispatcherFunc
+func (m *Database) NewOutboxDispatcher(handler couchdb.OutboxHandler, store couchdb.CheckpointStore, opts *couchdb.FollowerOptions) *couchdb.OutboxDispatcher {
+	if m.NewOutboxDispatcherFunc == nil {
+		panic("mocks: unexpected call to Database.NewOutboxDispatcher")
+	}
+	return m.NewOutboxDispatcherFunc(handler, store, opts)
+}
+
+// NewViewCache calls NewViewCacheFunc
+func (m *Database) NewViewCache(opts *couchdb.ViewCacheOptions) *couchdb.ViewCache {
+	if m.NewViewCacheFunc == nil {
+		panic("mocks: unexpected call to Database.NewViewCache")
+	}
+	return m.NewViewCacheFunc(opts)
+}
+
+// NewViewQuery calls NewViewQueryFunc
+func (m *Database) NewViewQuery(designDoc string, viewName string) *couchdb.ViewBuilder {
+	if m.NewViewQueryFunc == nil {
+		panic("mocks: unexpected call to Database.NewViewQuery")
+	}
+	return m.NewViewQueryFunc(designDoc, viewName)
+}
+
+// NewViewWarmer calls NewViewWarmerFunc
+func (m *Database) NewViewWarmer(opts *couchdb.ViewWarmerOptions) *couchdb.ViewWarmer {
+	if m.NewViewWarmerFunc == nil {
+		panic("mocks: unexpected call to Database.NewViewWarmer")
+	}
+	return m.NewViewWarmerFunc(opts)
+}
+
+// PaginateAllDocs calls PaginateAllDocsFunc
+func (m *Database) PaginateAllDocs(opts *couchdb.ViewOptions, pageSize int) *couchdb.Paginator {
+	if m.PaginateAllDocsFunc == nil {
+		panic("mocks: unexpected call to Database.PaginateAllDocs")
+	}
+	return m.PaginateAllDocsFunc(opts, pageSize)
+}
+
+// PaginateFind calls PaginateFindFunc
+func (m *Database) PaginateFind(query *couchdb.FindQuery, pageSize int) *couchdb.Paginator {
+	if m.PaginateFindFunc == nil {
+		panic("mocks: unexpected call to Database.PaginateFind")
+	}
+	return m.PaginateFindFunc(query, pageSize)
+}
+
+// PaginateView calls PaginateViewFunc
+func (m *Database) PaginateView(designDoc string, viewName string, opts *couchdb.ViewOptions, pageSize int) *couchdb.Paginator {
+	if m.PaginateViewFunc == nil {
+		panic("mocks: unexpected call to Database.PaginateView")
+	}
+	return m.PaginateViewFunc(designDoc, viewName, opts, pageSize)
+}
+
+// PurgeSeq calls PurgeSeqFunc
+func (m *Database) PurgeSeq(ctx context.Context) (string, error) {
+	if m.PurgeSeqFunc == nil {
+		panic("mocks: unexpected call to Database.PurgeSeq")
+	}
+	return m.PurgeSeqFunc(ctx)
+}
+
+// PurgedSince calls PurgedSinceFunc
+func (m *Database) PurgedSince(ctx context.Context, checkpoint string) (bool, string, error) {
+	if m.PurgedSinceFunc == nil {
+		panic("mocks: unexpected call to Database.PurgedSince")
+	}
+	return m.PurgedSinceFunc(ctx, checkpoint)
+}
+
+// Put calls PutFunc
+func (m *Database) Put(ctx context.Context, doc interface{}) (*couchdb.Document, error) {
+	if m.PutFunc == nil {
+		panic("mocks: unexpected call to Database.Put")
+	}
+	return m.PutFunc(ctx, doc)
+}
+
+// PutAttachment calls PutAttachmentFunc
+func (m *Database) PutAttachment(ctx context.Context, docID string, rev string, name string, contentType string, data []byte) (*couchdb.Document, error) {
+	if m.PutAttachmentFunc == nil {
+		panic("mocks: unexpected call to Database.PutAttachment")
+	}
+	return m.PutAttachmentFunc(ctx, docID, rev, name, contentType, data)
+}
+
+// PutAttachmentStream calls PutAttachmentStreamFunc
+func (m *Database) PutAttachmentStream(ctx context.Context, docID string, rev string, name string, contentType string, body io.Reader, size int64) (*couchdb.Document, error) {
+	if m.PutAttachmentStreamFunc == nil {
+		panic("mocks: unexpected call to Database.PutAttachmentStream")
+	}
+	return m.PutAttachmentStreamFunc(ctx, docID, rev, name, contentType, body, size)
+}
+
+// PutDesignDoc calls PutDesignDocFunc
+func (m *Database) PutDesignDoc(ctx context.Context, name string, designDoc *couchdb.DesignDocument) (*couchdb.Document, error) {
+	if m.PutDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.PutDesignDoc")
+	}
+	return m.PutDesignDocFunc(ctx, name, designDoc)
+}
+
+// PutIdempotent calls PutIdempotentFunc
+func (m *Database) PutIdempotent(ctx context.Context, key string, doc interface{}, opts *couchdb.IdempotencyOptions) (*couchdb.Document, error) {
+	if m.PutIdempotentFunc == nil {
+		panic("mocks: unexpected call to Database.PutIdempotent")
+	}
+	return m.PutIdempotentFunc(ctx, key, doc, opts)
+}
+
+// PutLocal calls PutLocalFunc
+func (m *Database) PutLocal(ctx context.Context, id string, doc interface{}) (*couchdb.Document, error) {
+	if m.PutLocalFunc == nil {
+		panic("mocks: unexpected call to Database.PutLocal")
+	}
+	return m.PutLocalFunc(ctx, id, doc)
+}
+
+// PutMultipart calls PutMultipartFunc
+func (m *Database) PutMultipart(ctx context.Context, doc interface{}, attachments ...*couchdb.AttachmentUpload) (*couchdb.Document, error) {
+	if m.PutMultipartFunc == nil {
+		panic("mocks: unexpected call to Database.PutMultipart")
+	}
+	return m.PutMultipartFunc(ctx, doc, attachments...)
+}
+
+// PutWithOptions calls PutWithOptionsFunc
+func (m *Database) PutWithOptions(ctx context.Context, doc interface{}, opts *couchdb.WriteOptions) (*couchdb.Document, error) {
+	if m.PutWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.PutWithOptions")
+	}
+	return m.PutWithOptionsFunc(ctx, doc, opts)
+}
+
+// Q calls QFunc
+func (m *Database) Q(viewName string) *couchdb.ViewBuilder {
+	if m.QFunc == nil {
+		panic("mocks: unexpected call to Database.Q")
+	}
+	return m.QFunc(viewName)
+}
+
+// RemoveAdmin calls RemoveAdminFunc
+func (m *Database) RemoveAdmin(ctx context.Context, name string) error {
+	if m.RemoveAdminFunc == nil {
+		panic("mocks: unexpected call to Database.RemoveAdmin")
+	}
+	return m.RemoveAdminFunc(ctx, name)
+}
+
+// RemoveAdminRole calls RemoveAdminRoleFunc
+func (m *Database) RemoveAdminRole(ctx context.Context, role string) error {
+	if m.RemoveAdminRoleFunc == nil {
+		panic("mocks: unexpected call to Database.RemoveAdminRole")
+	}
+	return m.RemoveAdminRoleFunc(ctx, role)
+}
+
+// RemoveMember calls RemoveMemberFunc
+func (m *Database) RemoveMember(ctx context.Context, name string) error {
+	if m.RemoveMemberFunc == nil {
+		panic("mocks: unexpected call to Database.RemoveMember")
+	}
+	return m.RemoveMemberFunc(ctx, name)
+}
+
+// RemoveMemberRole calls RemoveMemberRoleFunc
+func (m *Database) RemoveMemberRole(ctx context.Context, role string) error {
+	if m.RemoveMemberRoleFunc == nil {
+		panic("mocks: unexpected call to Database.RemoveMemberRole")
+	}
+	return m.RemoveMemberRoleFunc(ctx, role)
+}
+
+// ResolveConflict calls ResolveConflictFunc
+func (m *Database) ResolveConflict(ctx context.Context, id string, winner interface{}, losingRevs ...string) (couchdb.BulkResponse, error) {
+	if m.ResolveConflictFunc == nil {
+		panic("mocks: unexpected call to Database.ResolveConflict")
+	}
+	return m.ResolveConflictFunc(ctx, id, winner, losingRevs...)
+}
+
+// Restore calls RestoreFunc
+func (m *Database) Restore(ctx context.Context, id string) (*couchdb.Document, error) {
+	if m.RestoreFunc == nil {
+		panic("mocks: unexpected call to Database.Restore")
+	}
+	return m.RestoreFunc(ctx, id)
+}
+
+// RevsDiff calls RevsDiffFunc
+func (m *Database) RevsDiff(ctx context.Context, revs map[string][]string) (map[string]couchdb.RevsDiffResult, error) {
+	if m.RevsDiffFunc == nil {
+		panic("mocks: unexpected call to Database.RevsDiff")
+	}
+	return m.RevsDiffFunc(ctx, revs)
+}
+
+// Rewrite calls RewriteFunc
+func (m *Database) Rewrite(ctx context.Context, designDoc string, path string, method string, body interface{}) (*couchdb.RewriteResult, error) {
+	if m.RewriteFunc == nil {
+		panic("mocks: unexpected call to Database.Rewrite")
+	}
+	return m.RewriteFunc(ctx, designDoc, path, method, body)
+}
+
+// Search calls SearchFunc
+func (m *Database) Search(ctx context.Context, designDoc string, indexName string, query *couchdb.SearchQuery) (*couchdb.SearchResult, error) {
+	if m.SearchFunc == nil {
+		panic("mocks: unexpected call to Database.Search")
+	}
+	return m.SearchFunc(ctx, designDoc, indexName, query)
+}
+
+// Set calls SetFunc
+func (m *Database) Set(name string, clientID string) *couchdb.Set {
+	if m.SetFunc == nil {
+		panic("mocks: unexpected call to Database.Set")
+	}
+	return m.SetFunc(name, clientID)
+}
+
+// SetRevsLimit calls SetRevsLimitFunc
+func (m *Database) SetRevsLimit(ctx context.Context, limit int) error {
+	if m.SetRevsLimitFunc == nil {
+		panic("mocks: unexpected call to Database.SetRevsLimit")
+	}
+	return m.SetRevsLimitFunc(ctx, limit)
+}
+
+// SetSecurity calls SetSecurityFunc
+func (m *Database) SetSecurity(ctx context.Context, security *couchdb.SecurityObject) error {
+	if m.SetSecurityFunc == nil {
+		panic("mocks: unexpected call to Database.SetSecurity")
+	}
+	return m.SetSecurityFunc(ctx, security)
+}
+
+// Subscribe calls SubscribeFunc
+func (m *Database) Subscribe(ctx context.Context, opts couchdb.SubscribeOptions) *couchdb.Subscription {
+	if m.SubscribeFunc == nil {
+		panic("mocks: unexpected call to Database.Subscribe")
+	}
+	return m.SubscribeFunc(ctx, opts)
+}
+
+// SyncDesignDocs calls SyncDesignDocsFunc
+func (m *Database) SyncDesignDocs(ctx context.Context, fsys fs.FS) ([]string, error) {
+	if m.SyncDesignDocsFunc == nil {
+		panic("mocks: unexpected call to Database.SyncDesignDocs")
+	}
+	return m.SyncDesignDocsFunc(ctx, fsys)
+}
+
+// Update calls UpdateFunc
+func (m *Database) Update(ctx context.Context, id string, doc interface{}) (*couchdb.Document, error) {
+	if m.UpdateFunc == nil {
+		panic("mocks: unexpected call to Database.Update")
+	}
+	return m.UpdateFunc(ctx, id, doc)
+}
+
+// UpdateHandler calls UpdateHandlerFunc
+func (m *Database) UpdateHandler(ctx context.Context, designDoc string, handlerName string, docID string, body interface{}) (*couchdb.UpdateHandlerResult, error) {
+	if m.UpdateHandlerFunc == nil {
+		panic("mocks: unexpected call to Database.UpdateHandler")
+	}
+	return m.UpdateHandlerFunc(ctx, designDoc, handlerName, docID, body)
+}
+
+// UpdateWithOptions calls UpdateWithOptionsFunc
+func (m *Database) UpdateWithOptions(ctx context.Context, id string, doc interface{}, opts *couchdb.WriteOptions) (*couchdb.Document, error) {
+	if m.UpdateWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.UpdateWithOptions")
+	}
+	return m.UpdateWithOptionsFunc(ctx, id, doc, opts)
+}
+
+// Upsert calls UpsertFunc
+func (m *Database) Upsert(ctx context.Context, id string, mutate couchdb.UpsertFunc) (*couchdb.Document, error) {
+	if m.UpsertFunc == nil {
+		panic("mocks: unexpected call to Database.Upsert")
+	}
+	return m.UpsertFunc(ctx, id, mutate)
+}
+
+// UpsertWithOptions calls UpsertWithOptionsFunc
+func (m *Database) UpsertWithOptions(ctx context.Context, id string, mutate couchdb.UpsertFunc, opts *couchdb.UpsertOptions) (*couchdb.Document, error) {
+	if m.UpsertWithOptionsFunc == nil {
+		panic("mocks: unexpected call to Database.UpsertWithOptions")
+	}
+	return m.UpsertWithOptionsFunc(ctx, id, mutate, opts)
+}
+
+// View calls ViewFunc
+func (m *Database) View(ctx context.Context, designDoc string, viewName string, opts *couchdb.ViewOptions) (*couchdb.ViewResult, error) {
+	if m.ViewFunc == nil {
+		panic("mocks: unexpected call to Database.View")
+	}
+	return m.ViewFunc(ctx, designDoc, viewName, opts)
+}
+
+// ViewAll calls ViewAllFunc
+func (m *Database) ViewAll(ctx context.Context, designDoc string, viewName string, includeDocs bool) (*couchdb.ViewResult, error) {
+	if m.ViewAllFunc == nil {
+		panic("mocks: unexpected call to Database.ViewAll")
+	}
+	return m.ViewAllFunc(ctx, designDoc, viewName, includeDocs)
+}
+
+// ViewByKey calls ViewByKeyFunc
+func (m *Database) ViewByKey(ctx context.Context, designDoc string, viewName string, key interface{}) (*couchdb.ViewResult, error) {
+	if m.ViewByKeyFunc == nil {
+		panic("mocks: unexpected call to Database.ViewByKey")
+	}
+	return m.ViewByKeyFunc(ctx, designDoc, viewName, key)
+}
+
+// ViewByKeyRange calls ViewByKeyRangeFunc
+func (m *Database) ViewByKeyRange(ctx context.Context, designDoc string, viewName string, startKey interface{}, endKey interface{}) (*couchdb.ViewResult, error) {
+	if m.ViewByKeyRangeFunc == nil {
+		panic("mocks: unexpected call to Database.ViewByKeyRange")
+	}
+	return m.ViewByKeyRangeFunc(ctx, designDoc, viewName, startKey, endKey)
+}
+
+// ViewCleanup calls ViewCleanupFunc
+func (m *Database) ViewCleanup(ctx context.Context) error {
+	if m.ViewCleanupFunc == nil {
+		panic("mocks: unexpected call to Database.ViewCleanup")
+	}
+	return m.ViewCleanupFunc(ctx)
+}
+
+// ViewInfo calls ViewInfoFunc
+func (m *Database) ViewInfo(ctx context.Context, designDoc string, viewName string) (map[string]interface{}, error) {
+	if m.ViewInfoFunc == nil {
+		panic("mocks: unexpected call to Database.ViewInfo")
+	}
+	return m.ViewInfoFunc(ctx, designDoc, viewName)
+}
+
+// ViewReduce calls ViewReduceFunc
+func (m *Database) ViewReduce(ctx context.Context, designDoc string, viewName string, groupLevel int) (*couchdb.ViewResult, error) {
+	if m.ViewReduceFunc == nil {
+		panic("mocks: unexpected call to Database.ViewReduce")
+	}
+	return m.ViewReduceFunc(ctx, designDoc, viewName, groupLevel)
+}
+
+// ViewStream calls ViewStreamFunc
+func (m *Database) ViewStream(ctx context.Context, designDoc string, viewName string, opts *couchdb.ViewOptions) (*couchdb.ViewRows, error) {
+	if m.ViewStreamFunc == nil {
+		panic("mocks: unexpected call to Database.ViewStream")
+	}
+	return m.ViewStreamFunc(ctx, designDoc, viewName, opts)
+}
+
+// ViewWithKeys calls ViewWithKeysFunc
+func (m *Database) ViewWithKeys(ctx context.Context, designDoc string, viewName string, keys []interface{}, opts *couchdb.ViewOptions) (*couchdb.ViewResult, error) {
+	if m.ViewWithKeysFunc == nil {
+		panic("mocks: unexpected call to Database.ViewWithKeys")
+	}
+	return m.ViewWithKeysFunc(ctx, designDoc, viewName, keys, opts)
+}
+
+// WaitForCompaction calls WaitForCompactionFunc
+func (m *Database) WaitForCompaction(ctx context.Context, pollInterval time.Duration, progress func(couchdb.CompactionProgress)) error {
+	if m.WaitForCompactionFunc == nil {
+		panic("mocks: unexpected call to Database.WaitForCompaction")
+	}
+	return m.WaitForCompactionFunc(ctx, pollInterval, progress)
+}
+
+// WaitForViewCompaction calls WaitForViewCompactionFunc
+func (m *Database) WaitForViewCompaction(ctx context.Context, designDoc string, pollInterval time.Duration, progress func(couchdb.CompactionProgress)) error {
+	if m.WaitForViewCompactionFunc == nil {
+		panic("mocks: unexpected call to Database.WaitForViewCompaction")
+	}
+	return m.WaitForViewCompactionFunc(ctx, designDoc, pollInterval, progress)
+}
+
+// WarmViews calls WarmViewsFunc
+func (m *Database) WarmViews(ctx context.Context, designDoc string, opts *couchdb.WarmOptions) error {
+	if m.WarmViewsFunc == nil {
+		panic("mocks: unexpected call to Database.WarmViews")
+	}
+	return m.WarmViewsFunc(ctx, designDoc, opts)
+}
+
+// WithDesignDoc calls WithDesignDocFunc
+func (m *Database) WithDesignDoc(designDoc string) *couchdb.Database {
+	if m.WithDesignDocFunc == nil {
+		panic("mocks: unexpected call to Database.WithDesignDoc")
+	}
+	return m.WithDesignDocFunc(designDoc)
+}
+
+// WithIDGenerator calls WithIDGeneratorFunc
+func (m *Database) WithIDGenerator(gen couchdb.IDGenerator) *couchdb.Database {
+	if m.WithIDGeneratorFunc == nil {
+		panic("mocks: unexpected call to Database.WithIDGenerator")
+	}
+	return m.WithIDGeneratorFunc(gen)
+}
+
+// WithSoftDelete calls WithSoftDeleteFunc
+func (m *Database) WithSoftDelete(opts *couchdb.SoftDeleteOptions) *couchdb.Database {
+	if m.WithSoftDeleteFunc == nil {
+		panic("mocks: unexpected call to Database.WithSoftDelete")
+	}
+	return m.WithSoftDeleteFunc(opts)
+}
+
+// WithTimestamps calls WithTimestampsFunc
+func (m *Database) WithTimestamps(opts *couchdb.TimestampOptions) *couchdb.Database {
+	if m.WithTimestampsFunc == nil {
+		panic("mocks: unexpected call to Database.WithTimestamps")
+	}
+	return m.WithTimestampsFunc(opts)
+}
+
+// WithValidator calls WithValidatorFunc
+func (m *Database) WithValidator(v couchdb.DocValidator) *couchdb.Database {
+	if m.WithValidatorFunc == nil {
+		panic("mocks: unexpected call to Database.WithValidator")
+	}
+	return m.WithValidatorFunc(v)
+}
+
+// WithWriteHook calls WithWriteHookFunc
+func (m *Database) WithWriteHook(hook couchdb.WriteHook) *couchdb.Database {
+	if m.WithWriteHookFunc == nil {
+		panic("mocks: unexpected call to Database.WithWriteHook")
+	}
+	return m.WithWriteHookFunc(hook)
+}
+
+// WriteWithEvents calls WriteWithEventsFunc
+func (m *Database) WriteWithEvents(ctx context.Context, docs []interface{}, events ...*couchdb.OutboxEvent) (couchdb.BulkResponse, error) {
+	if m.WriteWithEventsFunc == nil {
+		panic("mocks: unexpected call to Database.WriteWithEvents")
+	}
+	return m.WriteWithEventsFunc(ctx, docs, events...)
+}
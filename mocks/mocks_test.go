@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userName stands in for application code that depends on the interface
+func userName(ctx context.Context, db couchdb.DatabaseAPI, id string) (string, error) {
+	doc, err := db.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	name, _ := doc.Data["name"].(string)
+	return name, nil
+}
+
+func TestDatabaseMock(t *testing.T) {
+	var gotID string
+	db := &Database{
+		GetFunc: func(ctx context.Context, id string, rev ...string) (*couchdb.Document, error) {
+			gotID = id
+			if id == "missing" {
+				return nil, &couchdb.Error{StatusCode: 404, Type: "not_found", Reason: "missing"}
+			}
+			return &couchdb.Document{ID: id, Data: map[string]interface{}{"name": "Ann"}}, nil
+		},
+	}
+
+	name, err := userName(context.Background(), db, "user:1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ann", name)
+	assert.Equal(t, "user:1", gotID)
+
+	_, err = userName(context.Background(), db, "missing")
+	assert.True(t, couchdb.IsNotFound(err))
+
+	assert.PanicsWithValue(t, "mocks: unexpected call to Database.Info", func() {
+		_, _ = db.Info(context.Background())
+	})
+}
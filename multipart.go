@@ -0,0 +1,360 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// AttachmentUpload is an attachment sent along with its document by PutMultipart
+type AttachmentUpload struct {
+	Name        string
+	ContentType string
+	Body        io.Reader
+	Length      int64 // exact size of Body in bytes, required by the server
+}
+
+// PutMultipart creates or updates a document together with its attachments
+// in a single multipart/related request. The document must have an _id.
+// Attachment bodies are streamed, not buffered. Existing attachment stubs in
+// the document are kept; an upload with the same name replaces the stub.
+func (db *Database) PutMultipart(ctx context.Context, doc interface{}, attachments ...*AttachmentUpload) (*Document, error) {
+	body, err := toMap(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := body["_id"].(string)
+	if id == "" {
+		return nil, errors.New("put multipart: document has no _id")
+	}
+
+	// Parts must follow the order of _attachments, which encoding/json sorts by name
+	uploads := append([]*AttachmentUpload(nil), attachments...)
+	sort.Slice(uploads, func(i, j int) bool { return uploads[i].Name < uploads[j].Name })
+
+	stubs, _ := body["_attachments"].(map[string]interface{})
+	if stubs == nil {
+		stubs = make(map[string]interface{})
+	}
+	for _, a := range uploads {
+		if a.Length < 0 {
+			return nil, fmt.Errorf("put multipart: attachment %s has no length", a.Name)
+		}
+		stubs[a.Name] = map[string]interface{}{
+			"follows":      true,
+			"content_type": a.ContentType,
+			"length":       a.Length,
+		}
+	}
+	if len(stubs) > 0 {
+		body["_attachments"] = stubs
+	}
+
+	docJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.client.checkDocumentSize(docJSON, -1); err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	size, err := multipartSize(boundary, docJSON, uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeMultipart(pw, boundary, docJSON, uploads, true))
+	}()
+	defer pr.Close()
+
+	ctx = context.WithValue(ctx, contentLengthKey{}, size)
+
+	var result struct {
+		ID  string `json:"id"`
+		Rev string `json:"rev"`
+	}
+
+	resp, err := db.client.stream.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "multipart/related; boundary="+boundary).
+		SetBody(pr).
+		SetResult(&result).
+		Put(db.docPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &Document{ID: result.ID, Rev: result.Rev}, nil
+}
+
+// writeMultipart writes the document followed by one part per attachment.
+// Without bodies only the framing is written, see multipartSize.
+func writeMultipart(w io.Writer, boundary string, docJSON []byte, uploads []*AttachmentUpload, withBodies bool) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(docJSON); err != nil {
+		return err
+	}
+
+	for _, a := range uploads {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":        {a.ContentType},
+			"Content-Disposition": {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		})
+		if err != nil {
+			return err
+		}
+		if !withBodies {
+			continue
+		}
+
+		n, err := io.Copy(part, a.Body)
+		if err != nil {
+			return fmt.Errorf("attachment %s: %w", a.Name, err)
+		}
+		if n != a.Length {
+			return fmt.Errorf("attachment %s: read %d bytes, expected %d", a.Name, n, a.Length)
+		}
+	}
+
+	return mw.Close()
+}
+
+// multipartSize computes the request length up front, so the body can be
+// streamed with a Content-Length instead of chunked encoding
+func multipartSize(boundary string, docJSON []byte, uploads []*AttachmentUpload) (int64, error) {
+	counter := &countingWriter{}
+	if err := writeMultipart(counter, boundary, docJSON, uploads, false); err != nil {
+		return 0, err
+	}
+
+	size := counter.n
+	for _, a := range uploads {
+		size += a.Length
+	}
+	return size, nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// MultipartDocument is a document read together with its attachments by
+// GetMultipart. Read the attachments in order with NextAttachment, then Close.
+type MultipartDocument struct {
+	Doc *Document
+
+	body   io.ReadCloser
+	reader *multipart.Reader
+}
+
+// AttachmentPart is one streamed attachment of a MultipartDocument. Body is
+// only valid until the next call to NextAttachment.
+type AttachmentPart struct {
+	Name        string
+	ContentType string
+	Body        io.Reader
+}
+
+// GetMultipart retrieves a document with the content of its attachments as
+// a multipart/related response, so attachments are streamed instead of
+// being base64 encoded inside the JSON. opts.Attachments is always set.
+func (db *Database) GetMultipart(ctx context.Context, id string, opts *GetOptions) (*MultipartDocument, error) {
+	var o GetOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Attachments = true
+
+	resp, err := db.client.stream.R().
+		SetContext(ctx).
+		SetQueryParams(o.queryParams()).
+		SetHeader("Accept", "multipart/related").
+		SetDoNotParseResponse(true).
+		Get(db.docPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := resp.RawBody()
+	if resp.IsError() {
+		defer body.Close()
+		return nil, db.client.parseStreamError(resp)
+	}
+
+	md := &MultipartDocument{body: body, Doc: &Document{}}
+
+	// Documents without attachments come back as plain JSON
+	mediaType, params, err := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		defer body.Close()
+		if err := json.NewDecoder(body).Decode(md.Doc); err != nil {
+			return nil, fmt.Errorf("decode document: %w", err)
+		}
+		return md, nil
+	}
+
+	md.reader = multipart.NewReader(body, params["boundary"])
+	part, err := md.reader.NextPart()
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("read document part: %w", err)
+	}
+	if err := json.NewDecoder(part).Decode(md.Doc); err != nil {
+		body.Close()
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+
+	return md, nil
+}
+
+// NextAttachment returns the next attachment, or io.EOF when there are no more
+func (md *MultipartDocument) NextAttachment() (*AttachmentPart, error) {
+	if md.reader == nil {
+		return nil, io.EOF
+	}
+
+	part, err := md.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachmentPart{
+		Name:        part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Body:        part,
+	}, nil
+}
+
+// Close releases the connection
+func (md *MultipartDocument) Close() error {
+	return md.body.Close()
+}
+
+// OpenRevsReader streams the revisions of a multipart open_revs response,
+// see GetOpenRevsMultipart. Read them in order with Next, then Close.
+type OpenRevsReader struct {
+	body   io.ReadCloser
+	reader *multipart.Reader
+}
+
+// OpenRevPart is one entry of an OpenRevsReader: either a document with
+// its attachments or a revision that could not be found. Doc is only valid
+// until the next call to Next and need not be closed.
+type OpenRevPart struct {
+	Doc     *MultipartDocument
+	Missing string
+}
+
+// GetOpenRevsMultipart retrieves the given revisions of a document, or all
+// leaf revisions with no revs, as a multipart/mixed response. This is how
+// replicators fetch revisions: attachments are streamed instead of being
+// base64 encoded inside the JSON. With opts.Attachments unset, only
+// attachment stubs are returned.
+func (db *Database) GetOpenRevsMultipart(ctx context.Context, id string, revs []string, opts *GetOptions) (*OpenRevsReader, error) {
+	params, err := openRevsParams(revs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := db.client.stream.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		SetHeader("Accept", "multipart/mixed").
+		SetDoNotParseResponse(true).
+		Get(db.docPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := resp.RawBody()
+	if resp.IsError() {
+		defer body.Close()
+		return nil, db.client.parseStreamError(resp)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		body.Close()
+		return nil, fmt.Errorf("open revs: unexpected content type %q", resp.Header().Get("Content-Type"))
+	}
+
+	return &OpenRevsReader{body: body, reader: multipart.NewReader(body, params["boundary"])}, nil
+}
+
+// Next returns the next revision, or io.EOF when there are no more
+func (r *OpenRevsReader) Next() (*OpenRevPart, error) {
+	part, err := r.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, params, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, fmt.Errorf("open revs: %w", err)
+	}
+
+	// A revision with attachments is a nested multipart/related part, the
+	// document followed by the attachments
+	md := &MultipartDocument{body: io.NopCloser(part), Doc: &Document{}}
+	docPart := io.Reader(part)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		md.reader = multipart.NewReader(part, params["boundary"])
+		if docPart, err = md.reader.NextPart(); err != nil {
+			return nil, fmt.Errorf("read document part: %w", err)
+		}
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(docPart).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+
+	var missing struct {
+		ID      string `json:"_id"`
+		Missing string `json:"missing"`
+	}
+	if err := json.Unmarshal(raw, &missing); err == nil && missing.ID == "" && missing.Missing != "" {
+		return &OpenRevPart{Missing: missing.Missing}, nil
+	}
+
+	if err := json.Unmarshal(raw, md.Doc); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	return &OpenRevPart{Doc: md}, nil
+}
+
+// Close releases the connection
+func (r *OpenRevsReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,155 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Node methods
+
+// LocalNode refers to the node handling the request
+const LocalNode = "_local"
+
+// NodePrometheus returns the node's native Prometheus metrics in text exposition format (CouchDB 3.2+)
+func (c *Client) NodePrometheus(ctx context.Context, node string) ([]byte, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetHeader("Accept", "text/plain").
+		Get(buildPath("_node", node, "_prometheus"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return resp.Body(), nil
+}
+
+// GetConfig returns the complete configuration of a node, keyed by section
+func (c *Client) GetConfig(ctx context.Context, node string) (map[string]map[string]string, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var result map[string]map[string]string
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get(buildPath("_node", node, "_config"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return result, nil
+}
+
+// GetConfigSection returns all key/value pairs of a node configuration section
+func (c *Client) GetConfigSection(ctx context.Context, node, section string) (map[string]string, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var result map[string]string
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get(buildPath("_node", node, "_config", section))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return result, nil
+}
+
+// GetConfigKey returns a single node configuration value
+func (c *Client) GetConfigKey(ctx context.Context, node, section, key string) (string, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var value string
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&value).
+		Get(buildPath("_node", node, "_config", section, key))
+
+	if err != nil {
+		return "", err
+	}
+
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	return value, nil
+}
+
+// SetConfigKey sets a single node configuration value and returns the previous value
+func (c *Client) SetConfigKey(ctx context.Context, node, section, key, value string) (string, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	// The config API expects the value as a JSON string
+	body, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	var previous string
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&previous).
+		Put(buildPath("_node", node, "_config", section, key))
+
+	if err != nil {
+		return "", err
+	}
+
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	return previous, nil
+}
+
+// DeleteConfigKey removes a single node configuration value and returns the previous value
+func (c *Client) DeleteConfigKey(ctx context.Context, node, section, key string) (string, error) {
+	if node == "" {
+		node = LocalNode
+	}
+
+	var previous string
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&previous).
+		Delete(buildPath("_node", node, "_config", section, key))
+
+	if err != nil {
+		return "", err
+	}
+
+	if resp.IsError() {
+		return "", c.parseError(resp)
+	}
+
+	return previous, nil
+}
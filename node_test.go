@@ -0,0 +1,130 @@
+package couchdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigAPI(t *testing.T) {
+	config := map[string]string{"max_dbs_open": "500"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/_node/_local/_config":
+			_, _ = w.Write([]byte(`{"couchdb":{"max_dbs_open":"` + config["max_dbs_open"] + `"}}`))
+		case r.URL.Path == "/_node/_local/_config/couchdb/max_dbs_open" && r.Method == "GET":
+			_, _ = w.Write([]byte(`"` + config["max_dbs_open"] + `"`))
+		case r.URL.Path == "/_node/_local/_config/couchdb/max_dbs_open" && r.Method == "PUT":
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, `"1000"`, string(body), "values must be sent as JSON strings")
+			_, _ = w.Write([]byte(`"` + config["max_dbs_open"] + `"`))
+			config["max_dbs_open"] = "1000"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"unknown_config_value"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	all, err := client.GetConfig(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "500", all["couchdb"]["max_dbs_open"])
+
+	previous, err := client.SetConfigKey(ctx, "", "couchdb", "max_dbs_open", "1000")
+	require.NoError(t, err)
+	assert.Equal(t, "500", previous)
+
+	value, err := client.GetConfigKey(ctx, LocalNode, "couchdb", "max_dbs_open")
+	require.NoError(t, err)
+	assert.Equal(t, "1000", value)
+
+	_, err = client.GetConfigKey(ctx, "", "couchdb", "missing")
+	assert.True(t, isStatus(err, http.StatusNotFound))
+}
+
+func TestConfigureCORS(t *testing.T) {
+	var mu sync.Mutex
+	written := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/_membership" {
+			_, _ = w.Write([]byte(`{"all_nodes":["a@x","b@x"],"cluster_nodes":["a@x","b@x"]}`))
+			return
+		}
+
+		assert.Equal(t, http.MethodPut, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		written[r.URL.Path] = string(body)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`""`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	err := client.ConfigureCORS(ctx, CORSConfig{
+		Origins:     []string{"https://app.example.com", "https://admin.example.com"},
+		Credentials: true,
+		Headers:     []string{"X-Request-ID"},
+	})
+	require.NoError(t, err)
+
+	for _, node := range []string{"a@x", "b@x"} {
+		prefix := "/_node/" + node + "/_config/"
+		assert.Equal(t, `"true"`, written[prefix+"chttpd/enable_cors"])
+		assert.Equal(t, `"https://app.example.com, https://admin.example.com"`, written[prefix+"cors/origins"])
+		assert.Equal(t, `"true"`, written[prefix+"cors/credentials"])
+		assert.Equal(t, `"X-Request-ID"`, written[prefix+"cors/headers"])
+		assert.NotContains(t, written, prefix+"cors/methods")
+	}
+
+	err = client.ConfigureCORS(ctx, CORSConfig{Origins: []string{"*"}, Credentials: true})
+	assert.Error(t, err)
+	assert.Error(t, client.ConfigureCORS(ctx, CORSConfig{}))
+}
+
+func TestNodePrometheus(t *testing.T) {
+	const exposition = "# TYPE couchdb_uptime_seconds counter\ncouchdb_uptime_seconds 42\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "text/plain", r.Header.Get("Accept"))
+
+		switch r.URL.EscapedPath() {
+		case "/_node/_local/_prometheus":
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			_, _ = w.Write([]byte(exposition))
+		case "/_node/couchdb@db1.example.com/_prometheus":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	body, err := client.NodePrometheus(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, exposition, string(body))
+
+	// CouchDB before 3.2 has no _prometheus endpoint
+	_, err = client.NodePrometheus(ctx, "couchdb@db1.example.com")
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,135 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	outboxDocType  = "outbox_event"
+	outboxIDPrefix = "outbox:"
+)
+
+// OutboxEvent is an event stored alongside the documents that produced it
+type OutboxEvent struct {
+	ID          string      `json:"_id,omitempty"`
+	Rev         string      `json:"_rev,omitempty"`
+	DocType     string      `json:"type"`
+	EventType   string      `json:"event_type"`
+	Payload     interface{} `json:"payload,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Delivered   bool        `json:"delivered"`
+	DeliveredAt *time.Time  `json:"delivered_at,omitempty"`
+}
+
+// NewOutboxEvent creates an undelivered outbox event
+func NewOutboxEvent(eventType string, payload interface{}) *OutboxEvent {
+	return &OutboxEvent{
+		DocType:   outboxDocType,
+		EventType: eventType,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// WriteWithEvents stores docs and outbox events in a single _bulk_docs call.
+// CouchDB does not make the call atomic, so check the returned results for
+// per-document failures; events are only dispatched once they are stored.
+func (db *Database) WriteWithEvents(ctx context.Context, docs []interface{}, events ...*OutboxEvent) (BulkResponse, error) {
+	all := make([]interface{}, 0, len(docs)+len(events))
+	all = append(all, docs...)
+
+	for _, event := range events {
+		if event.ID == "" {
+			id, err := randomID()
+			if err != nil {
+				return nil, err
+			}
+			event.ID = outboxIDPrefix + id
+		}
+		event.DocType = outboxDocType
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now().UTC()
+		}
+		all = append(all, event)
+	}
+
+	return db.Bulk(ctx, all)
+}
+
+// OutboxHandler publishes a single outbox event
+type OutboxHandler func(ctx context.Context, event *OutboxEvent) error
+
+// OutboxDispatcher follows the changes feed, publishes undelivered outbox
+// events and marks them delivered. Like ChangesFollower it delivers
+// at-least-once, so handlers should tolerate duplicates.
+type OutboxDispatcher struct {
+	db       *Database
+	handler  OutboxHandler
+	follower *ChangesFollower
+}
+
+// NewOutboxDispatcher creates a dispatcher for db's outbox events
+func (db *Database) NewOutboxDispatcher(handler OutboxHandler, store CheckpointStore, opts *FollowerOptions) *OutboxDispatcher {
+	followerOpts := FollowerOptions{}
+	if opts != nil {
+		followerOpts = *opts
+	}
+	if followerOpts.CheckpointKey == "" {
+		followerOpts.CheckpointKey = "outbox-" + db.name
+	}
+	followerOpts.IncludeDocs = true
+
+	d := &OutboxDispatcher{
+		db:      db,
+		handler: handler,
+	}
+	d.follower = db.NewChangesFollower(EventSinkFunc(d.dispatch), store, &followerOpts)
+
+	return d
+}
+
+// Run dispatches events until ctx is cancelled or an error occurs
+func (d *OutboxDispatcher) Run(ctx context.Context) error {
+	return d.follower.Run(ctx)
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context, change *Change) error {
+	if change.Deleted || change.Doc == nil || change.Doc.Data["type"] != outboxDocType {
+		return nil
+	}
+
+	var event OutboxEvent
+	if err := convertDoc(change.Doc, &event); err != nil {
+		return err
+	}
+
+	if event.Delivered {
+		return nil
+	}
+
+	if err := d.handler(ctx, &event); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	event.Delivered = true
+	event.DeliveredAt = &now
+
+	// A conflict means the event was marked by a concurrent dispatcher
+	if _, err := d.db.Update(ctx, event.ID, &event); err != nil && !isStatus(err, 409) {
+		return err
+	}
+
+	return nil
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
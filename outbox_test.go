@@ -0,0 +1,138 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteWithEvents(t *testing.T) {
+	var body struct {
+		Docs []map[string]interface{} `json:"docs"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/orders/_bulk_docs", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`[{"ok":true,"id":"order-1","rev":"1-a"},{"ok":true,"id":"outbox:e1","rev":"1-b"},{"id":"outbox:x","error":"conflict","reason":"Document update conflict."}]`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("orders")
+	created := NewOutboxEvent("order_created", map[string]interface{}{"order": "order-1"})
+	custom := &OutboxEvent{ID: "outbox:x", EventType: "order_paid"}
+
+	results, err := db.WriteWithEvents(context.Background(), []interface{}{
+		map[string]interface{}{"_id": "order-1", "total": 10},
+	}, created, custom)
+	require.NoError(t, err)
+
+	// Documents and events go out in one request, documents first
+	require.Len(t, body.Docs, 3)
+	assert.Equal(t, "order-1", body.Docs[0]["_id"])
+
+	assert.True(t, strings.HasPrefix(created.ID, outboxIDPrefix))
+	assert.Equal(t, created.ID, body.Docs[1]["_id"])
+	assert.Equal(t, "order_created", body.Docs[1]["event_type"])
+	assert.Equal(t, map[string]interface{}{"order": "order-1"}, body.Docs[1]["payload"])
+	assert.Equal(t, false, body.Docs[1]["delivered"])
+
+	assert.Equal(t, "outbox:x", body.Docs[2]["_id"])
+	assert.Equal(t, outboxDocType, body.Docs[2]["type"])
+	assert.False(t, custom.CreatedAt.IsZero())
+
+	assert.Len(t, results.Succeeded(), 2)
+}
+
+func TestOutboxDispatcher(t *testing.T) {
+	changes := []map[string]interface{}{
+		{"seq": "1-a", "id": "outbox:e1", "doc": map[string]interface{}{"_id": "outbox:e1", "_rev": "1-x", "type": outboxDocType, "event_type": "created"}},
+		{"seq": "2-b", "id": "order-1", "doc": map[string]interface{}{"_id": "order-1", "_rev": "1-y", "type": "order"}},
+		{"seq": "3-c", "id": "outbox:e2", "doc": map[string]interface{}{"_id": "outbox:e2", "_rev": "1-z", "type": outboxDocType, "event_type": "paid"}},
+		{"seq": "4-d", "id": "outbox:e3", "doc": map[string]interface{}{"_id": "outbox:e3", "_rev": "2-w", "type": outboxDocType, "event_type": "shipped", "delivered": true}},
+	}
+
+	var mu sync.Mutex
+	marked := map[string]map[string]interface{}{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/orders/_changes" {
+			assert.Equal(t, "true", r.URL.Query().Get("include_docs"))
+			results := changes
+			for i, change := range changes {
+				if change["seq"] == r.URL.Query().Get("since") {
+					results = changes[i+1:]
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "last_seq": "4-d"})
+			return
+		}
+
+		require.Equal(t, "PUT", r.Method)
+		var doc map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+		mu.Lock()
+		marked[strings.TrimPrefix(r.URL.Path, "/orders/")] = doc
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true,"id":"` + doc["_id"].(string) + `","rev":"2-m"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("orders")
+	store := NewMemoryCheckpointStore()
+	handlerErr := errors.New("broker unavailable")
+
+	var published []string
+	err := db.NewOutboxDispatcher(func(_ context.Context, event *OutboxEvent) error {
+		if event.ID == "outbox:e2" {
+			return handlerErr
+		}
+		published = append(published, event.EventType)
+		return nil
+	}, store, nil).Run(context.Background())
+	require.ErrorIs(t, err, handlerErr)
+
+	assert.Equal(t, []string{"created"}, published)
+	require.Contains(t, marked, "outbox:e1")
+	assert.Equal(t, true, marked["outbox:e1"]["delivered"])
+	assert.Equal(t, "1-x", marked["outbox:e1"]["_rev"])
+	assert.NotNil(t, marked["outbox:e1"]["delivered_at"])
+	assert.NotContains(t, marked, "outbox:e2")
+
+	seq, err := store.Load(context.Background(), "outbox-orders")
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", seq)
+
+	// The next run resumes with the failed event; delivered ones are skipped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = db.NewOutboxDispatcher(func(_ context.Context, event *OutboxEvent) error {
+		published = append(published, event.EventType)
+		return nil
+	}, store, &FollowerOptions{OnCheckpoint: func(_ int, seq string) {
+		if seq == "4-d" {
+			cancel()
+		}
+	}}).Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, []string{"created", "paid"}, published)
+	require.Contains(t, marked, "outbox:e2")
+	assert.Equal(t, true, marked["outbox:e2"]["delivered"])
+	assert.NotContains(t, marked, "outbox:e3")
+}
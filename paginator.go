@@ -0,0 +1,125 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultPageSize is the page size used when none is given
+const DefaultPageSize = 25
+
+// Page is one page of results. Rows is set for views and _all_docs, Docs
+// for Mango queries.
+type Page struct {
+	Rows []ViewRow
+	Docs []*Document
+
+	// Bookmark continues a Mango query after this page
+	Bookmark string
+}
+
+// Paginator pages through view, _all_docs or Mango results
+type Paginator struct {
+	fetch func(ctx context.Context) (*Page, error)
+	done  bool
+}
+
+// Next returns the next page. It returns an error if there are no more pages.
+func (p *Paginator) Next(ctx context.Context) (*Page, error) {
+	if p.done {
+		return nil, fmt.Errorf("no more pages")
+	}
+
+	return p.fetch(ctx)
+}
+
+// HasMore reports whether another page may be available
+func (p *Paginator) HasMore() bool {
+	return !p.done
+}
+
+// PaginateAllDocs pages through _all_docs
+func (db *Database) PaginateAllDocs(opts *ViewOptions, pageSize int) *Paginator {
+	return db.paginateRows(opts, pageSize, db.AllDocs)
+}
+
+// PaginateView pages through a view
+func (db *Database) PaginateView(designDoc, viewName string, opts *ViewOptions, pageSize int) *Paginator {
+	return db.paginateRows(opts, pageSize, func(ctx context.Context, opts *ViewOptions) (*ViewResult, error) {
+		return db.View(ctx, designDoc, viewName, opts)
+	})
+}
+
+// paginateRows pages by key: each request asks for one row more than the
+// page size and the extra row's key and ID become the next startkey and
+// startkey_docid, which stays correct when keys are duplicated.
+func (db *Database) paginateRows(opts *ViewOptions, pageSize int, query func(context.Context, *ViewOptions) (*ViewResult, error)) *Paginator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	var o ViewOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Key != nil {
+		o.StartKey, o.EndKey, o.Key = o.Key, o.Key, nil
+	}
+
+	p := &Paginator{}
+	p.fetch = func(ctx context.Context) (*Page, error) {
+		if o.Keys != nil {
+			return nil, fmt.Errorf("pagination is not supported with keys")
+		}
+
+		o.Limit = pageSize + 1
+		result, err := query(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+
+		rows := result.Rows
+		if len(rows) > pageSize {
+			next := rows[pageSize]
+			o.StartKey = next.Key
+			o.StartKeyDocID = next.ID
+			o.Skip = 0
+			rows = rows[:pageSize]
+		} else {
+			p.done = true
+		}
+
+		return &Page{Rows: rows}, nil
+	}
+
+	return p
+}
+
+// PaginateFind pages through the results of a Mango query using bookmarks
+func (db *Database) PaginateFind(query *FindQuery, pageSize int) *Paginator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	q := *query
+
+	p := &Paginator{}
+	p.fetch = func(ctx context.Context) (*Page, error) {
+		q.Limit = pageSize
+		result, err := db.Find(ctx, &q)
+		if err != nil {
+			return nil, err
+		}
+
+		// A full page may be followed by an empty one; a short page is always last
+		if len(result.Docs) < pageSize || result.Bookmark == "" || result.Bookmark == q.Bookmark {
+			p.done = true
+		}
+		q.Bookmark = result.Bookmark
+		q.Skip = 0
+
+		return &Page{Docs: result.Docs, Bookmark: result.Bookmark}, nil
+	}
+
+	return p
+}
@@ -0,0 +1,100 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateView(t *testing.T) {
+	// Seven rows with duplicate keys, sorted by key then ID
+	rows := []ViewRow{
+		{ID: "a", Key: "x"}, {ID: "b", Key: "x"}, {ID: "c", Key: "x"},
+		{ID: "d", Key: "y"}, {ID: "e", Key: "y"}, {ID: "f", Key: "z"}, {ID: "g", Key: "z"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		start := 0
+		if sk := q.Get("startkey"); sk != "" {
+			var key string
+			require.NoError(t, json.Unmarshal([]byte(sk), &key))
+			for start < len(rows) && (rows[start].Key.(string) < key ||
+				(rows[start].Key == key && rows[start].ID < q.Get("startkey_docid"))) {
+				start++
+			}
+		}
+
+		end := start + limit
+		if end > len(rows) {
+			end = len(rows)
+		}
+		_ = json.NewEncoder(w).Encode(ViewResult{TotalRows: int64(len(rows)), Rows: rows[start:end]})
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	p := db.PaginateView("app", "by_key", nil, 3)
+
+	var ids []string
+	pages := 0
+	for p.HasMore() {
+		page, err := p.Next(context.Background())
+		require.NoError(t, err)
+		pages++
+		for _, row := range page.Rows {
+			ids = append(ids, row.ID)
+		}
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, ids)
+
+	_, err := p.Next(context.Background())
+	assert.Error(t, err)
+}
+
+func TestPaginateFind(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var query FindQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		assert.Equal(t, 2, query.Limit)
+		if requests > 0 {
+			assert.Equal(t, fmt.Sprintf("b%d", requests), query.Bookmark)
+		}
+		requests++
+
+		switch requests {
+		case 1, 2:
+			_, _ = fmt.Fprintf(w, `{"docs":[{"_id":"%d"},{"_id":"%d"}],"bookmark":"b%d"}`, requests*2-1, requests*2, requests)
+		default:
+			_, _ = fmt.Fprintf(w, `{"docs":[],"bookmark":"b%d"}`, requests)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	p := db.PaginateFind(&FindQuery{Selector: Selector{}}, 2)
+
+	count := 0
+	for p.HasMore() {
+		page, err := p.Next(context.Background())
+		require.NoError(t, err)
+		count += len(page.Docs)
+	}
+
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 3, requests, "a full page is followed by one more request")
+}
@@ -0,0 +1,82 @@
+package couchdb
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxDBNameLength is the longest database name CouchDB accepts
+const maxDBNameLength = 238
+
+// dbNamePattern matches the names CouchDB accepts for databases
+var dbNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_$()+/-]*$`)
+
+// systemDBs are the databases whose names may start with an underscore
+var systemDBs = map[string]bool{
+	"_users":          true,
+	"_replicator":     true,
+	"_global_changes": true,
+}
+
+// ValidateDBName checks a database name against CouchDB's rules: a
+// lowercase letter followed by lowercase letters, digits and any of
+// _$()+-/, at most 238 characters, or one of the system databases. The
+// error is the illegal_database_name error CouchDB would return.
+func ValidateDBName(name string) error {
+	if systemDBs[name] || (len(name) <= maxDBNameLength && dbNamePattern.MatchString(name)) {
+		return nil
+	}
+	return &Error{
+		StatusCode: http.StatusBadRequest,
+		Type:       "illegal_database_name",
+		Reason:     "Name: '" + name + "'. Only lowercase characters (a-z), digits (0-9), and any of the characters _, $, (, ), +, -, and / are allowed. Must begin with a letter.",
+	}
+}
+
+// escapeSegment escapes a value for use as one URL path segment. Slashes
+// are escaped, as in database names like "a/b", and so is "+", which
+// CouchDB would otherwise read as a space.
+func escapeSegment(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "+", "%2B")
+}
+
+// escapeDocID escapes a document ID for a URL path. The slash after the
+// _design and _local prefixes is kept, as CouchDB routes on it.
+func escapeDocID(id string) string {
+	for _, prefix := range []string{"_design/", localPrefix} {
+		if rest, ok := strings.CutPrefix(id, prefix); ok {
+			return prefix + escapeSegment(rest)
+		}
+	}
+	return escapeSegment(id)
+}
+
+// buildPath joins segments into a URL path, escaping each of them. Every
+// path containing a name or ID is built with it or the Database helpers
+// below, so that no name or ID can add segments or a query string.
+func buildPath(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(escapeSegment(segment))
+	}
+	return b.String()
+}
+
+// path returns the path of the database followed by segments
+func (db *Database) path(segments ...string) string {
+	return buildPath(db.name) + buildPath(segments...)
+}
+
+// docPath returns the path of a document followed by segments
+func (db *Database) docPath(id string, segments ...string) string {
+	return db.path() + "/" + escapeDocID(id) + buildPath(segments...)
+}
+
+// designPath returns the path of a design document followed by segments.
+// The name may be given with or without the _design/ prefix.
+func (db *Database) designPath(designDoc string, segments ...string) string {
+	return db.docPath("_design/"+strings.TrimPrefix(designDoc, "_design/"), segments...)
+}
@@ -0,0 +1,88 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDBName(t *testing.T) {
+	for _, name := range []string{"users", "a/b", "app_v2", "x$()+-", "_users", "_replicator", strings.Repeat("a", 238)} {
+		assert.NoError(t, ValidateDBName(name), name)
+	}
+
+	for _, name := range []string{"", "Users", "1db", "_db", "a b", "db?x", strings.Repeat("a", 239)} {
+		err := ValidateDBName(name)
+		assert.ErrorIs(t, err, ErrBadRequest, name)
+		assert.Equal(t, "illegal_database_name", err.(*Error).Type)
+	}
+
+	err := NewClient("http://localhost:1", nil).CreateDB(context.Background(), "Bad")
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestEscapedPaths(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"_id":"x","_rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	db := client.DB("app/v1")
+
+	for _, id := range []string{"a/b", "a+b", "a b", "a?b#c", "café", "_design/x/y", "_local/a+b"} {
+		_, err := db.Get(ctx, id)
+		require.NoError(t, err)
+	}
+
+	_, _ = db.View(ctx, "my ddoc", "by?name", nil)
+	_, _ = db.GetDesignDoc(ctx, "_design/app")
+	_, _, _ = db.GetAttachment(ctx, "a/b", "img/logo.png")
+
+	assert.Equal(t, []string{
+		"/app%2Fv1/a%2Fb",
+		"/app%2Fv1/a%2Bb",
+		"/app%2Fv1/a%20b",
+		"/app%2Fv1/a%3Fb%23c",
+		"/app%2Fv1/caf%C3%A9",
+		"/app%2Fv1/_design/x%2Fy",
+		"/app%2Fv1/_local/a%2Bb",
+		"/app%2Fv1/_design/my%20ddoc/_view/by%3Fname",
+		"/app%2Fv1/_design/app",
+		"/app%2Fv1/a%2Fb/img%2Flogo.png",
+	}, paths)
+}
+
+func TestEscapedPaths_RoundTrip(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+	require.NoError(t, client.CreateDB(ctx, "app/v1"))
+	db := client.DB("app/v1")
+
+	for _, id := range []string{"a/b", "a+b", "a b"} {
+		result, err := db.Put(ctx, map[string]interface{}{"_id": id, "value": id})
+		require.NoError(t, err, id)
+
+		doc, err := db.Get(ctx, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, id, doc.ID)
+		assert.Equal(t, id, doc.Data["value"])
+
+		err = db.Delete(ctx, id, result.Rev)
+		require.NoError(t, err, id)
+	}
+}
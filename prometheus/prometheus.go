@@ -0,0 +1,211 @@
+// Package prometheus provides a couchdb.MetricsCollector that exposes client
+// metrics in the Prometheus text exposition format, without depending on the
+// Prometheus client library.
+//
+//	collector := prometheus.New("myapp")
+//	client := couchdb.NewClient(url, &couchdb.ClientOptions{Metrics: collector})
+//	http.Handle("/metrics", collector)
+//
+// The following metrics are exported, prefixed with the namespace:
+//
+//	couchdb_requests_total{method,operation,code}         counter
+//	couchdb_request_errors_total{method,operation}        counter
+//	couchdb_request_duration_seconds{method,operation}    histogram
+//	couchdb_requests_in_flight                            gauge
+//
+// Errors are transport failures and 5xx responses. Database names are not
+// used as labels to keep cardinality bounded.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+// DefaultBuckets are the latency histogram buckets in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector aggregates request metrics and serves them over HTTP
+type Collector struct {
+	prefix  string
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	errors    map[operationKey]uint64
+	durations map[operationKey]*histogram
+	inFlight  int64
+}
+
+type operationKey struct {
+	method    string
+	operation string
+}
+
+type requestKey struct {
+	operationKey
+	code int
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+var _ couchdb.MetricsCollector = (*Collector)(nil)
+
+// New creates a collector. Metric names are prefixed with namespace, if
+// given, and then "couchdb_". Buckets default to DefaultBuckets.
+func New(namespace string, buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	prefix := "couchdb_"
+	if namespace != "" {
+		prefix = namespace + "_" + prefix
+	}
+
+	return &Collector{
+		prefix:    prefix,
+		buckets:   buckets,
+		requests:  make(map[requestKey]uint64),
+		errors:    make(map[operationKey]uint64),
+		durations: make(map[operationKey]*histogram),
+	}
+}
+
+// RequestStarted implements couchdb.MetricsCollector
+func (c *Collector) RequestStarted(couchdb.RequestInfo) {
+	c.mu.Lock()
+	c.inFlight++
+	c.mu.Unlock()
+}
+
+// RequestFinished implements couchdb.MetricsCollector
+func (c *Collector) RequestFinished(info couchdb.RequestInfo, status int, duration time.Duration, err error) {
+	op := operationKey{method: info.Method, operation: info.Operation}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	c.requests[requestKey{operationKey: op, code: status}]++
+
+	if err != nil || status >= 500 {
+		c.errors[op]++
+	}
+
+	h := c.durations[op]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.durations[op] = h
+	}
+
+	seconds := duration.Seconds()
+	h.count++
+	h.sum += seconds
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	name := c.prefix + "requests_total"
+	cw.printf("# HELP %s Total CouchDB requests by method, operation and status code.\n", name)
+	cw.printf("# TYPE %s counter\n", name)
+	for _, key := range sortedKeys(c.requests, func(k requestKey) string {
+		return k.method + "\x00" + k.operation + "\x00" + strconv.Itoa(k.code)
+	}) {
+		cw.printf("%s{method=%q,operation=%q,code=\"%d\"} %d\n", name, key.method, key.operation, key.code, c.requests[key])
+	}
+
+	name = c.prefix + "request_errors_total"
+	cw.printf("# HELP %s CouchDB requests that failed in transport or with a 5xx status.\n", name)
+	cw.printf("# TYPE %s counter\n", name)
+	for _, key := range sortedKeys(c.errors, operationKey.String) {
+		cw.printf("%s{method=%q,operation=%q} %d\n", name, key.method, key.operation, c.errors[key])
+	}
+
+	name = c.prefix + "request_duration_seconds"
+	cw.printf("# HELP %s CouchDB request latency until response headers.\n", name)
+	cw.printf("# TYPE %s histogram\n", name)
+	for _, key := range sortedKeys(c.durations, operationKey.String) {
+		h := c.durations[key]
+		labels := fmt.Sprintf("method=%q,operation=%q", key.method, key.operation)
+
+		var cumulative uint64
+		for i, bound := range c.buckets {
+			cumulative += h.counts[i]
+			cw.printf("%s_bucket{%s,le=\"%s\"} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		cw.printf("%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		cw.printf("%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		cw.printf("%s_count{%s} %d\n", name, labels, h.count)
+	}
+
+	name = c.prefix + "requests_in_flight"
+	cw.printf("# HELP %s CouchDB requests awaiting a response.\n", name)
+	cw.printf("# TYPE %s gauge\n", name)
+	cw.printf("%s %d\n", name, c.inFlight)
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (k operationKey) String() string {
+	return k.method + "\x00" + k.operation
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, sortKey func(K) string) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return sortKey(keys[i]) < sortKey(keys[j]) })
+	return keys
+}
+
+// countingWriter records the bytes written and the first error
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
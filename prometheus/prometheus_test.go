@@ -0,0 +1,57 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		if r.URL.Path == "/db/_find" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"unavailable","reason":"try later"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"_id":"a","_rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	collector := New("app", 0.5, 0.1)
+	db := couchdb.NewClient(server.URL, &couchdb.ClientOptions{Metrics: collector}).DB("db")
+	ctx := context.Background()
+
+	_, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	_, err = db.Get(ctx, "missing")
+	require.Error(t, err)
+	_, err = db.Find(ctx, &couchdb.FindQuery{})
+	require.Error(t, err)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	assert.Contains(t, out, `app_couchdb_requests_total{method="GET",operation="document",code="200"} 1`)
+	assert.Contains(t, out, `app_couchdb_requests_total{method="GET",operation="document",code="404"} 1`)
+	assert.Contains(t, out, `app_couchdb_request_errors_total{method="POST",operation="_find"} 1`)
+	assert.Contains(t, out, `app_couchdb_request_duration_seconds_count{method="GET",operation="document"} 2`)
+	assert.Contains(t, out, `app_couchdb_request_duration_seconds_bucket{method="GET",operation="document",le="+Inf"} 2`)
+	assert.Contains(t, out, "app_couchdb_requests_in_flight 0")
+	assert.NotContains(t, out, `request_errors_total{method="GET"`, "4xx responses are not errors")
+
+	// Buckets are sorted and cumulative
+	assert.Less(t, strings.Index(out, `le="0.1"`), strings.Index(out, `le="0.5"`))
+}
@@ -0,0 +1,25 @@
+package couchdb
+
+import "context"
+
+// PurgeSeq returns the database's current purge sequence
+func (db *Database) PurgeSeq(ctx context.Context) (string, error) {
+	info, err := db.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return info.PurgeSeq.String(), nil
+}
+
+// PurgedSince reports whether documents were purged after the given purge
+// sequence checkpoint. It also returns the current purge sequence so callers
+// can record it as their next checkpoint once purged documents are handled.
+func (db *Database) PurgedSince(ctx context.Context, checkpoint string) (bool, string, error) {
+	current, err := db.PurgeSeq(ctx)
+	if err != nil {
+		return false, "", err
+	}
+
+	return Sequence(current).After(Sequence(checkpoint)), current, nil
+}
@@ -0,0 +1,50 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgedSince(t *testing.T) {
+	purgeSeq := `"7-g1AAAAB1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"db_name":"db","purge_seq":` + purgeSeq + `}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		checkpoint string
+		purged     bool
+	}{
+		// Only the numeric prefix is compared, the opaque suffix differs per shard
+		{"equal", "7-g1AAAAC2", false},
+		{"older", "3-g1AAAAD3", true},
+		{"newer", "9-g1AAAAE4", false},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		purged, current, err := db.PurgedSince(ctx, tt.checkpoint)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.purged, purged, tt.name)
+		assert.Equal(t, "7-g1AAAAB1", current, tt.name)
+	}
+
+	// A database that was never purged reports a numeric zero
+	purgeSeq = `0`
+	purged, current, err := db.PurgedSince(ctx, "")
+	require.NoError(t, err)
+	assert.False(t, purged)
+	assert.Equal(t, "0", current)
+}
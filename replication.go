@@ -0,0 +1,226 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// ReplicationSpec represents a document in the _replicator database
+type ReplicationSpec struct {
+	ID  string `json:"_id,omitempty"`
+	Rev string `json:"_rev,omitempty"`
+
+	Source ReplicationEndpoint `json:"source"`
+	Target ReplicationEndpoint `json:"target"`
+
+	Continuous         bool                   `json:"continuous,omitempty"`
+	CreateTarget       bool                   `json:"create_target,omitempty"`
+	Filter             string                 `json:"filter,omitempty"`
+	QueryParams        map[string]interface{} `json:"query_params,omitempty"`
+	Selector           Selector               `json:"selector,omitempty"`
+	DocIDs             []string               `json:"doc_ids,omitempty"`
+	SinceSeq           string                 `json:"since_seq,omitempty"`
+	UseCheckpoints     *bool                  `json:"use_checkpoints,omitempty"`
+	CheckpointInterval int                    `json:"checkpoint_interval,omitempty"` // milliseconds
+	WorkerProcesses    int                    `json:"worker_processes,omitempty"`
+	WorkerBatchSize    int                    `json:"worker_batch_size,omitempty"`
+
+	// State fields maintained by the replicator (read-only)
+	ReplicationID          string `json:"_replication_id,omitempty"`
+	ReplicationState       string `json:"_replication_state,omitempty"`
+	ReplicationStateReason string `json:"_replication_state_reason,omitempty"`
+}
+
+// ReplicationEndpoint is a replication source or target
+type ReplicationEndpoint struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Auth    *ReplicationAuth  `json:"auth,omitempty"`
+}
+
+// ReplicationAuth holds credentials for a replication endpoint
+type ReplicationAuth struct {
+	Basic *BasicAuth `json:"basic,omitempty"`
+}
+
+// BasicAuth holds basic authentication credentials
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// NewReplicationEndpoint creates an endpoint with optional basic auth credentials
+func NewReplicationEndpoint(url, username, password string) ReplicationEndpoint {
+	endpoint := ReplicationEndpoint{URL: url}
+	if username != "" {
+		endpoint.Auth = &ReplicationAuth{Basic: &BasicAuth{Username: username, Password: password}}
+	}
+	return endpoint
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting both the plain URL and object forms
+func (e *ReplicationEndpoint) UnmarshalJSON(data []byte) error {
+	var url string
+	if err := json.Unmarshal(data, &url); err == nil {
+		*e = ReplicationEndpoint{URL: url}
+		return nil
+	}
+
+	type endpoint ReplicationEndpoint
+	return json.Unmarshal(data, (*endpoint)(e))
+}
+
+// CreateReplication writes a replication document to the _replicator
+// database. A random ID is assigned if spec.ID is empty.
+func (c *Client) CreateReplication(ctx context.Context, spec *ReplicationSpec) (*Document, error) {
+	if spec.ID == "" {
+		id, err := randomID()
+		if err != nil {
+			return nil, err
+		}
+		spec.ID = id
+	}
+
+	return c.DB(DefaultReplicatorDB).Update(ctx, spec.ID, spec)
+}
+
+// GetReplication returns a replication document from the _replicator database
+func (c *Client) GetReplication(ctx context.Context, id string) (*ReplicationSpec, error) {
+	doc, err := c.DB(DefaultReplicatorDB).Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec ReplicationSpec
+	if err := convertDoc(doc, &spec); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// CancelReplication stops a replication by deleting its document
+func (c *Client) CancelReplication(ctx context.Context, id string) error {
+	db := c.DB(DefaultReplicatorDB)
+
+	doc, err := db.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return db.Delete(ctx, doc.ID, doc.Rev)
+}
+
+// ListReplications returns all replication documents in the _replicator database
+func (c *Client) ListReplications(ctx context.Context) ([]*ReplicationSpec, error) {
+	result, err := c.DB(DefaultReplicatorDB).AllDocs(ctx, &ViewOptions{IncludeDocs: true})
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]*ReplicationSpec, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		if row.Doc == nil || strings.HasPrefix(row.ID, "_design/") {
+			continue
+		}
+
+		var spec ReplicationSpec
+		if err := convertDoc(row.Doc, &spec); err != nil {
+			return nil, err
+		}
+		specs = append(specs, &spec)
+	}
+
+	return specs, nil
+}
+
+// ReplicateOptions holds options for a transient replication
+type ReplicateOptions struct {
+	SourceAuth   *ReplicationAuth
+	TargetAuth   *ReplicationAuth
+	Continuous   bool
+	CreateTarget bool
+	Filter       string
+	QueryParams  map[string]interface{}
+	Selector     Selector
+	DocIDs       []string
+	SinceSeq     string
+	Cancel       bool // cancel a running continuous replication with the same parameters
+}
+
+// ReplicationResult is the response of the /_replicate endpoint
+type ReplicationResult struct {
+	OK                   bool                 `json:"ok"`
+	NoChanges            bool                 `json:"no_changes,omitempty"`
+	SessionID            string               `json:"session_id,omitempty"`
+	SourceLastSeq        Sequence             `json:"source_last_seq,omitempty"`
+	ReplicationIDVersion int                  `json:"replication_id_version,omitempty"`
+	LocalID              string               `json:"_local_id,omitempty"`
+	History              []ReplicationHistory `json:"history,omitempty"`
+}
+
+// ReplicationHistory holds the statistics of one replication session
+type ReplicationHistory struct {
+	SessionID        string   `json:"session_id"`
+	StartTime        string   `json:"start_time"`
+	EndTime          string   `json:"end_time"`
+	StartLastSeq     Sequence `json:"start_last_seq"`
+	EndLastSeq       Sequence `json:"end_last_seq"`
+	RecordedSeq      Sequence `json:"recorded_seq"`
+	MissingChecked   int64    `json:"missing_checked"`
+	MissingFound     int64    `json:"missing_found"`
+	DocsRead         int64    `json:"docs_read"`
+	DocsWritten      int64    `json:"docs_written"`
+	DocWriteFailures int64    `json:"doc_write_failures"`
+}
+
+// Replicate runs a transient replication through the /_replicate endpoint.
+// One-shot replications block until finished, so the request is not subject
+// to the client timeout; use ctx to bound it.
+func (c *Client) Replicate(ctx context.Context, source, target string, opts *ReplicateOptions) (*ReplicationResult, error) {
+	if opts == nil {
+		opts = &ReplicateOptions{}
+	}
+
+	body := struct {
+		Source       ReplicationEndpoint    `json:"source"`
+		Target       ReplicationEndpoint    `json:"target"`
+		Continuous   bool                   `json:"continuous,omitempty"`
+		CreateTarget bool                   `json:"create_target,omitempty"`
+		Filter       string                 `json:"filter,omitempty"`
+		QueryParams  map[string]interface{} `json:"query_params,omitempty"`
+		Selector     Selector               `json:"selector,omitempty"`
+		DocIDs       []string               `json:"doc_ids,omitempty"`
+		SinceSeq     string                 `json:"since_seq,omitempty"`
+		Cancel       bool                   `json:"cancel,omitempty"`
+	}{
+		Source:       ReplicationEndpoint{URL: source, Auth: opts.SourceAuth},
+		Target:       ReplicationEndpoint{URL: target, Auth: opts.TargetAuth},
+		Continuous:   opts.Continuous,
+		CreateTarget: opts.CreateTarget,
+		Filter:       opts.Filter,
+		QueryParams:  opts.QueryParams,
+		Selector:     opts.Selector,
+		DocIDs:       opts.DocIDs,
+		SinceSeq:     opts.SinceSeq,
+		Cancel:       opts.Cancel,
+	}
+
+	var result ReplicationResult
+	resp, err := c.stream.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&result).
+		Post("/_replicate")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &result, nil
+}
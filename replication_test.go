@@ -0,0 +1,114 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateReplication(t *testing.T) {
+	var body map[string]interface{}
+	var path string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true,"id":"` + body["_id"].(string) + `","rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	spec := &ReplicationSpec{
+		ID:         "backup",
+		Source:     NewReplicationEndpoint("http://a:5984/db", "admin", "secret"),
+		Target:     NewReplicationEndpoint("http://b:5984/db", "", ""),
+		Continuous: true,
+		Selector:   Selector{"type": "order"},
+	}
+
+	doc, err := client.CreateReplication(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", doc.Rev)
+
+	assert.Equal(t, "/_replicator/backup", path)
+	assert.Equal(t, map[string]interface{}{
+		"_id": "backup",
+		"source": map[string]interface{}{
+			"url":  "http://a:5984/db",
+			"auth": map[string]interface{}{"basic": map[string]interface{}{"username": "admin", "password": "secret"}},
+		},
+		"target":     map[string]interface{}{"url": "http://b:5984/db"},
+		"continuous": true,
+		"selector":   map[string]interface{}{"type": "order"},
+	}, body)
+
+	// Without an ID one is generated
+	doc, err = client.CreateReplication(context.Background(), &ReplicationSpec{
+		Source: ReplicationEndpoint{URL: "http://a:5984/db"},
+		Target: ReplicationEndpoint{URL: "http://b:5984/db"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, doc.ID, 32)
+	assert.Equal(t, "/_replicator/"+doc.ID, path)
+}
+
+func TestReplicate(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/_replicate", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		if body["target"].(map[string]interface{})["url"] == "http://b:5984/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"Database does not exist."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"session_id":"s1","source_last_seq":"12-g1","replication_id_version":4,
+			"history":[{"session_id":"s1","start_last_seq":0,"end_last_seq":"12-g1","recorded_seq":"12-g1","missing_checked":12,"missing_found":10,"docs_read":10,"docs_written":9,"doc_write_failures":1}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	result, err := client.Replicate(ctx, "http://a:5984/db", "http://b:5984/db", &ReplicateOptions{
+		TargetAuth:   &ReplicationAuth{Basic: &BasicAuth{Username: "admin", Password: "secret"}},
+		CreateTarget: true,
+		DocIDs:       []string{"a", "b"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"source": map[string]interface{}{"url": "http://a:5984/db"},
+		"target": map[string]interface{}{
+			"url":  "http://b:5984/db",
+			"auth": map[string]interface{}{"basic": map[string]interface{}{"username": "admin", "password": "secret"}},
+		},
+		"create_target": true,
+		"doc_ids":       []interface{}{"a", "b"},
+	}, body)
+
+	assert.True(t, result.OK)
+	assert.Equal(t, "s1", result.SessionID)
+	assert.Equal(t, "12-g1", result.SourceLastSeq.String())
+	assert.Equal(t, 4, result.ReplicationIDVersion)
+	require.Len(t, result.History, 1)
+	assert.Equal(t, int64(9), result.History[0].DocsWritten)
+	assert.Equal(t, int64(1), result.History[0].DocWriteFailures)
+
+	_, err = client.Replicate(ctx, "http://a:5984/db", "http://b:5984/missing", nil)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, map[string]interface{}{"url": "http://a:5984/db"}, body["source"])
+}
@@ -0,0 +1,152 @@
+// Package repo offers a repository per document type on top of a CouchDB
+// database, for applications that keep several types in one database:
+//
+//	type User struct {
+//		couchdb.DocumentMeta
+//		Name  string `json:"name"`
+//		Email string `json:"email"`
+//	}
+//
+//	users := repo.New[User](db, "user")
+//	err := users.EnsureIndex(ctx, "email")
+//	err = users.Save(ctx, &User{Name: "Ann", Email: "ann@example.com"})
+//	found, err := users.FindWhere(ctx, couchdb.Eq("email", "ann@example.com"))
+//
+// Documents carry their type in the TypeField, which the repository sets on
+// every save and adds to every query. T maps the document ID and revision
+// with "_id" and "_rev" JSON tags, by embedding couchdb.DocumentMeta, or with
+// couchdb:"id" and couchdb:"rev" tags.
+package repo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+)
+
+// TypeField is the document field holding the type name
+const TypeField = "type"
+
+// pageSize is the number of documents requested per _find call
+const pageSize = 1000
+
+// Repository stores and queries documents of one type
+type Repository[T any] struct {
+	db       *couchdb.Database
+	typed    *couchdb.TypedDB[T]
+	typeName string
+}
+
+// New returns a repository for documents of type T stored in db with
+// typeName in their TypeField
+func New[T any](db *couchdb.Database, typeName string) *Repository[T] {
+	stamped := db.WithWriteHook(func(doc map[string]interface{}) error {
+		doc[TypeField] = typeName
+		return nil
+	})
+
+	return &Repository[T]{
+		db:       db,
+		typed:    couchdb.Typed[T](stamped),
+		typeName: typeName,
+	}
+}
+
+// TypeName returns the type name of the repository's documents
+func (r *Repository[T]) TypeName() string {
+	return r.typeName
+}
+
+// Save creates or updates a document and stores its new ID and revision in doc
+func (r *Repository[T]) Save(ctx context.Context, doc *T) error {
+	_, err := r.typed.Put(ctx, doc)
+	return err
+}
+
+// FindByID returns a document by ID. Documents of another type are
+// reported as not found.
+func (r *Repository[T]) FindByID(ctx context.Context, id string) (*T, error) {
+	doc, err := r.db.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Data[TypeField] != r.typeName {
+		return nil, &couchdb.Error{
+			StatusCode: http.StatusNotFound,
+			Type:       "not_found",
+			Reason:     "not a " + r.typeName,
+		}
+	}
+
+	var value T
+	if err := doc.Decode(&value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// FindWhere returns all documents of the type matching selector, following
+// bookmarks until every match has been read
+func (r *Repository[T]) FindWhere(ctx context.Context, selector couchdb.Selector) ([]T, error) {
+	query := &couchdb.FindQuery{Selector: r.selector(selector), Limit: pageSize}
+
+	values := []T{}
+	for {
+		result, err := r.db.Find(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, doc := range result.Docs {
+			var value T
+			if err := doc.Decode(&value); err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+
+		if len(result.Docs) < pageSize || result.Bookmark == "" {
+			return values, nil
+		}
+		query.Bookmark = result.Bookmark
+	}
+}
+
+// All returns every document of the type
+func (r *Repository[T]) All(ctx context.Context) ([]T, error) {
+	return r.FindWhere(ctx, nil)
+}
+
+// Delete deletes a document by the ID and revision stored in doc
+func (r *Repository[T]) Delete(ctx context.Context, doc *T) error {
+	return r.db.DeleteDoc(ctx, doc)
+}
+
+// EnsureIndex creates a Mango index on the TypeField followed by fields,
+// so that queries of the type filtering on those fields do not scan the
+// whole database. It is safe to call at every start.
+func (r *Repository[T]) EnsureIndex(ctx context.Context, fields ...string) error {
+	name := "type"
+	if len(fields) > 0 {
+		name += "-" + strings.Join(fields, "-")
+	}
+
+	_, err := r.db.CreateIndex(ctx, &couchdb.MangoIndex{
+		Fields:    append([]string{TypeField}, fields...),
+		DesignDoc: "repo-" + r.typeName,
+		Name:      name,
+	})
+	return err
+}
+
+// selector restricts selector to documents of the type
+func (r *Repository[T]) selector(selector couchdb.Selector) couchdb.Selector {
+	byType := couchdb.Eq(TypeField, r.typeName)
+	if len(selector) == 0 {
+		return byType
+	}
+	return couchdb.And(byType, selector)
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb"
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	couchdb.DocumentMeta
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type post struct {
+	Slug    string `couchdb:"id" json:"-"`
+	Version string `couchdb:"rev" json:"-"`
+	Title   string `json:"title"`
+}
+
+func TestRepository(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("app")
+
+	db := couchdb.NewClient(server.URL, nil).DB("app")
+	ctx := context.Background()
+
+	users := New[user](db, "user")
+	posts := New[post](db, "post")
+
+	require.NoError(t, users.EnsureIndex(ctx, "age"))
+
+	ann := &user{Name: "Ann", Age: 30}
+	require.NoError(t, users.Save(ctx, ann))
+	assert.NotEmpty(t, ann.ID)
+	assert.Regexp(t, `^1-`, ann.Rev)
+
+	require.NoError(t, users.Save(ctx, &user{DocumentMeta: couchdb.DocumentMeta{ID: "bob"}, Name: "Bob", Age: 20}))
+
+	hello := &post{Slug: "hello", Title: "Hello"}
+	require.NoError(t, posts.Save(ctx, hello))
+	assert.Regexp(t, `^1-`, hello.Version)
+
+	raw, err := db.Get(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "post", raw.Data["type"])
+
+	ann.Age = 31
+	require.NoError(t, users.Save(ctx, ann))
+	assert.Regexp(t, `^2-`, ann.Rev)
+
+	found, err := users.FindByID(ctx, ann.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *ann, *found)
+
+	_, err = users.FindByID(ctx, "hello")
+	assert.True(t, couchdb.IsNotFound(err), "a post is not a user")
+
+	foundPost, err := posts.FindByID(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, *hello, *foundPost)
+
+	all, err := users.All(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	adults, err := users.FindWhere(ctx, couchdb.Gte("age", 21))
+	require.NoError(t, err)
+	require.Len(t, adults, 1)
+	assert.Equal(t, "Ann", adults[0].Name)
+
+	allPosts, err := posts.All(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []post{*hello}, allPosts)
+
+	require.NoError(t, posts.Delete(ctx, hello))
+	allPosts, err = posts.All(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, allPosts)
+}
@@ -0,0 +1,230 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type requestOverridesKey struct{}
+
+// requestOverrides holds the per-request settings carried by a context
+type requestOverrides struct {
+	timeout     time.Duration
+	headers     map[string]string
+	queryParams map[string]string
+	info        *ResponseInfo
+	requestID   string
+}
+
+func overridesFrom(ctx context.Context) *requestOverrides {
+	o, _ := ctx.Value(requestOverridesKey{}).(*requestOverrides)
+	return o
+}
+
+// withOverrides copies the overrides already in ctx, lets update change the
+// copy and stores it in a derived context
+func withOverrides(ctx context.Context, update func(o *requestOverrides)) context.Context {
+	o := &requestOverrides{headers: map[string]string{}, queryParams: map[string]string{}}
+	if parent := overridesFrom(ctx); parent != nil {
+		o.timeout = parent.timeout
+		o.info = parent.info
+		o.requestID = parent.requestID
+		for k, v := range parent.headers {
+			o.headers[k] = v
+		}
+		for k, v := range parent.queryParams {
+			o.queryParams[k] = v
+		}
+	}
+	update(o)
+	return context.WithValue(ctx, requestOverridesKey{}, o)
+}
+
+// WithRequestTimeout returns a context whose requests use timeout instead of
+// ClientOptions.Timeout. It may be longer than the client timeout, e.g. for
+// a view that takes a while to build. Each retry attempt gets the full timeout.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return withOverrides(ctx, func(o *requestOverrides) { o.timeout = timeout })
+}
+
+// WithRequestHeader returns a context whose requests carry an extra header
+func WithRequestHeader(ctx context.Context, key, value string) context.Context {
+	return withOverrides(ctx, func(o *requestOverrides) { o.headers[key] = value })
+}
+
+// WithRequestQueryParam returns a context whose requests carry an extra
+// query parameter, replacing one the client would otherwise set
+func WithRequestQueryParam(ctx context.Context, key, value string) context.Context {
+	return withOverrides(ctx, func(o *requestOverrides) { o.queryParams[key] = value })
+}
+
+const (
+	requestIDHeader      = "X-Request-ID"
+	couchRequestIDHeader = "X-Couch-Request-ID"
+)
+
+// WithRequestID returns a context whose requests carry id as X-Request-ID,
+// e.g. the ID of the incoming request being served, so that the CouchDB
+// requests can be correlated with it. Requests without one get a random ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withOverrides(ctx, func(o *requestOverrides) { o.requestID = id })
+}
+
+// applyRequestID sets the X-Request-ID header from the context, or to a
+// random ID. A header set by WithRequestHeader takes precedence.
+func applyRequestID(_ *resty.Client, req *resty.Request) error {
+	if req.Header.Get(requestIDHeader) != "" {
+		return nil
+	}
+
+	if o := overridesFrom(req.Context()); o != nil && o.requestID != "" {
+		req.SetHeader(requestIDHeader, o.requestID)
+		return nil
+	}
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	req.SetHeader(requestIDHeader, hex.EncodeToString(b[:]))
+	return nil
+}
+
+// ResponseInfo describes the last HTTP response received with a context
+// from WithResponseInfo
+type ResponseInfo struct {
+	Method string
+	URL    string
+
+	// ClientRequestID is the X-Request-ID sent with the request
+	ClientRequestID string
+
+	StatusCode int
+	Header     http.Header
+
+	// Duration is the time from sending the request to reading the response
+	Duration time.Duration
+
+	// BodySize is the size of the response body, or its Content-Length for
+	// streamed responses (-1 if unknown)
+	BodySize int64
+
+	// Requests counts the responses received with the context, as some
+	// operations make several requests
+	Requests int
+}
+
+// ETag returns the ETag header without quotes, the revision for documents
+func (i *ResponseInfo) ETag() string {
+	return strings.Trim(i.Header.Get("ETag"), `"`)
+}
+
+// RequestID returns the X-Couch-Request-ID header, which identifies the
+// request in the server logs
+func (i *ResponseInfo) RequestID() string {
+	return i.Header.Get(couchRequestIDHeader)
+}
+
+// responseInfoMu guards the ResponseInfo of contexts shared by concurrent
+// requests
+var responseInfoMu sync.Mutex
+
+// WithResponseInfo returns a context whose requests record their response
+// into info, so that a single operation can be logged or debugged without
+// enabling Debug for the whole client. When an operation makes several
+// requests, info describes the last one; a request that fails without a
+// response leaves it unchanged.
+func WithResponseInfo(ctx context.Context, info *ResponseInfo) context.Context {
+	return withOverrides(ctx, func(o *requestOverrides) { o.info = info })
+}
+
+// captureResponseInfo fills the ResponseInfo of the request context
+func captureResponseInfo(_ *resty.Client, resp *resty.Response) error {
+	o := overridesFrom(resp.Request.Context())
+	if o == nil || o.info == nil {
+		return nil
+	}
+
+	size := resp.Size()
+	if size == 0 && resp.RawResponse != nil && resp.Request.Method != http.MethodHead {
+		size = resp.RawResponse.ContentLength
+	}
+
+	responseInfoMu.Lock()
+	defer responseInfoMu.Unlock()
+
+	o.info.Method = resp.Request.Method
+	o.info.ClientRequestID = resp.Request.Header.Get(requestIDHeader)
+	o.info.URL = resp.Request.URL
+	o.info.StatusCode = resp.StatusCode()
+	o.info.Header = resp.Header().Clone()
+	o.info.Duration = resp.Time()
+	o.info.BodySize = size
+	o.info.Requests++
+	return nil
+}
+
+// applyRequestOverrides adds the headers and query parameters from the
+// request context
+func applyRequestOverrides(_ *resty.Client, req *resty.Request) error {
+	o := overridesFrom(req.Context())
+	if o == nil {
+		return nil
+	}
+
+	for k, v := range o.headers {
+		req.SetHeader(k, v)
+	}
+	for k, v := range o.queryParams {
+		req.SetQueryParam(k, v)
+	}
+	return nil
+}
+
+// timeoutTransport bounds each round trip, including reading the body, by
+// the context's request timeout or else a default. It replaces
+// http.Client.Timeout, which cannot be extended per request.
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration // zero means no default timeout
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.timeout
+	if o := overridesFrom(req.Context()); o != nil && o.timeout > 0 {
+		timeout = o.timeout
+	}
+	if timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the request context once the body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,127 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeoutOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"couchdb":"Welcome","version":"3.4.2"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, &ClientOptions{Timeout: 20 * time.Millisecond})
+
+	_, err := client.Info(context.Background())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "client timeout applies by default: %v", err)
+
+	ctx := WithRequestTimeout(context.Background(), 5*time.Second)
+	info, err := client.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.2", info.Version)
+}
+
+func TestRequestHeaderAndQueryParam(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trace-1", r.Header.Get("X-Trace"))
+		assert.Equal(t, "2", r.URL.Query().Get("r"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"_id":"doc1","_rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	ctx := WithRequestHeader(context.Background(), "X-Trace", "trace-1")
+	ctx = WithRequestQueryParam(ctx, "r", "2")
+
+	doc, err := NewClient(server.URL, nil).DB("db").Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, "1-a", doc.Rev)
+}
+
+func TestResponseInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trace-1", r.Header.Get("X-Trace"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Couch-Request-ID", "abc123")
+		if r.URL.Path == "/db/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		w.Header().Set("ETag", `"1-a"`)
+		_, _ = w.Write([]byte(`{"_id":"doc1","_rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+
+	var info ResponseInfo
+	ctx := WithResponseInfo(context.Background(), &info)
+	ctx = WithRequestHeader(ctx, "X-Trace", "trace-1")
+
+	_, err := db.Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodGet, info.Method)
+	assert.Equal(t, server.URL+"/db/doc1", info.URL)
+	assert.Equal(t, http.StatusOK, info.StatusCode)
+	assert.Equal(t, "1-a", info.ETag())
+	assert.Equal(t, "abc123", info.RequestID())
+	assert.Equal(t, int64(len(`{"_id":"doc1","_rev":"1-a"}`)), info.BodySize)
+	assert.Positive(t, info.Duration)
+	assert.Equal(t, 1, info.Requests)
+
+	_, err = db.Get(ctx, "missing")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, info.StatusCode)
+	assert.Empty(t, info.ETag())
+	assert.Equal(t, 2, info.Requests)
+
+	// Contexts without WithResponseInfo record nothing
+	_, err = db.Get(WithRequestHeader(context.Background(), "X-Trace", "trace-1"), "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Requests)
+}
+
+func TestRequestID(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-Request-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Couch-Request-ID", "couch-1")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+
+	// Errors carry both IDs
+	var info ResponseInfo
+	_, err := db.Get(WithResponseInfo(WithRequestID(context.Background(), "req-1"), &info), "doc1")
+	var couchErr *Error
+	require.ErrorAs(t, err, &couchErr)
+	assert.Equal(t, "req-1", couchErr.ClientRequestID)
+	assert.Equal(t, "couch-1", couchErr.RequestID)
+	assert.Equal(t, "req-1", info.ClientRequestID)
+	assert.Equal(t, "couch-1", info.RequestID())
+
+	// Without one in the context every request gets a random ID
+	_, _ = db.Get(context.Background(), "doc1")
+	_, _ = db.Get(context.Background(), "doc1")
+	require.Len(t, seen, 3)
+	assert.Regexp(t, `^[0-9a-f]{32}$`, seen[1])
+	assert.NotEqual(t, seen[1], seen[2])
+
+	_, _ = NewClient(server.URL, &ClientOptions{DisableRequestID: true}).DB("db").Get(context.Background(), "doc1")
+	assert.Empty(t, seen[3])
+}
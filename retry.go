@@ -0,0 +1,144 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultRetryStatusCodes are the statuses retried when RetryPolicy.RetryOn is empty
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures automatic retries of failed requests. Streaming
+// requests (feeds, attachment streams and /_replicate) are never retried.
+type RetryPolicy struct {
+	MaxRetries int // zero disables retries
+
+	// Backoff doubles from MinBackoff up to MaxBackoff. A Retry-After
+	// header from the server takes precedence but is capped at MaxBackoff.
+	MinBackoff time.Duration // defaults to 100ms
+	MaxBackoff time.Duration // defaults to 10s
+
+	// Jitter randomises each backoff by up to this fraction, between 0 and 1
+	Jitter float64
+
+	// RetryOn lists the HTTP statuses to retry. Defaults to DefaultRetryStatusCodes.
+	RetryOn []int
+
+	// RetryNonIdempotent also retries POST requests. A POST that reached the
+	// server before failing may then be applied twice.
+	RetryNonIdempotent bool
+}
+
+// apply installs the policy on a resty client
+func (p *RetryPolicy) apply(client *resty.Client, logger Logger) {
+	if p == nil || p.MaxRetries <= 0 {
+		return
+	}
+
+	policy := *p
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = 100 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 10 * time.Second
+	}
+	if policy.MaxBackoff < policy.MinBackoff {
+		policy.MaxBackoff = policy.MinBackoff
+	}
+	if len(policy.RetryOn) == 0 {
+		policy.RetryOn = DefaultRetryStatusCodes
+	}
+
+	client.
+		SetRetryCount(policy.MaxRetries).
+		SetRetryWaitTime(policy.MinBackoff).
+		SetRetryMaxWaitTime(policy.MaxBackoff).
+		AddRetryCondition(policy.shouldRetry).
+		SetRetryAfter(policy.backoff).
+		AddRetryHook(func(resp *resty.Response, err error) {
+			if err != nil {
+				logger.Debugf("retrying request after error: %v", err)
+			} else if resp != nil {
+				logger.Debugf("retrying %s %s after status %d", resp.Request.Method, resp.Request.URL, resp.StatusCode())
+			}
+		})
+}
+
+// shouldRetry reports whether a response or transport error is retryable
+func (p *RetryPolicy) shouldRetry(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+
+	if resp.Request.Method == http.MethodPost && !p.RetryNonIdempotent {
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	for _, status := range p.RetryOn {
+		if resp.StatusCode() == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the delay before the next attempt
+func (p *RetryPolicy) backoff(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	if wait, ok := retryAfter(resp); ok {
+		return min(wait, p.MaxBackoff), nil
+	}
+
+	attempt := 0
+	if resp != nil && resp.Request != nil {
+		attempt = resp.Request.Attempt - 1
+	}
+
+	wait := float64(p.MinBackoff) * math.Pow(2, float64(attempt))
+	wait = math.Min(wait, float64(p.MaxBackoff))
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		wait -= wait * jitter * rand.Float64()
+	}
+
+	return time.Duration(wait), nil
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as an HTTP date
+func retryAfter(resp *resty.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header().Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,65 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	attempts := map[string]int{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		attempts[r.Method]++
+		if attempts[r.Method] < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"too_many_requests","reason":"slow down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"id":"a","rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	policy := &RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	db := NewClient(server.URL, &ClientOptions{Retry: policy}).DB("db")
+
+	_, err := db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts["GET"])
+
+	_, err = db.Put(ctx, map[string]interface{}{"a": 1})
+	assert.True(t, isStatus(err, http.StatusTooManyRequests), "POST must not be retried by default")
+	assert.Equal(t, 1, attempts["POST"])
+
+	policy.RetryNonIdempotent = true
+	db = NewClient(server.URL, &ClientOptions{Retry: policy}).DB("db")
+	_, err = db.Put(ctx, map[string]interface{}{"a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts["POST"])
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	resp := &resty.Response{Request: &resty.Request{Attempt: 3}, RawResponse: &http.Response{Header: http.Header{}}}
+	wait, err := policy.backoff(nil, resp)
+	require.NoError(t, err)
+	assert.Equal(t, 400*time.Millisecond, wait)
+
+	resp.Request.Attempt = 10
+	wait, _ = policy.backoff(nil, resp)
+	assert.Equal(t, time.Second, wait)
+
+	resp.RawResponse.Header.Set("Retry-After", "7")
+	wait, _ = policy.backoff(nil, resp)
+	assert.Equal(t, time.Second, wait, "Retry-After is capped at MaxBackoff")
+}
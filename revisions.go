@@ -0,0 +1,208 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Revisions is a document's revision history, returned with revs=true
+type Revisions struct {
+	Start int      `json:"start"`
+	IDs   []string `json:"ids"` // revision hashes, newest first
+}
+
+// RevIDs returns the full revision IDs ("N-hash"), newest first
+func (r *Revisions) RevIDs() []string {
+	revs := make([]string, len(r.IDs))
+	for i, id := range r.IDs {
+		revs[i] = strconv.Itoa(r.Start-i) + "-" + id
+	}
+	return revs
+}
+
+// Revision status values reported in revs_info
+const (
+	RevisionAvailable = "available"
+	RevisionMissing   = "missing"
+	RevisionDeleted   = "deleted"
+)
+
+// RevisionInfo describes one revision and whether its body is still stored
+type RevisionInfo struct {
+	Rev    string `json:"rev"`
+	Status string `json:"status"`
+}
+
+// GetOptions holds options for GetWithOptions
+type GetOptions struct {
+	Rev              string // fetch a specific revision
+	Revs             bool   // include the revision history in Document.Revisions
+	RevsInfo         bool   // include revision availability in Document.RevsInfo
+	Conflicts        bool   // include conflicting revisions in Document.Conflicts
+	DeletedConflicts bool   // include deleted conflicting revisions
+	Latest           bool   // return the latest leaf of the requested revision's branch
+	Attachments      bool   // include attachment content
+	Meta             bool   // shorthand for conflicts, deleted_conflicts and revs_info
+
+	// R is the number of replicas that must agree on the document before
+	// the server responds. Zero uses the cluster default.
+	R int
+}
+
+func (o *GetOptions) queryParams() map[string]string {
+	params := make(map[string]string)
+	if o == nil {
+		return params
+	}
+
+	if o.Rev != "" {
+		params["rev"] = o.Rev
+	}
+	if o.R > 0 {
+		params["r"] = strconv.Itoa(o.R)
+	}
+	for name, set := range map[string]bool{
+		"revs":              o.Revs,
+		"revs_info":         o.RevsInfo,
+		"conflicts":         o.Conflicts,
+		"deleted_conflicts": o.DeletedConflicts,
+		"latest":            o.Latest,
+		"attachments":       o.Attachments,
+		"meta":              o.Meta,
+	} {
+		if set {
+			params[name] = "true"
+		}
+	}
+
+	return params
+}
+
+// GetWithOptions retrieves a document with revision metadata such as its
+// history, revision availability and conflicts
+func (db *Database) GetWithOptions(ctx context.Context, id string, opts *GetOptions) (*Document, error) {
+	var doc Document
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetQueryParams(opts.queryParams()).
+		SetResult(&doc).
+		Get(db.docPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &doc, nil
+}
+
+// OpenRev is one entry of an open_revs response: either a document or a
+// revision that could not be found
+type OpenRev struct {
+	OK      *Document `json:"ok,omitempty"`
+	Missing string    `json:"missing,omitempty"`
+}
+
+// GetOpenRevs retrieves the given revisions of a document. With no revs,
+// all leaf revisions are returned, including conflicts and deleted leaves.
+func (db *Database) GetOpenRevs(ctx context.Context, id string, revs []string, opts *GetOptions) ([]OpenRev, error) {
+	params, err := openRevsParams(revs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []OpenRev
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetQueryParams(params).
+		SetResult(&result).
+		Get(db.docPath(id))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return result, nil
+}
+
+// openRevsParams encodes the query of an open_revs request
+func openRevsParams(revs []string, opts *GetOptions) (map[string]string, error) {
+	params := opts.queryParams()
+	delete(params, "rev")
+
+	if len(revs) == 0 {
+		params["open_revs"] = "all"
+		return params, nil
+	}
+
+	encoded, err := json.Marshal(revs)
+	if err != nil {
+		return nil, err
+	}
+	params["open_revs"] = string(encoded)
+	return params, nil
+}
+
+// RevsDiffResult lists the revisions of one document the database lacks
+type RevsDiffResult struct {
+	Missing           []string `json:"missing"`
+	PossibleAncestors []string `json:"possible_ancestors,omitempty"`
+}
+
+// RevsDiff reports which of the given revisions, keyed by document ID, are
+// not stored in the database. Documents with no missing revisions are
+// omitted from the result.
+func (db *Database) RevsDiff(ctx context.Context, revs map[string][]string) (map[string]RevsDiffResult, error) {
+	result := make(map[string]RevsDiffResult)
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(revs).
+		SetResult(&result).
+		Post(db.path("_revs_diff"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return result, nil
+}
+
+// MissingRevs reports which of the given revisions, keyed by document ID,
+// are not stored in the database
+func (db *Database) MissingRevs(ctx context.Context, revs map[string][]string) (map[string][]string, error) {
+	var result struct {
+		MissingRevs map[string][]string `json:"missing_revs"`
+	}
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(revs).
+		SetResult(&result).
+		Post(db.path("_missing_revs"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	if result.MissingRevs == nil {
+		result.MissingRevs = make(map[string][]string)
+	}
+
+	return result.MissingRevs, nil
+}
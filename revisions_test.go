@@ -0,0 +1,188 @@
+package couchdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+
+		if q.Get("open_revs") != "" {
+			assert.Equal(t, `["2-b","2-x"]`, q.Get("open_revs"))
+			assert.Equal(t, "application/json", r.Header.Get("Accept"))
+			_, _ = w.Write([]byte(`[{"ok":{"_id":"a","_rev":"2-b","v":1}},{"missing":"2-x"}]`))
+			return
+		}
+
+		assert.Equal(t, "true", q.Get("revs"))
+		assert.Equal(t, "true", q.Get("revs_info"))
+		assert.Equal(t, "true", q.Get("conflicts"))
+		_, _ = w.Write([]byte(`{"_id":"a","_rev":"3-c","v":1,
+			"_revisions":{"start":3,"ids":["c","b","a"]},
+			"_revs_info":[{"rev":"3-c","status":"available"},{"rev":"2-b","status":"missing"}],
+			"_conflicts":["3-z"]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	doc, err := db.GetWithOptions(ctx, "a", &GetOptions{Revs: true, RevsInfo: true, Conflicts: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3-c", "2-b", "1-a"}, doc.Revisions.RevIDs())
+	assert.Equal(t, RevisionMissing, doc.RevsInfo[1].Status)
+	assert.Equal(t, []string{"3-z"}, doc.Conflicts)
+	assert.NotContains(t, doc.Data, "_revisions")
+
+	revs, err := db.GetOpenRevs(ctx, "a", []string{"2-b", "2-x"}, nil)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	assert.Equal(t, "2-b", revs[0].OK.Rev)
+	assert.Equal(t, "2-x", revs[1].Missing)
+}
+
+func TestResolveConflict(t *testing.T) {
+	var written []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/db/_bulk_docs":
+			var body struct {
+				Docs []map[string]interface{} `json:"docs"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			written = body.Docs
+			_, _ = w.Write([]byte(`[{"id":"a","rev":"3-w"},{"id":"a","rev":"3-d"}]`))
+		case r.URL.Query().Get("open_revs") != "":
+			_, _ = w.Write([]byte(`[{"ok":{"_id":"a","_rev":"2-y","v":2}}]`))
+		default:
+			_, _ = w.Write([]byte(`{"_id":"a","_rev":"2-x","v":1,"_conflicts":["2-y"]}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	set, err := db.GetConflicts(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, set.HasConflicts())
+	assert.Equal(t, []string{"2-y"}, set.Revs())
+	assert.Equal(t, float64(2), set.Conflicts[0].Data["v"])
+
+	merged := set.Current
+	merged.Data["v"] = 3
+	_, err = db.ResolveConflict(ctx, "a", merged, set.Revs()...)
+	require.NoError(t, err)
+
+	require.Len(t, written, 2)
+	assert.Equal(t, "2-x", written[0]["_rev"])
+	assert.Equal(t, float64(3), written[0]["v"])
+	assert.Equal(t, map[string]interface{}{"_id": "a", "_rev": "2-y", "_deleted": true}, written[1])
+}
+
+func TestRevsDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string][]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"2-b", "3-c"}, body["a"])
+
+		switch r.URL.Path {
+		case "/db/_revs_diff":
+			_, _ = w.Write([]byte(`{"a":{"missing":["3-c"],"possible_ancestors":["2-b"]}}`))
+		case "/db/_missing_revs":
+			_, _ = w.Write([]byte(`{"missing_revs":{"a":["3-c"]}}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	revs := map[string][]string{"a": {"2-b", "3-c"}}
+
+	diff, err := db.RevsDiff(context.Background(), revs)
+	require.NoError(t, err)
+	assert.Equal(t, RevsDiffResult{Missing: []string{"3-c"}, PossibleAncestors: []string{"2-b"}}, diff["a"])
+
+	missing, err := db.MissingRevs(context.Background(), revs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"a": {"3-c"}}, missing)
+}
+
+func TestGetOpenRevsMultipart(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/a", r.URL.Path)
+		assert.Equal(t, "all", r.URL.Query().Get("open_revs"))
+		assert.Equal(t, "multipart/mixed", r.Header.Get("Accept"))
+
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+
+		// A revision with an attachment, nested as multipart/related
+		var related bytes.Buffer
+		rw := multipart.NewWriter(&related)
+		part, _ := rw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+		_, _ = part.Write([]byte(`{"_id":"a","_rev":"2-b","_attachments":{"note.txt":{"follows":true,"content_type":"text/plain","length":5}}}`))
+		part, _ = rw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":        {"text/plain"},
+			"Content-Disposition": {`attachment; filename="note.txt"`},
+		})
+		_, _ = part.Write([]byte("hello"))
+		_ = rw.Close()
+
+		part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"multipart/related; boundary=" + rw.Boundary()}})
+		_, _ = part.Write(related.Bytes())
+
+		part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+		_, _ = part.Write([]byte(`{"_id":"a","_rev":"2-x","_deleted":true}`))
+
+		part, _ = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {`application/json; error="true"`}})
+		_, _ = part.Write([]byte(`{"missing":"3-z"}`))
+		_ = mw.Close()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	revs, err := db.GetOpenRevsMultipart(context.Background(), "a", nil, &GetOptions{Attachments: true})
+	require.NoError(t, err)
+	defer revs.Close()
+
+	rev, err := revs.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", rev.Doc.Doc.Rev)
+	attachment, err := rev.Doc.NextAttachment()
+	require.NoError(t, err)
+	assert.Equal(t, "note.txt", attachment.Name)
+	content, err := io.ReadAll(attachment.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	rev, err = revs.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "2-x", rev.Doc.Doc.Rev)
+	assert.True(t, rev.Doc.Doc.Deleted)
+	_, err = rev.Doc.NextAttachment()
+	assert.Equal(t, io.EOF, err)
+
+	rev, err = revs.Next()
+	require.NoError(t, err)
+	assert.Nil(t, rev.Doc)
+	assert.Equal(t, "3-z", rev.Missing)
+
+	_, err = revs.Next()
+	assert.Equal(t, io.EOF, err)
+}
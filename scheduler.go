@@ -0,0 +1,228 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Replication states reported by the scheduler
+const (
+	ReplicationInitializing = "initializing"
+	ReplicationError        = "error"
+	ReplicationPending      = "pending"
+	ReplicationRunning      = "running"
+	ReplicationCrashing     = "crashing"
+	ReplicationCompleted    = "completed"
+	ReplicationFailed       = "failed"
+)
+
+// DefaultReplicatorDB is the database holding replication documents
+const DefaultReplicatorDB = "_replicator"
+
+// SchedulerDoc represents the scheduler state of a replication document
+type SchedulerDoc struct {
+	Database    string            `json:"database"`
+	DocID       string            `json:"doc_id"`
+	ID          string            `json:"id"`
+	Node        string            `json:"node"`
+	Source      string            `json:"source"`
+	Target      string            `json:"target"`
+	State       string            `json:"state"`
+	ErrorCount  int               `json:"error_count"`
+	Info        *SchedulerDocInfo `json:"info"`
+	StartTime   string            `json:"start_time"`
+	LastUpdated string            `json:"last_updated"`
+}
+
+// SchedulerDocInfo holds replication progress statistics or the last error
+type SchedulerDocInfo struct {
+	RevisionsChecked      int64       `json:"revisions_checked"`
+	MissingRevisionsFound int64       `json:"missing_revisions_found"`
+	DocsRead              int64       `json:"docs_read"`
+	DocsWritten           int64       `json:"docs_written"`
+	DocWriteFailures      int64       `json:"doc_write_failures"`
+	ChangesPending        *int64      `json:"changes_pending"`
+	CheckpointedSourceSeq interface{} `json:"checkpointed_source_seq,omitempty"`
+	SourceSeq             interface{} `json:"source_seq,omitempty"`
+	ThroughSeq            interface{} `json:"through_seq,omitempty"`
+	Error                 string      `json:"error,omitempty"`
+}
+
+// GetSchedulerDoc returns the scheduler state of a single replication document
+func (c *Client) GetSchedulerDoc(ctx context.Context, replicatorDB, docID string) (*SchedulerDoc, error) {
+	if replicatorDB == "" {
+		replicatorDB = DefaultReplicatorDB
+	}
+
+	var doc SchedulerDoc
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&doc).
+		Get(buildPath("_scheduler", "docs", replicatorDB, docID))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &doc, nil
+}
+
+// SchedulerDocs returns the scheduler state of every replication document
+// in replicatorDB, or in all replicator databases if replicatorDB is empty
+func (c *Client) SchedulerDocs(ctx context.Context, replicatorDB string) ([]SchedulerDoc, error) {
+	path := "/_scheduler/docs"
+	if replicatorDB != "" {
+		// Prefixed replicator databases such as "team/_replicator" form one path segment
+		path += buildPath(replicatorDB)
+	}
+
+	var result struct {
+		TotalRows int            `json:"total_rows"`
+		Docs      []SchedulerDoc `json:"docs"`
+	}
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return result.Docs, nil
+}
+
+// Replication job event types recorded in SchedulerJob.History
+const (
+	JobAdded   = "added"
+	JobStarted = "started"
+	JobCrashed = "crashed"
+	JobStopped = "stopped"
+)
+
+// SchedulerJob is a replication job known to the scheduler. Transient
+// replications have no Database or DocID.
+type SchedulerJob struct {
+	Database  string              `json:"database"`
+	DocID     string              `json:"doc_id"`
+	ID        string              `json:"id"`
+	Node      string              `json:"node"`
+	PID       string              `json:"pid"`
+	Source    string              `json:"source"`
+	Target    string              `json:"target"`
+	User      string              `json:"user"`
+	StartTime string              `json:"start_time"`
+	History   []SchedulerJobEvent `json:"history"`
+	Info      *SchedulerDocInfo   `json:"info"`
+}
+
+// SchedulerJobEvent is one entry of a job's history, newest first
+type SchedulerJobEvent struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason,omitempty"` // set for crashed events
+}
+
+// Running reports whether the job's latest event is a start
+func (j *SchedulerJob) Running() bool {
+	return len(j.History) > 0 && j.History[0].Type == JobStarted
+}
+
+// SchedulerJobs returns the replication jobs currently managed by the scheduler
+func (c *Client) SchedulerJobs(ctx context.Context) ([]SchedulerJob, error) {
+	var result struct {
+		TotalRows int            `json:"total_rows"`
+		Jobs      []SchedulerJob `json:"jobs"`
+	}
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&result).
+		Get("/_scheduler/jobs")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return result.Jobs, nil
+}
+
+// WaitOptions controls how WaitForReplication polls the scheduler
+type WaitOptions struct {
+	ReplicatorDB string              // defaults to "_replicator"
+	PollInterval time.Duration       // defaults to one second
+	Timeout      time.Duration       // zero means wait until ctx is done
+	Progress     func(*SchedulerDoc) // called after every poll
+}
+
+// WaitForReplication polls _scheduler/docs until the replication document
+// reaches the completed or failed state. A failed replication is returned
+// together with an error describing the failure. If ctx ends or Timeout
+// passes first, the last state seen is returned with the context's error.
+func (c *Client) WaitForReplication(ctx context.Context, replicationID string, opts *WaitOptions) (*SchedulerDoc, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *SchedulerDoc
+	for {
+		doc, err := c.GetSchedulerDoc(ctx, opts.ReplicatorDB, replicationID)
+		// The scheduler may not have picked up a freshly written document yet
+		if err != nil && !isStatus(err, 404) {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			return nil, err
+		}
+
+		if doc != nil {
+			last = doc
+			if opts.Progress != nil {
+				opts.Progress(doc)
+			}
+
+			switch doc.State {
+			case ReplicationCompleted:
+				return doc, nil
+			case ReplicationFailed:
+				reason := "unknown error"
+				if doc.Info != nil && doc.Info.Error != "" {
+					reason = doc.Info.Error
+				}
+				return doc, fmt.Errorf("replication %s failed: %s", replicationID, reason)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
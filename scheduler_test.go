@@ -0,0 +1,97 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/_scheduler/jobs":
+			_, _ = w.Write([]byte(`{"total_rows":1,"offset":0,"jobs":[{
+				"database":"_replicator","doc_id":"rep1","id":"abc+continuous","node":"a@h1","pid":"<0.1.0>",
+				"source":"http://a/db/","target":"http://b/db/","user":"admin","start_time":"2024-01-01T00:00:00Z",
+				"history":[{"timestamp":"2024-01-01T00:00:05Z","type":"started"},{"timestamp":"2024-01-01T00:00:00Z","type":"added"}],
+				"info":{"docs_written":5}
+			}]}`))
+		case "/_scheduler/docs/team%2F_replicator":
+			_, _ = w.Write([]byte(`{"total_rows":1,"offset":0,"docs":[{"database":"team/_replicator","doc_id":"rep2","state":"crashing","error_count":3,"info":{"error":"db_not_found"}}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	jobs, err := client.SchedulerJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.True(t, jobs[0].Running())
+	assert.Equal(t, int64(5), jobs[0].Info.DocsWritten)
+
+	docs, err := client.SchedulerDocs(ctx, "team/_replicator")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, ReplicationCrashing, docs[0].State)
+	assert.Equal(t, "db_not_found", docs[0].Info.Error)
+}
+
+func TestWaitForReplication(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/_scheduler/docs/_replicator/nightly%2Fbackup%2B1":
+			// Not picked up by the scheduler yet, then running, then done
+			switch polls.Add(1) {
+			case 1:
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			case 2:
+				_, _ = w.Write([]byte(`{"doc_id":"nightly/backup+1","state":"running","info":{"docs_written":3}}`))
+			default:
+				_, _ = w.Write([]byte(`{"doc_id":"nightly/backup+1","state":"completed","info":{"docs_written":7}}`))
+			}
+		case "/_scheduler/docs/team%2F_replicator/broken":
+			_, _ = w.Write([]byte(`{"doc_id":"broken","state":"failed","info":{"error":"db_not_found: could not open source"}}`))
+		case "/_scheduler/docs/_replicator/slow":
+			_, _ = w.Write([]byte(`{"doc_id":"slow","state":"running"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	var states []string
+	doc, err := client.WaitForReplication(ctx, "nightly/backup+1", &WaitOptions{
+		PollInterval: 5 * time.Millisecond,
+		Progress:     func(doc *SchedulerDoc) { states = append(states, doc.State) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), doc.Info.DocsWritten)
+	assert.Equal(t, []string{ReplicationRunning, ReplicationCompleted}, states)
+
+	doc, err = client.WaitForReplication(ctx, "broken", &WaitOptions{ReplicatorDB: "team/_replicator"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db_not_found: could not open source")
+	assert.Equal(t, ReplicationFailed, doc.State)
+
+	doc, err = client.WaitForReplication(ctx, "slow", &WaitOptions{PollInterval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, ReplicationRunning, doc.State)
+}
@@ -0,0 +1,116 @@
+package couchdb
+
+import "context"
+
+// SearchEngine selects the full-text search backend of an index
+type SearchEngine int
+
+const (
+	// SearchClouseau queries indexes defined under "indexes" (the _search endpoint)
+	SearchClouseau SearchEngine = iota
+	// SearchNouveau queries indexes defined under "nouveau" (CouchDB 3.4+)
+	SearchNouveau
+)
+
+// SearchQuery holds a full-text search query. Options not supported by the
+// selected engine are ignored by the server.
+type SearchQuery struct {
+	Engine SearchEngine `json:"-"`
+
+	Query       string   `json:"q"` // Lucene query syntax
+	Sort        []string `json:"sort,omitempty"`
+	Bookmark    string   `json:"bookmark,omitempty"`
+	Limit       int      `json:"limit,omitempty"`
+	IncludeDocs bool     `json:"include_docs,omitempty"`
+
+	// Facets. Ranges use the engine's format: {"price": {"cheap": "[0 TO 100]"}}
+	// for Clouseau, {"price": [{"label": "cheap", "min": 0, "max": 100}]} for Nouveau.
+	Counts    []string               `json:"counts,omitempty"`
+	Ranges    map[string]interface{} `json:"ranges,omitempty"`
+	Drilldown [][]string             `json:"drilldown,omitempty"` // Clouseau only
+
+	// Highlighting (Clouseau only)
+	HighlightFields  []string `json:"highlight_fields,omitempty"`
+	HighlightPreTag  string   `json:"highlight_pre_tag,omitempty"`
+	HighlightPostTag string   `json:"highlight_post_tag,omitempty"`
+	HighlightNumber  int      `json:"highlight_number,omitempty"`
+	HighlightSize    int      `json:"highlight_size,omitempty"`
+
+	IncludeFields []string `json:"include_fields,omitempty"` // Clouseau only
+	Update        *bool    `json:"update,omitempty"`
+}
+
+// SearchResult holds the rows and facets of a search
+type SearchResult struct {
+	TotalRows int64
+	// TotalRowsRelation is "EQUAL_TO" or "GREATER_THAN_OR_EQUAL_TO" for
+	// Nouveau, where the total may be a lower bound
+	TotalRowsRelation string
+	Bookmark          string
+	Rows              []SearchRow
+	Counts            map[string]map[string]int64
+	Ranges            map[string]map[string]int64
+}
+
+// SearchRow is a single search hit
+type SearchRow struct {
+	ID         string                 `json:"id"`
+	Order      []interface{}          `json:"order"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Highlights map[string][]string    `json:"highlights,omitempty"`
+	Doc        *Document              `json:"doc,omitempty"`
+}
+
+// Search runs a full-text query against a search index. A nil query is
+// sent as an empty one.
+func (db *Database) Search(ctx context.Context, designDoc, indexName string, query *SearchQuery) (*SearchResult, error) {
+	if query == nil {
+		query = &SearchQuery{}
+	}
+
+	endpoint := "_search"
+	if query.Engine == SearchNouveau {
+		endpoint = "_nouveau"
+	}
+
+	// Clouseau reports rows and total_rows, Nouveau hits and total_hits
+	var raw struct {
+		TotalRows         int64                       `json:"total_rows"`
+		TotalHits         int64                       `json:"total_hits"`
+		TotalHitsRelation string                      `json:"total_hits_relation"`
+		Bookmark          string                      `json:"bookmark"`
+		Rows              []SearchRow                 `json:"rows"`
+		Hits              []SearchRow                 `json:"hits"`
+		Counts            map[string]map[string]int64 `json:"counts"`
+		Ranges            map[string]map[string]int64 `json:"ranges"`
+	}
+
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(query).
+		SetResult(&raw).
+		Post(db.designPath(designDoc, endpoint, indexName))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	result := &SearchResult{
+		TotalRows:         raw.TotalRows,
+		TotalRowsRelation: raw.TotalHitsRelation,
+		Bookmark:          raw.Bookmark,
+		Rows:              raw.Rows,
+		Counts:            raw.Counts,
+		Ranges:            raw.Ranges,
+	}
+	if query.Engine == SearchNouveau {
+		result.TotalRows = raw.TotalHits
+		result.Rows = raw.Hits
+	}
+
+	return result, nil
+}
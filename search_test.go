@@ -0,0 +1,71 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "name:alice", body["q"])
+
+		switch r.URL.Path {
+		case "/db/_design/app/_search/people":
+			assert.Equal(t, []interface{}{"name"}, body["highlight_fields"])
+			_, _ = w.Write([]byte(`{"total_rows":1,"bookmark":"g1","rows":[
+				{"id":"a","order":[1.5,0],"fields":{"name":"alice"},"highlights":{"name":["<em>alice</em>"]}}
+			],"counts":{"type":{"user":1}}}`))
+		case "/db/_design/app/_nouveau/people":
+			assert.NotContains(t, body, "highlight_fields")
+			_, _ = w.Write([]byte(`{"total_hits":3,"total_hits_relation":"EQUAL_TO","bookmark":"n1","hits":[
+				{"id":"a","order":[{"@type":"float","value":1.5}],"fields":{"name":"alice"}}
+			]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	result, err := db.Search(ctx, "app", "people", &SearchQuery{Query: "name:alice", HighlightFields: []string{"name"}, Counts: []string{"type"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.TotalRows)
+	assert.Equal(t, []string{"<em>alice</em>"}, result.Rows[0].Highlights["name"])
+	assert.Equal(t, int64(1), result.Counts["type"]["user"])
+
+	result, err = db.Search(ctx, "app", "people", &SearchQuery{Engine: SearchNouveau, Query: "name:alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.TotalRows)
+	assert.Equal(t, "EQUAL_TO", result.TotalRowsRelation)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, "a", result.Rows[0].ID)
+}
+
+func TestSearch_NilQuery(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/db/_design/app/_search/people", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_rows":0,"rows":[]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	result, err := db.Search(context.Background(), "app", "people", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"q": ""}, body)
+	assert.Empty(t, result.Rows)
+}
@@ -0,0 +1,175 @@
+package couchdb
+
+import "context"
+
+// SecurityObject represents a database _security document
+type SecurityObject struct {
+	Admins  SecurityMembers `json:"admins"`
+	Members SecurityMembers `json:"members"`
+}
+
+// SecurityMembers lists the user names and roles of a security group
+type SecurityMembers struct {
+	Names []string `json:"names,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// GetSecurity returns the database security object
+func (db *Database) GetSecurity(ctx context.Context) (*SecurityObject, error) {
+	var security SecurityObject
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetResult(&security).
+		Get(db.path("_security"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
+
+	return &security, nil
+}
+
+// SetSecurity replaces the database security object
+func (db *Database) SetSecurity(ctx context.Context, security *SecurityObject) error {
+	return db.putSecurity(ctx, security)
+}
+
+func (db *Database) putSecurity(ctx context.Context, security interface{}) error {
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetBody(security).
+		Put(db.path("_security"))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	return nil
+}
+
+// AddMember grants a user member access
+func (db *Database) AddMember(ctx context.Context, name string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return addUnique(&s.Members.Names, name) })
+}
+
+// AddMemberRole grants a role member access
+func (db *Database) AddMemberRole(ctx context.Context, role string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return addUnique(&s.Members.Roles, role) })
+}
+
+// RemoveMember revokes a user's member access
+func (db *Database) RemoveMember(ctx context.Context, name string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return removeAll(&s.Members.Names, name) })
+}
+
+// RemoveMemberRole revokes a role's member access
+func (db *Database) RemoveMemberRole(ctx context.Context, role string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return removeAll(&s.Members.Roles, role) })
+}
+
+// AddAdmin grants a user admin access
+func (db *Database) AddAdmin(ctx context.Context, name string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return addUnique(&s.Admins.Names, name) })
+}
+
+// AddAdminRole grants a role admin access
+func (db *Database) AddAdminRole(ctx context.Context, role string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return addUnique(&s.Admins.Roles, role) })
+}
+
+// RemoveAdmin revokes a user's admin access
+func (db *Database) RemoveAdmin(ctx context.Context, name string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return removeAll(&s.Admins.Names, name) })
+}
+
+// RemoveAdminRole revokes a role's admin access
+func (db *Database) RemoveAdminRole(ctx context.Context, role string) error {
+	return db.updateSecurity(ctx, func(s *SecurityObject) bool { return removeAll(&s.Admins.Roles, role) })
+}
+
+// updateSecurity fetches the security object, applies modify and writes it
+// back only if it changed. The object is read and written as a map, so
+// fields SecurityObject does not model are kept.
+func (db *Database) updateSecurity(ctx context.Context, modify func(*SecurityObject) bool) error {
+	var raw map[string]interface{}
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetResult(&raw).
+		Get(db.path("_security"))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	var security SecurityObject
+	if err := convertDoc(raw, &security); err != nil {
+		return err
+	}
+
+	if !modify(&security) {
+		return nil
+	}
+
+	if raw == nil {
+		raw = make(map[string]interface{})
+	}
+	setSecurityMembers(raw, "admins", security.Admins)
+	setSecurityMembers(raw, "members", security.Members)
+
+	return db.putSecurity(ctx, raw)
+}
+
+// setSecurityMembers stores the names and roles of a group in raw, keeping
+// the group's other fields
+func setSecurityMembers(raw map[string]interface{}, group string, members SecurityMembers) {
+	fields, _ := raw[group].(map[string]interface{})
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+
+	for key, values := range map[string][]string{"names": members.Names, "roles": members.Roles} {
+		if len(values) > 0 {
+			fields[key] = values
+		} else {
+			delete(fields, key)
+		}
+	}
+
+	raw[group] = fields
+}
+
+func addUnique(list *[]string, value string) bool {
+	for _, v := range *list {
+		if v == value {
+			return false
+		}
+	}
+
+	*list = append(*list, value)
+	return true
+}
+
+func removeAll(list *[]string, value string) bool {
+	kept := (*list)[:0]
+	for _, v := range *list {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+
+	changed := len(kept) != len(*list)
+	*list = kept
+	return changed
+}
@@ -0,0 +1,90 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityRoleHelpers(t *testing.T) {
+	security := SecurityObject{Members: SecurityMembers{Names: []string{"alice"}}}
+	writes := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/_security", r.URL.Path)
+
+		switch r.Method {
+		case "GET":
+			_ = json.NewEncoder(w).Encode(security)
+		case "PUT":
+			writes++
+			security = SecurityObject{}
+			_ = json.NewDecoder(r.Body).Decode(&security)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	require.NoError(t, db.AddMember(ctx, "alice"))
+	assert.Equal(t, 0, writes, "adding an existing member must not write")
+
+	require.NoError(t, db.AddMember(ctx, "bob"))
+	require.NoError(t, db.AddAdminRole(ctx, "ops"))
+	assert.Equal(t, []string{"alice", "bob"}, security.Members.Names)
+	assert.Equal(t, []string{"ops"}, security.Admins.Roles)
+
+	require.NoError(t, db.RemoveMember(ctx, "alice"))
+	assert.Equal(t, []string{"bob"}, security.Members.Names)
+
+	require.NoError(t, db.RemoveAdmin(ctx, "nobody"))
+	assert.Equal(t, 3, writes)
+}
+
+func TestSecurityObject_JSON(t *testing.T) {
+	var security SecurityObject
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &security))
+	assert.Empty(t, security.Admins.Names)
+	assert.Empty(t, security.Members.Roles)
+
+	security = SecurityObject{
+		Admins:  SecurityMembers{Roles: []string{"ops"}},
+		Members: SecurityMembers{Names: []string{"alice"}, Roles: []string{"readers"}},
+	}
+	data, err := json.Marshal(security)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"admins":{"roles":["ops"]},"members":{"names":["alice"],"roles":["readers"]}}`, string(data))
+}
+
+func TestSecurityRoleHelpers_KeepUnmodeledFields(t *testing.T) {
+	stored := `{"members":{"names":["alice"],"roles":["readers"],"note":"synced by ops"},"couchdb_auth_only":true}`
+	var written map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PUT" {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(stored))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	require.NoError(t, db.RemoveMember(context.Background(), "alice"))
+
+	assert.Equal(t, map[string]interface{}{
+		"members":           map[string]interface{}{"roles": []interface{}{"readers"}, "note": "synced by ops"},
+		"admins":            map[string]interface{}{},
+		"couchdb_auth_only": true,
+	}, written)
+}
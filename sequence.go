@@ -0,0 +1,79 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Sequence is an update sequence. CouchDB 2.0+ reports opaque strings while
+// older servers use integers; both decode into a Sequence.
+type Sequence string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Sequence) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = Sequence(str)
+		return nil
+	}
+
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+
+	*s = Sequence(strings.TrimSpace(string(data)))
+	return nil
+}
+
+// String returns the sequence as a string
+func (s Sequence) String() string {
+	return string(s)
+}
+
+// Number returns the numeric prefix of the sequence: the whole value for
+// CouchDB 1.x integers, the part before the dash for CouchDB 2.0+ strings
+// such as "12-g1AAAA...". It is zero for empty or unparsable sequences.
+func (s Sequence) Number() int64 {
+	str := string(s)
+	if i := strings.IndexByte(str, '-'); i >= 0 {
+		str = str[:i]
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// Compare orders two sequences of the same database by their numeric
+// prefix, returning -1, 0 or +1. In a cluster the prefix is the sum of the
+// shard sequences, so it grows with every update but sequences read from
+// different nodes may compare equal or reversed for a short while; the
+// opaque part is never compared.
+func (s Sequence) Compare(other Sequence) int {
+	a, b := s.Number(), other.Number()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// After reports whether s is later than other, see Compare
+func (s Sequence) After(other Sequence) bool {
+	return s.Compare(other) > 0
+}
+
+// IsZero reports whether the sequence is empty or "0", i.e. the start of the database
+func (s Sequence) IsZero() bool {
+	return s == "" || s == "0"
+}
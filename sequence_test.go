@@ -0,0 +1,22 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence(t *testing.T) {
+	var info DatabaseInfo
+	require.NoError(t, json.Unmarshal([]byte(`{"update_seq":"12-g1AAAA","purge_seq":0}`), &info))
+	assert.Equal(t, int64(12), info.UpdateSeq.Number())
+	assert.True(t, info.PurgeSeq.IsZero())
+
+	assert.True(t, Sequence("12-g1AAAA").After("9-g1BBBB"))
+	assert.False(t, Sequence("9").After("12"))
+	assert.Equal(t, 0, Sequence("7-a").Compare("7-b"))
+	assert.Equal(t, -1, Sequence("").Compare("1"))
+	assert.Equal(t, int64(0), Sequence("now").Number())
+}
@@ -0,0 +1,209 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	sessionCookieName = "AuthSession"
+	sessionPath       = "/_session"
+
+	// renew sessions once this fraction of the timeout has elapsed
+	sessionRenewFraction = 0.8
+)
+
+// SessionInfo describes the current authentication session
+type SessionInfo struct {
+	OK      bool        `json:"ok"`
+	UserCtx UserContext `json:"userCtx"`
+	Info    struct {
+		Authenticated          string   `json:"authenticated,omitempty"`
+		AuthenticationDB       string   `json:"authentication_db,omitempty"`
+		AuthenticationHandlers []string `json:"authentication_handlers,omitempty"`
+	} `json:"info"`
+}
+
+// UserContext identifies the user a request is made as
+type UserContext struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// sessionState holds the cookie session established by Login
+type sessionState struct {
+	timeout time.Duration
+
+	mu       sync.Mutex
+	cookie   *http.Cookie
+	issued   time.Time
+	username string
+	password string
+
+	renewMu sync.Mutex
+}
+
+func newSessionState(timeout time.Duration) *sessionState {
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+	return &sessionState{timeout: timeout}
+}
+
+// Login authenticates with cookie authentication. Subsequent requests carry
+// the session cookie, which is renewed automatically before it expires.
+func (c *Client) Login(ctx context.Context, username, password string) (*UserContext, error) {
+	userCtx, err := c.login(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	c.session.mu.Lock()
+	c.session.username = username
+	c.session.password = password
+	c.session.mu.Unlock()
+
+	return userCtx, nil
+}
+
+func (c *Client) login(ctx context.Context, username, password string) (*UserContext, error) {
+	var result struct {
+		OK    bool     `json:"ok"`
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+	}
+
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetBody(map[string]string{"name": username, "password": password}).
+		SetResult(&result).
+		Post(sessionPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &UserContext{Name: result.Name, Roles: result.Roles}, nil
+}
+
+// Logout ends the cookie session
+func (c *Client) Logout(ctx context.Context) error {
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		Delete(sessionPath)
+
+	c.session.mu.Lock()
+	c.session.cookie = nil
+	c.session.username = ""
+	c.session.password = ""
+	c.session.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// Session returns information about the current session and its roles
+func (c *Client) Session(ctx context.Context) (*SessionInfo, error) {
+	var info SessionInfo
+	resp, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&info).
+		Get(sessionPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, c.parseError(resp)
+	}
+
+	return &info, nil
+}
+
+// applySession attaches the session cookie, renewing the session first if it is close to expiry
+func (c *Client) applySession(_ *resty.Client, req *resty.Request) error {
+	if req.URL == sessionPath {
+		if cookie := c.sessionCookie(); cookie != nil && req.Method != http.MethodPost {
+			req.SetCookie(cookie)
+		}
+		return nil
+	}
+
+	c.renewSession(req.Context())
+
+	if cookie := c.sessionCookie(); cookie != nil {
+		req.SetCookie(cookie)
+	}
+	return nil
+}
+
+func (c *Client) renewSession(ctx context.Context) {
+	s := c.session
+
+	s.mu.Lock()
+	due := s.cookie != nil && s.username != "" &&
+		time.Since(s.issued) > time.Duration(float64(s.timeout)*sessionRenewFraction)
+	username, password := s.username, s.password
+	s.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	s.renewMu.Lock()
+	defer s.renewMu.Unlock()
+
+	// Another request may have renewed the session while we waited
+	s.mu.Lock()
+	due = time.Since(s.issued) > time.Duration(float64(s.timeout)*sessionRenewFraction)
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	if _, err := c.login(ctx, username, password); err != nil {
+		c.logger.Warnf("session renewal failed: %v", err)
+	}
+}
+
+// captureSession stores session cookies set by the server, including the
+// refreshed cookies CouchDB issues on regular requests
+func (c *Client) captureSession(_ *resty.Client, resp *resty.Response) error {
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name != sessionCookieName {
+			continue
+		}
+
+		c.session.mu.Lock()
+		if cookie.Value == "" || cookie.MaxAge < 0 {
+			c.session.cookie = nil
+		} else {
+			c.session.cookie = &http.Cookie{Name: cookie.Name, Value: cookie.Value}
+			c.session.issued = time.Now()
+		}
+		c.session.mu.Unlock()
+	}
+	return nil
+}
+
+func (c *Client) sessionCookie() *http.Cookie {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	return c.session.cookie
+}
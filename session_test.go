@@ -0,0 +1,65 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieSession(t *testing.T) {
+	logins := 0
+	var lastCookie string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		lastCookie = ""
+		if cookie, err := r.Cookie("AuthSession"); err == nil {
+			lastCookie = cookie.Value
+		}
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/_session":
+			logins++
+			var creds map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&creds)
+			assert.Equal(t, "alice", creds["name"])
+			http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "token-" + string(rune('0'+logins))})
+			_, _ = w.Write([]byte(`{"ok":true,"name":"alice","roles":["reader"]}`))
+		case r.Method == "DELETE" && r.URL.Path == "/_session":
+			http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "", MaxAge: -1})
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"couchdb":"Welcome","version":"3.3.2"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, &ClientOptions{SessionTimeout: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	userCtx, err := client.Login(ctx, "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reader"}, userCtx.Roles)
+
+	_, err = client.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", lastCookie)
+	assert.Equal(t, 1, logins)
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = client.Info(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, logins, "expired session must be renewed")
+	assert.Equal(t, "token-2", lastCookie)
+
+	require.NoError(t, client.Logout(ctx))
+	_, err = client.Info(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, lastCookie)
+}
@@ -0,0 +1,120 @@
+package couchdb
+
+import (
+	"context"
+	"time"
+)
+
+// SoftDeleteOptions configures WithSoftDelete
+type SoftDeleteOptions struct {
+	Field     string // flag set to true on deleted documents, defaults to "deleted"
+	TimeField string // time of deletion, defaults to "deleted_at"
+	Layout    string // time format, defaults to time.RFC3339Nano
+
+	// IncludeDeleted keeps soft-deleted documents in Find, View and AllDocs
+	// results, e.g. for a trash listing
+	IncludeDeleted bool
+
+	// Now returns the current time, defaults to time.Now in UTC
+	Now func() time.Time
+}
+
+// WithSoftDelete returns a handle on the same database whose Delete,
+// DeleteWithOptions and DeleteDoc flag documents as deleted instead of
+// removing them, so they can be brought back with Restore. Find leaves
+// flagged documents out with an extra selector condition, and View and
+// AllDocs drop rows whose included document is flagged; TotalRows still
+// counts them. Handles without soft delete, such as the one returned by
+// Client.DB, delete for real.
+func (db *Database) WithSoftDelete(opts *SoftDeleteOptions) *Database {
+	handle := *db
+	handle.softDelete = softDeleteDefaults(opts)
+	return &handle
+}
+
+func softDeleteDefaults(opts *SoftDeleteOptions) *SoftDeleteOptions {
+	o := SoftDeleteOptions{Field: "deleted", TimeField: "deleted_at", Layout: time.RFC3339Nano}
+	if opts != nil {
+		if opts.Field != "" {
+			o.Field = opts.Field
+		}
+		if opts.TimeField != "" {
+			o.TimeField = opts.TimeField
+		}
+		if opts.Layout != "" {
+			o.Layout = opts.Layout
+		}
+		o.IncludeDeleted = opts.IncludeDeleted
+		o.Now = opts.Now
+	}
+	if o.Now == nil {
+		o.Now = func() time.Time { return time.Now().UTC() }
+	}
+	return &o
+}
+
+// softDeleteDoc flags revision rev of a document as deleted
+func (db *Database) softDeleteDoc(ctx context.Context, id, rev string, opts *WriteOptions) (*Document, error) {
+	var doc map[string]interface{}
+	if err := db.GetInto(ctx, id, &doc, rev); err != nil {
+		return nil, err
+	}
+
+	doc["_rev"] = rev
+	doc[db.softDelete.Field] = true
+	doc[db.softDelete.TimeField] = db.softDelete.Now().Format(db.softDelete.Layout)
+
+	return db.UpdateWithOptions(ctx, id, doc, opts)
+}
+
+// Restore clears the soft-delete flags of a document, using the field
+// names of the handle's SoftDeleteOptions or the defaults
+func (db *Database) Restore(ctx context.Context, id string) (*Document, error) {
+	o := db.softDelete
+	if o == nil {
+		o = softDeleteDefaults(nil)
+	}
+
+	var doc map[string]interface{}
+	if err := db.GetInto(ctx, id, &doc); err != nil {
+		return nil, err
+	}
+
+	delete(doc, o.Field)
+	delete(doc, o.TimeField)
+
+	return db.Update(ctx, id, doc)
+}
+
+// excludeDeleted adds a condition leaving out soft-deleted documents to a
+// copy of query, if the handle filters them
+func (db *Database) excludeDeleted(query *FindQuery) *FindQuery {
+	if db.softDelete == nil || db.softDelete.IncludeDeleted {
+		return query
+	}
+
+	q := *query
+	notDeleted := Not(Eq(db.softDelete.Field, true))
+	if len(q.Selector) == 0 {
+		q.Selector = notDeleted
+	} else {
+		q.Selector = And(q.Selector, notDeleted)
+	}
+	return &q
+}
+
+// dropDeletedRows removes the rows whose included document is soft-deleted
+func (db *Database) dropDeletedRows(result *ViewResult) {
+	if db.softDelete == nil || db.softDelete.IncludeDeleted {
+		return
+	}
+
+	rows := result.Rows[:0]
+	for _, row := range result.Rows {
+		if row.Doc != nil && row.Doc.Data[db.softDelete.Field] == true {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	result.Rows = rows
+}
@@ -0,0 +1,91 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDelete(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("db")
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plain := NewClient(server.URL, nil).DB("db")
+	db := plain.WithSoftDelete(&SoftDeleteOptions{Now: func() time.Time { return now }})
+	ctx := context.Background()
+
+	a, err := db.Put(ctx, map[string]interface{}{"_id": "a"})
+	require.NoError(t, err)
+	_, err = db.Put(ctx, map[string]interface{}{"_id": "b"})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Delete(ctx, "a", a.Rev))
+
+	doc, err := db.Get(ctx, "a")
+	require.NoError(t, err, "the document is kept")
+	assert.Equal(t, true, doc.Data["deleted"])
+	assert.Equal(t, "2024-05-01T12:00:00Z", doc.Data["deleted_at"])
+
+	all, err := db.AllDocs(ctx, &ViewOptions{IncludeDocs: true})
+	require.NoError(t, err)
+	require.Len(t, all.Rows, 1)
+	assert.Equal(t, "b", all.Rows[0].ID)
+
+	all, err = plain.WithSoftDelete(&SoftDeleteOptions{IncludeDeleted: true}).AllDocs(ctx, &ViewOptions{IncludeDocs: true})
+	require.NoError(t, err)
+	assert.Len(t, all.Rows, 2)
+
+	restored, err := db.Restore(ctx, "a")
+	require.NoError(t, err)
+	assert.Regexp(t, `^3-`, restored.Rev)
+	doc, err = db.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.NotContains(t, doc.Data, "deleted")
+	assert.NotContains(t, doc.Data, "deleted_at")
+
+	order := &taggedOrder{Key: "c"}
+	_, err = db.Put(ctx, order)
+	require.NoError(t, err)
+	require.NoError(t, db.DeleteDoc(ctx, order))
+	assert.Regexp(t, `^2-`, order.Version)
+
+	require.NoError(t, plain.Delete(ctx, "a", restored.Rev))
+	_, err = db.Get(ctx, "a")
+	assert.True(t, IsNotFound(err))
+}
+
+func TestSoftDelete_Find(t *testing.T) {
+	var selectors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query struct {
+			Selector json.RawMessage `json:"selector"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		selectors = append(selectors, string(query.Selector))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"docs":[]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db").WithSoftDelete(&SoftDeleteOptions{Field: "trashed"})
+	ctx := context.Background()
+
+	query := &FindQuery{Selector: Eq("type", "post")}
+	_, err := db.Find(ctx, query)
+	require.NoError(t, err)
+	_, err = db.Find(ctx, &FindQuery{})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"$and":[{"type":{"$eq":"post"}},{"$not":{"trashed":{"$eq":true}}}]}`, selectors[0])
+	assert.JSONEq(t, `{"$not":{"trashed":{"$eq":true}}}`, selectors[1])
+	assert.Equal(t, Eq("type", "post"), query.Selector, "the caller's query is not modified")
+}
@@ -0,0 +1,144 @@
+package couchdb
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// EventType classifies a document change
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a document change delivered by a Subscription
+type Event struct {
+	Type EventType
+	ID   string
+	Rev  string
+	Seq  Sequence
+	Doc  *Document // set when IncludeDocs is enabled
+}
+
+// SubscribeOptions configures a Subscription
+type SubscribeOptions struct {
+	// Since is the sequence to start from. Defaults to "now", so only
+	// changes made after subscribing are delivered; use "0" to replay all.
+	Since string
+
+	Types       []EventType // event types to deliver, all when empty
+	IncludeDocs bool
+
+	// Server-side filters, see ChangesOptions
+	Selector interface{}
+	DocIDs   []string
+
+	// Match filters events on the client after Types
+	Match func(Event) bool
+
+	// BufferSize is the capacity of the event channel, defaults to 64. When
+	// the buffer is full the subscription stops reading from the feed until
+	// the consumer catches up.
+	BufferSize int
+}
+
+// Subscription delivers a database's document changes as events
+type Subscription struct {
+	feed   *ChangesFeed
+	events chan Event
+	cancel context.CancelFunc
+}
+
+// Subscribe streams the database's document changes as typed events. Read
+// from Events() until it is closed, then check Err(). Cancel ctx or call
+// Close to stop.
+//
+// An event is classified as created when its revision is the first one.
+// Updates that arrive after a document was recreated, or several changes
+// to the same document collapsed into one row, are reported as updated.
+func (db *Database) Subscribe(ctx context.Context, opts SubscribeOptions) *Subscription {
+	if opts.Since == "" {
+		opts.Since = "now"
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 64
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	feed := db.ChangesFeed(ctx, &ChangesFeedOptions{
+		ChangesOptions: ChangesOptions{
+			Since:       opts.Since,
+			IncludeDocs: opts.IncludeDocs,
+			Selector:    opts.Selector,
+			DocIDs:      opts.DocIDs,
+		},
+	})
+
+	s := &Subscription{feed: feed, events: make(chan Event, opts.BufferSize), cancel: cancel}
+	go s.run(ctx, opts)
+
+	return s
+}
+
+// Events returns the channel on which events are delivered
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err returns the error that stopped the subscription, or nil if it was cancelled
+func (s *Subscription) Err() error {
+	return s.feed.Err()
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.cancel()
+}
+
+func (s *Subscription) run(ctx context.Context, opts SubscribeOptions) {
+	defer close(s.events)
+	defer s.cancel()
+
+	for change := range s.feed.Changes() {
+		event := newEvent(change)
+		if !matchesEvent(event, opts) {
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newEvent(change Change) Event {
+	event := Event{ID: change.ID, Seq: change.Seq, Doc: change.Doc}
+	if len(change.Changes) > 0 {
+		event.Rev = change.Changes[0].Rev
+	}
+
+	switch {
+	case change.Deleted:
+		event.Type = EventDeleted
+	case strings.HasPrefix(event.Rev, "1-"):
+		event.Type = EventCreated
+	default:
+		event.Type = EventUpdated
+	}
+
+	return event
+}
+
+func matchesEvent(event Event, opts SubscribeOptions) bool {
+	if len(opts.Types) > 0 && !slices.Contains(opts.Types, event.Type) {
+		return false
+	}
+
+	return opts.Match == nil || opts.Match(event)
+}
@@ -0,0 +1,89 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe_ReconnectKeepsChangesMadeWhileDisconnected(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db" {
+			_, _ = w.Write([]byte(`{"db_name":"db","update_seq":"5-e"}`))
+			return
+		}
+
+		// Both connections start from the sequence current at subscribe
+		// time, not from "now", which would skip "c" written in between
+		assert.Equal(t, "5-e", r.URL.Query().Get("since"))
+		if connections.Add(1) == 1 {
+			return
+		}
+		fmt.Fprintln(w, `{"seq":"6-f","id":"c","changes":[{"rev":"1-x"}]}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	sub := db.Subscribe(context.Background(), SubscribeOptions{})
+	defer sub.Close()
+
+	event, ok := <-sub.Events()
+	require.True(t, ok, sub.Err())
+	assert.Equal(t, "c", event.ID)
+	assert.Equal(t, EventCreated, event.Type)
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+func TestSubscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db" {
+			_, _ = w.Write([]byte(`{"db_name":"db","update_seq":"0-z"}`))
+			return
+		}
+		assert.Equal(t, "0-z", r.URL.Query().Get("since"))
+		fmt.Fprintln(w, `{"seq":"1-a","id":"a","changes":[{"rev":"1-x"}]}`)
+		fmt.Fprintln(w, `{"seq":"2-b","id":"a","changes":[{"rev":"2-y"}]}`)
+		fmt.Fprintln(w, `{"seq":"3-c","id":"b","changes":[{"rev":"3-z"}],"deleted":true}`)
+		fmt.Fprintln(w, `{"seq":"4-d","id":"_design/app","changes":[{"rev":"1-w"}]}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	sub := db.Subscribe(context.Background(), SubscribeOptions{
+		Match: func(e Event) bool { return !strings.HasPrefix(e.ID, "_design/") },
+	})
+
+	var events []Event
+	for event := range sub.Events() {
+		events = append(events, event)
+		if len(events) == 3 {
+			sub.Close()
+		}
+	}
+
+	require.NoError(t, sub.Err())
+	require.Len(t, events, 3)
+	assert.Equal(t, EventCreated, events[0].Type)
+	assert.Equal(t, EventUpdated, events[1].Type)
+	assert.Equal(t, "2-y", events[1].Rev)
+	assert.Equal(t, EventDeleted, events[2].Type)
+
+	sub = db.Subscribe(context.Background(), SubscribeOptions{Types: []EventType{EventDeleted}})
+	event := <-sub.Events()
+	sub.Close()
+	assert.Equal(t, "b", event.ID)
+}
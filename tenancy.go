@@ -0,0 +1,177 @@
+package couchdb
+
+import (
+	"container/list"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TenancyOptions configures a Tenancy
+type TenancyOptions struct {
+	// Prefix of the database names, defaults to "userdb-" as used by
+	// couch_peruser
+	Prefix string
+
+	// DBOptions are used when a tenant's database is created. Its Security
+	// is ignored in favour of the Security function.
+	DBOptions *DBCreateOptions
+
+	// Security returns the security object of a tenant's database. The
+	// default makes the tenant its only member, as couch_peruser does.
+	Security func(tenant string) *SecurityObject
+
+	// DesignDocs are deployed to every tenant's database, keyed by name
+	DesignDocs map[string]*DesignDocument
+
+	// CacheSize is the number of tenants remembered as set up, defaults
+	// to 1000
+	CacheSize int
+}
+
+// Tenancy manages one database per tenant. Databases are named like those
+// of couch_peruser: the prefix followed by the hex encoded tenant ID, so a
+// tenant database can be looked up for a CouchDB user and vice versa.
+//
+// DB sets up a tenant's database on first use: it creates the database and
+// brings its security object and design documents up to date. Tenants set
+// up recently are kept in an LRU cache and returned without requests.
+type Tenancy struct {
+	client *Client
+	opts   TenancyOptions
+
+	mu      sync.Mutex
+	lru     *list.List // tenant IDs, most recently used first
+	tenants map[string]*list.Element
+}
+
+// NewTenancy creates a Tenancy for the databases of c
+func (c *Client) NewTenancy(opts *TenancyOptions) *Tenancy {
+	t := &Tenancy{
+		client:  c,
+		lru:     list.New(),
+		tenants: make(map[string]*list.Element),
+	}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.Prefix == "" {
+		t.opts.Prefix = "userdb-"
+	}
+	if t.opts.Security == nil {
+		t.opts.Security = func(tenant string) *SecurityObject {
+			return &SecurityObject{Members: SecurityMembers{Names: []string{tenant}}}
+		}
+	}
+	if t.opts.CacheSize <= 0 {
+		t.opts.CacheSize = 1000
+	}
+
+	return t
+}
+
+// DBName returns the name of the tenant's database
+func (t *Tenancy) DBName(tenant string) string {
+	return t.opts.Prefix + hex.EncodeToString([]byte(tenant))
+}
+
+// Tenant returns the tenant ID of a database name, and false if the name
+// does not belong to a tenant database
+func (t *Tenancy) Tenant(dbName string) (string, bool) {
+	encoded, ok := strings.CutPrefix(dbName, t.opts.Prefix)
+	if !ok {
+		return "", false
+	}
+	tenant, err := hex.DecodeString(encoded)
+	if err != nil || len(tenant) == 0 {
+		return "", false
+	}
+	return string(tenant), true
+}
+
+// DB returns a handle on the tenant's database, setting it up first unless
+// it has been set up recently
+func (t *Tenancy) DB(ctx context.Context, tenant string) (*Database, error) {
+	if tenant == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+
+	db := t.client.DB(t.DBName(tenant))
+	if t.cached(tenant) {
+		return db, nil
+	}
+
+	if err := t.setup(ctx, db, tenant); err != nil {
+		return nil, err
+	}
+
+	t.remember(tenant)
+	return db, nil
+}
+
+// Forget drops a tenant from the cache, so that the next DB call sets its
+// database up again, e.g. after it has been deleted
+func (t *Tenancy) Forget(tenant string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.tenants[tenant]; ok {
+		t.lru.Remove(elem)
+		delete(t.tenants, tenant)
+	}
+}
+
+// setup ensures the tenant's database, security object and design documents
+func (t *Tenancy) setup(ctx context.Context, db *Database, tenant string) error {
+	var opts DBCreateOptions
+	if t.opts.DBOptions != nil {
+		opts = *t.opts.DBOptions
+	}
+	opts.Security = t.opts.Security(tenant)
+
+	if _, err := t.client.EnsureDB(ctx, db.name, &opts); err != nil {
+		return err
+	}
+
+	for name, doc := range t.opts.DesignDocs {
+		// syncDesignDoc sets the revision, so it gets a copy
+		desired := *doc
+		if _, err := db.syncDesignDoc(ctx, name, &desired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cached reports whether the tenant is in the cache, marking it as used
+func (t *Tenancy) cached(tenant string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elem, ok := t.tenants[tenant]
+	if ok {
+		t.lru.MoveToFront(elem)
+	}
+	return ok
+}
+
+// remember adds the tenant to the cache, evicting the least recently used
+func (t *Tenancy) remember(tenant string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.tenants[tenant]; ok {
+		t.lru.MoveToFront(elem)
+		return
+	}
+
+	t.tenants[tenant] = t.lru.PushFront(tenant)
+	for t.lru.Len() > t.opts.CacheSize {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.tenants, oldest.Value.(string))
+	}
+}
@@ -0,0 +1,83 @@
+package couchdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenancyNames(t *testing.T) {
+	tenancy := NewClient("http://localhost:5984", nil).NewTenancy(nil)
+
+	// Same naming as couch_peruser
+	assert.Equal(t, "userdb-616c696365", tenancy.DBName("alice"))
+	assert.Equal(t, "userdb-616c6963654065782e636f6d", tenancy.DBName("alice@ex.com"))
+
+	tenant, ok := tenancy.Tenant("userdb-616c6963654065782e636f6d")
+	assert.True(t, ok)
+	assert.Equal(t, "alice@ex.com", tenant)
+
+	for _, name := range []string{"users", "userdb-", "userdb-zz", "tenant-616c696365"} {
+		_, ok := tenancy.Tenant(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestTenancyDB(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	ctx := context.Background()
+
+	tenancy := client.NewTenancy(&TenancyOptions{
+		Prefix: "tenant-",
+		DesignDocs: map[string]*DesignDocument{
+			"notes": {Views: map[string]*View{"by_date": {Map: "function (doc) { emit(doc.date, null); }"}}},
+		},
+		CacheSize: 2,
+	})
+
+	db, err := tenancy.DB(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-616c696365", db.Name())
+
+	security, err := db.GetSecurity(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, security.Members.Names)
+
+	ddoc, err := db.GetDesignDoc(ctx, "notes")
+	require.NoError(t, err)
+	assert.Contains(t, ddoc.Views, "by_date")
+
+	// A cached tenant is returned without checking its database
+	require.NoError(t, client.DeleteDB(ctx, db.Name()))
+	_, err = tenancy.DB(ctx, "alice")
+	require.NoError(t, err)
+	_, err = db.Info(ctx)
+	assert.True(t, IsNotFound(err))
+
+	// Until it is forgotten
+	tenancy.Forget("alice")
+	_, err = tenancy.DB(ctx, "alice")
+	require.NoError(t, err)
+	_, err = db.Info(ctx)
+	require.NoError(t, err)
+
+	// Or evicted by more recently used tenants
+	_, err = tenancy.DB(ctx, "bob")
+	require.NoError(t, err)
+	_, err = tenancy.DB(ctx, "carol")
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteDB(ctx, db.Name()))
+	_, err = tenancy.DB(ctx, "alice")
+	require.NoError(t, err)
+	_, err = db.Info(ctx)
+	require.NoError(t, err)
+
+	_, err = tenancy.DB(ctx, "")
+	assert.Error(t, err)
+}
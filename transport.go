@@ -0,0 +1,119 @@
+package couchdb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportOptions tunes the HTTP transport shared by all requests of a
+// client. Zero values keep the defaults of http.DefaultTransport.
+type TransportOptions struct {
+	// Connection pool
+	MaxIdleConns        int           // idle connections across all hosts
+	MaxIdleConnsPerHost int           // idle connections kept per host, net/http defaults to 2
+	MaxConnsPerHost     int           // total connections per host, zero means no limit
+	IdleConnTimeout     time.Duration // how long an idle connection stays in the pool
+
+	// Dialing and keep-alive
+	DialTimeout       time.Duration // defaults to 30s
+	KeepAlive         time.Duration // TCP keep-alive probe interval, defaults to 30s; negative disables probes
+	DisableKeepAlives bool          // open a new connection for every request
+
+	// TLSConfig sets client certificates and trusted CAs, see LoadTLSConfig
+	TLSConfig           *tls.Config
+	TLSHandshakeTimeout time.Duration
+
+	// ProxyURL routes requests through a proxy such as "http://proxy:3128".
+	// When empty the HTTP_PROXY and HTTPS_PROXY environment variables apply.
+	ProxyURL string
+
+	// DisableHTTP2 forces HTTP/1.1 on TLS connections
+	DisableHTTP2 bool
+}
+
+// newTransport builds an http.Transport from the options
+func (o *TransportOptions) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if o.DialTimeout > 0 {
+		dialer.Timeout = o.DialTimeout
+	}
+	if o.KeepAlive != 0 {
+		dialer.KeepAlive = o.KeepAlive
+	}
+	transport.DialContext = dialer.DialContext
+
+	if o.MaxIdleConns > 0 {
+		transport.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = o.IdleConnTimeout
+	}
+	transport.MaxConnsPerHost = o.MaxConnsPerHost
+	transport.DisableKeepAlives = o.DisableKeepAlives
+
+	if o.TLSConfig != nil {
+		transport.TLSClientConfig = o.TLSConfig.Clone()
+	}
+	if o.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = o.TLSHandshakeTimeout
+	}
+
+	if o.ProxyURL != "" {
+		proxy, err := url.Parse(o.ProxyURL)
+		// An invalid proxy fails every request rather than silently going direct
+		transport.Proxy = func(*http.Request) (*url.URL, error) { return proxy, err }
+	}
+
+	// A custom dialer or TLS config turns off HTTP/2 unless it is requested explicitly
+	transport.ForceAttemptHTTP2 = !o.DisableHTTP2
+	if o.DisableHTTP2 {
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return transport
+}
+
+// LoadTLSConfig builds a TLS configuration from PEM files for use as
+// TransportOptions.TLSConfig. certFile and keyFile hold a client
+// certificate for mutual TLS; caFile holds the certificates of a private
+// CA, trusted in addition to the system roots. Empty paths are skipped.
+func LoadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("load CA certificates: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("load CA certificates: no certificates found in %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
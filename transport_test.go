@@ -0,0 +1,126 @@
+package couchdb
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportOptions(t *testing.T) {
+	opts := &TransportOptions{
+		MaxIdleConnsPerHost: 64,
+		MaxConnsPerHost:     128,
+		IdleConnTimeout:     time.Minute,
+		TLSConfig:           &tls.Config{ServerName: "couch.internal"},
+		DisableHTTP2:        true,
+	}
+
+	transport := opts.newTransport()
+	assert.Equal(t, 64, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 128, transport.MaxConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.Equal(t, "couch.internal", transport.TLSClientConfig.ServerName)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.NotNil(t, transport.TLSNextProto)
+
+	assert.True(t, (&TransportOptions{}).newTransport().ForceAttemptHTTP2)
+}
+
+func TestTransportProxy(t *testing.T) {
+	// A proxy receives requests with the absolute target URL
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "http://couch.invalid:5984/", r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"couchdb":"Welcome","version":"3.4.2"}`))
+	}))
+	defer proxy.Close()
+
+	client := NewClient("http://couch.invalid:5984", &ClientOptions{
+		Transport: &TransportOptions{ProxyURL: proxy.URL},
+	})
+
+	info, err := client.Info(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.2", info.Version)
+
+	client = NewClient("http://couch.invalid:5984", &ClientOptions{
+		Transport: &TransportOptions{ProxyURL: "://bad"},
+	})
+	_, err = client.Info(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLoadTLSConfigMutualTLS(t *testing.T) {
+	// One self-signed certificate serves as CA, server and client certificate
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "couchdb-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	serverCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(parsed)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"couchdb":"Welcome","version":"3.4.2"}`))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	config, err := LoadTLSConfig(certFile, keyFile, certFile)
+	require.NoError(t, err)
+
+	client := NewClient(server.URL, &ClientOptions{Transport: &TransportOptions{TLSConfig: config}})
+	info, err := client.Info(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.2", info.Version)
+
+	_, err = LoadTLSConfig("", "", filepath.Join(dir, "missing.pem"))
+	assert.ErrorContains(t, err, "load CA certificates")
+}
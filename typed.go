@@ -0,0 +1,128 @@
+package couchdb
+
+import (
+	"context"
+	"strings"
+)
+
+// DocumentMeta holds the ID, revision and attachment stubs of a document.
+// Embed it in application structs so they round-trip these fields; keeping
+// the stubs means that updating a document does not drop its attachments.
+type DocumentMeta struct {
+	ID          string                 `json:"_id,omitempty"`
+	Rev         string                 `json:"_rev,omitempty"`
+	Attachments map[string]*Attachment `json:"_attachments,omitempty"`
+}
+
+// GetAs retrieves a document by ID and decodes it into a new T
+func GetAs[T any](ctx context.Context, db *Database, id string, rev ...string) (*T, error) {
+	var doc T
+	if err := db.GetInto(ctx, id, &doc, rev...); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// TypedDB wraps a Database to read and write documents as T. Map the
+// document ID and revision with "_id" and "_rev" JSON tags or by embedding
+// DocumentMeta.
+type TypedDB[T any] struct {
+	db *Database
+}
+
+// Typed returns a TypedDB for documents of type T
+func Typed[T any](db *Database) *TypedDB[T] {
+	return &TypedDB[T]{db: db}
+}
+
+// DB returns the underlying database
+func (t *TypedDB[T]) DB() *Database {
+	return t.db
+}
+
+// Get retrieves a document by ID
+func (t *TypedDB[T]) Get(ctx context.Context, id string, rev ...string) (*T, error) {
+	return GetAs[T](ctx, t.db, id, rev...)
+}
+
+// Put creates or updates a document and stores the new ID and revision back into doc
+func (t *TypedDB[T]) Put(ctx context.Context, doc *T) (*Document, error) {
+	result, err := t.db.Put(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the fields tagged _id and _rev are touched
+	if err := convertDoc(DocumentMeta{ID: result.ID, Rev: result.Rev}, doc); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Find runs a Mango query and decodes the matching documents
+func (t *TypedDB[T]) Find(ctx context.Context, query *FindQuery) ([]T, error) {
+	result, err := t.db.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDocs[T](result.Docs)
+}
+
+// AllDocs returns the documents in the database, skipping design documents.
+// Documents are always included regardless of opts.IncludeDocs.
+func (t *TypedDB[T]) AllDocs(ctx context.Context, opts *ViewOptions) ([]T, error) {
+	result, err := t.db.AllDocs(ctx, withDocs(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRows[T](result.Rows)
+}
+
+// View queries a view and decodes the document of each row. Documents are
+// always included regardless of opts.IncludeDocs.
+func (t *TypedDB[T]) View(ctx context.Context, designDoc, viewName string, opts *ViewOptions) ([]T, error) {
+	result, err := t.db.View(ctx, designDoc, viewName, withDocs(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRows[T](result.Rows)
+}
+
+// withDocs returns a copy of opts with include_docs set
+func withDocs(opts *ViewOptions) *ViewOptions {
+	var o ViewOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.IncludeDocs = true
+	return &o
+}
+
+func decodeRows[T any](rows []ViewRow) ([]T, error) {
+	docs := make([]*Document, 0, len(rows))
+	for _, row := range rows {
+		if row.Doc == nil || strings.HasPrefix(row.ID, "_design/") {
+			continue
+		}
+		docs = append(docs, row.Doc)
+	}
+
+	return decodeDocs[T](docs)
+}
+
+func decodeDocs[T any](docs []*Document) ([]T, error) {
+	values := make([]T, 0, len(docs))
+	for _, doc := range docs {
+		var value T
+		if err := doc.Decode(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,125 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPerson struct {
+	DocumentMeta
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestGetAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/db/alice" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not_found","reason":"missing"}`))
+			return
+		}
+		assert.Equal(t, "2-b", r.URL.Query().Get("rev"))
+		_, _ = w.Write([]byte(`{"_id":"alice","_rev":"2-b","name":"Alice","age":30}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	person, err := GetAs[testPerson](ctx, db, "alice", "2-b")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", person.ID)
+	assert.Equal(t, "2-b", person.Rev)
+	assert.Equal(t, "Alice", person.Name)
+	assert.Equal(t, 30, person.Age)
+
+	var missing testPerson
+	err = db.GetInto(ctx, "bob", &missing)
+	assert.True(t, isStatus(err, http.StatusNotFound))
+}
+
+func TestTypedDB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/db":
+			_, _ = w.Write([]byte(`{"ok":true,"id":"bob","rev":"1-a"}`))
+		case r.URL.Path == "/db/_all_docs":
+			assert.Equal(t, "true", r.URL.Query().Get("include_docs"))
+			_, _ = w.Write([]byte(`{"total_rows":2,"offset":0,"rows":[
+				{"id":"_design/app","key":"_design/app","value":{},"doc":{"_id":"_design/app","views":{}}},
+				{"id":"bob","key":"bob","value":{},"doc":{"_id":"bob","_rev":"1-a","name":"Bob","age":40}}
+			]}`))
+		case r.URL.Path == "/db/_find":
+			_, _ = w.Write([]byte(`{"docs":[{"_id":"bob","_rev":"1-a","name":"Bob","age":40}]}`))
+		}
+	}))
+	defer server.Close()
+
+	people := Typed[testPerson](NewClient(server.URL, nil).DB("db"))
+	ctx := context.Background()
+
+	bob := &testPerson{Name: "Bob", Age: 40}
+	_, err := people.Put(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, DocumentMeta{ID: "bob", Rev: "1-a"}, bob.DocumentMeta)
+	assert.Equal(t, "Bob", bob.Name)
+
+	all, err := people.AllDocs(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []testPerson{*bob}, all)
+
+	found, err := people.Find(ctx, &FindQuery{Selector: Selector{"age": 40}})
+	require.NoError(t, err)
+	assert.Equal(t, []testPerson{*bob}, found)
+}
+
+func TestAttachmentStubsRoundTrip(t *testing.T) {
+	const stored = `{"_id":"bob","_rev":"1-a","name":"Bob","age":40,
+		"_attachments":{"avatar.png":{"content_type":"image/png","digest":"md5-abc","length":512,"revpos":1,"stub":true}}}`
+
+	var written map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			_, _ = w.Write([]byte(stored))
+		default:
+			written = nil
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ok":true,"id":"bob","rev":"2-b"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	people := Typed[testPerson](db)
+	bob, err := people.Get(ctx, "bob")
+	require.NoError(t, err)
+	require.Contains(t, bob.Attachments, "avatar.png")
+	assert.Equal(t, int64(512), bob.Attachments["avatar.png"].Length)
+	assert.True(t, bob.Attachments["avatar.png"].Stub)
+
+	bob.Age++
+	_, err = people.Put(ctx, bob)
+	require.NoError(t, err)
+	assert.Equal(t, "2-b", bob.Rev)
+	assert.Contains(t, written["_attachments"], "avatar.png", "stubs must be sent back")
+
+	// Upsert keeps stubs even when the update is built from scratch
+	_, err = db.Upsert(ctx, "bob", func(*Document) (interface{}, error) {
+		return map[string]interface{}{"name": "Robert"}, nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, written["_attachments"], "avatar.png")
+}
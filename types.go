@@ -3,13 +3,19 @@ package couchdb
 import (
 	"encoding/json"
 	"github.com/go-resty/resty/v2"
+	"sync"
 	"time"
 )
 
 // Database represents a CouchDB database
 type Database struct {
-	client *Client
-	name   string
+	client      *Client
+	name        string
+	designDoc   string             // default design document for Q, see WithDesignDoc
+	validator   DocValidator       // checks documents before writes, see WithValidator
+	writeHooks  []WriteHook        // modify documents before writes, see WithWriteHook
+	softDelete  *SoftDeleteOptions // flag instead of delete, see WithSoftDelete
+	idGenerator IDGenerator        // IDs of new documents, see WithIDGenerator
 }
 
 // DB returns a Database instance for the specified database name
@@ -20,12 +26,54 @@ func (c *Client) DB(name string) *Database {
 	}
 }
 
+// Name returns the name of the database
+func (db *Database) Name() string {
+	return db.name
+}
+
+// WithDesignDoc returns a handle on the same database whose Q and
+// EnsureFieldView use designDoc (without the _design/ prefix)
+func (db *Database) WithDesignDoc(designDoc string) *Database {
+	handle := *db
+	handle.designDoc = designDoc
+	return &handle
+}
+
+// WithValidator returns a handle on the same database whose Put, Update and
+// Bulk check documents with v before sending them, failing with a
+// *ValidationError instead of a round trip to a validate_doc_update
+// function. Rules needing the stored document or the user are only checked
+// by CouchDB.
+func (db *Database) WithValidator(v DocValidator) *Database {
+	handle := *db
+	handle.validator = v
+	return &handle
+}
+
 // Document represents a CouchDB document
 type Document struct {
-	ID      string                 `json:"_id,omitempty"`
-	Rev     string                 `json:"_rev,omitempty"`
-	Deleted bool                   `json:"_deleted,omitempty"`
-	Data    map[string]interface{} `json:"-"`
+	ID               string                 `json:"_id,omitempty"`
+	Rev              string                 `json:"_rev,omitempty"`
+	Deleted          bool                   `json:"_deleted,omitempty"`
+	Attachments      map[string]*Attachment `json:"_attachments,omitempty"`
+	Conflicts        []string               `json:"_conflicts,omitempty"`
+	DeletedConflicts []string               `json:"_deleted_conflicts,omitempty"`
+	Revisions        *Revisions             `json:"_revisions,omitempty"`
+	RevsInfo         []RevisionInfo         `json:"_revs_info,omitempty"`
+	Data             map[string]interface{} `json:"-"`
+}
+
+// Attachment represents a document attachment, either inline or as a stub
+type Attachment struct {
+	ContentType   string `json:"content_type,omitempty"`
+	Data          []byte `json:"data,omitempty"` // base64 encoded on the wire
+	Digest        string `json:"digest,omitempty"`
+	Length        int64  `json:"length,omitempty"`
+	RevPos        int    `json:"revpos,omitempty"`
+	Stub          bool   `json:"stub,omitempty"`
+	Follows       bool   `json:"follows,omitempty"`
+	Encoding      string `json:"encoding,omitempty"`
+	EncodedLength int64  `json:"encoded_length,omitempty"`
 }
 
 // MarshalJSON implements json.Marshaler
@@ -47,6 +95,12 @@ func (d *Document) MarshalJSON() ([]byte, error) {
 	if d.Deleted {
 		doc["_deleted"] = d.Deleted
 	}
+	if len(d.Attachments) > 0 {
+		doc["_attachments"] = d.Attachments
+	}
+	if d.Revisions != nil {
+		doc["_revisions"] = d.Revisions
+	}
 
 	return json.Marshal(doc)
 }
@@ -58,6 +112,22 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	var meta struct {
+		Attachments      map[string]*Attachment `json:"_attachments"`
+		Conflicts        []string               `json:"_conflicts"`
+		DeletedConflicts []string               `json:"_deleted_conflicts"`
+		Revisions        *Revisions             `json:"_revisions"`
+		RevsInfo         []RevisionInfo         `json:"_revs_info"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return err
+	}
+
+	d.Attachments = meta.Attachments
+	d.Conflicts = meta.Conflicts
+	d.DeletedConflicts = meta.DeletedConflicts
+	d.Revisions = meta.Revisions
+	d.RevsInfo = meta.RevsInfo
 	d.Data = make(map[string]interface{})
 
 	for k, v := range doc {
@@ -74,6 +144,8 @@ func (d *Document) UnmarshalJSON(data []byte) error {
 			if deleted, ok := v.(bool); ok {
 				d.Deleted = deleted
 			}
+		case "_attachments", "_conflicts", "_deleted_conflicts", "_revisions", "_revs_info":
+			// Decoded into typed fields above
 		default:
 			d.Data[k] = v
 		}
@@ -93,6 +165,55 @@ type DesignDocument struct {
 	Updates  map[string]string `json:"updates,omitempty"`
 	Filters  map[string]string `json:"filters,omitempty"`
 	Validate string            `json:"validate_doc_update,omitempty"`
+	Rewrites *Rewrites         `json:"rewrites,omitempty"`
+
+	// Full-text search indexes
+	Indexes map[string]*SearchIndex `json:"indexes,omitempty"` // Clouseau
+	Nouveau map[string]*SearchIndex `json:"nouveau,omitempty"`
+}
+
+// SearchIndex defines a full-text search index in a design document
+type SearchIndex struct {
+	Index           string            `json:"index"`
+	Analyzer        interface{}       `json:"analyzer,omitempty"`         // Clouseau: a name or a per-field definition
+	DefaultAnalyzer string            `json:"default_analyzer,omitempty"` // Nouveau
+	FieldAnalyzers  map[string]string `json:"field_analyzers,omitempty"`  // Nouveau
+}
+
+// Rewrites holds the _rewrite routes of a design document, either as a
+// list of rules or, since CouchDB 2.0, as a JavaScript function. Only one
+// of the two is set.
+type Rewrites struct {
+	Rules    []RewriteRule
+	Function string
+}
+
+// RewriteRule maps a path below _rewrite to a path relative to the design document
+type RewriteRule struct {
+	From   string                 `json:"from"`
+	To     string                 `json:"to"`
+	Method string                 `json:"method,omitempty"` // matches any method when empty
+	Query  map[string]interface{} `json:"query,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (r Rewrites) MarshalJSON() ([]byte, error) {
+	if r.Function != "" {
+		return json.Marshal(r.Function)
+	}
+	if r.Rules == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r.Rules)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Rewrites) UnmarshalJSON(data []byte) error {
+	*r = Rewrites{}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &r.Function)
+	}
+	return json.Unmarshal(data, &r.Rules)
 }
 
 // View represents a CouchDB view with map and reduce functions
@@ -106,7 +227,7 @@ type ViewResult struct {
 	TotalRows int64     `json:"total_rows"`
 	Offset    int64     `json:"offset"`
 	Rows      []ViewRow `json:"rows"`
-	UpdateSeq string    `json:"update_seq,omitempty"`
+	UpdateSeq Sequence  `json:"update_seq,omitempty"` // set when ViewOptions.UpdateSeq is
 }
 
 // ViewRow represents a single row in a view result
@@ -115,6 +236,7 @@ type ViewRow struct {
 	Key   interface{} `json:"key"`
 	Value interface{} `json:"value"`
 	Doc   *Document   `json:"doc,omitempty"`
+	Error string      `json:"error,omitempty"` // set for keys that were not found, e.g. "not_found"
 }
 
 // ViewOptions holds options for view queries
@@ -128,10 +250,11 @@ type ViewOptions struct {
 	EndKeyDocID   string        `json:"endkey_docid,omitempty"`
 
 	// Result control
-	Limit        int  `json:"limit,omitempty"`
-	Skip         int  `json:"skip,omitempty"`
-	Descending   bool `json:"descending,omitempty"`
-	InclusiveEnd bool `json:"inclusive_end,omitempty"`
+	Limit        int   `json:"limit,omitempty"`
+	Skip         int   `json:"skip,omitempty"`
+	Descending   bool  `json:"descending,omitempty"`
+	InclusiveEnd bool  `json:"inclusive_end,omitempty"`
+	Sorted       *bool `json:"sorted,omitempty"`
 
 	// Group/Reduce
 	Group      bool  `json:"group,omitempty"`
@@ -148,6 +271,7 @@ type ViewOptions struct {
 	// Staleness
 	Stale  string `json:"stale,omitempty"`  // "ok" or "update_after"
 	Update string `json:"update,omitempty"` // "true", "false", or "lazy"
+	Stable bool   `json:"stable,omitempty"`
 }
 
 // ViewQuery represents a structured view query
@@ -159,6 +283,7 @@ type ViewQuery struct {
 
 // ViewBuilder helps build complex view queries
 type ViewBuilder struct {
+	db        *Database
 	designDoc string
 	viewName  string
 	options   *ViewOptions
@@ -167,7 +292,17 @@ type ViewBuilder struct {
 // Client represents a CouchDB client
 type Client struct {
 	resty   *resty.Client
+	stream  *resty.Client
 	baseURL string
+	logger  Logger
+
+	maxDocumentSize int64
+	maxRequestSize  int64
+	session         *sessionState
+
+	versionMu     sync.Mutex
+	serverVersion string
+	staleWarning  sync.Once
 }
 
 // ClientOptions holds configuration options for the CouchDB client
@@ -176,18 +311,73 @@ type ClientOptions struct {
 	Password string
 	Timeout  time.Duration
 	Debug    bool
+	Logger   Logger // defaults to a logger writing to stderr
+
+	// Size guards, checked on serialized bodies before sending. Zero disables the check.
+	MaxDocumentSize int64 // per document, mirrors [couchdb] max_document_size
+	MaxRequestSize  int64 // per request body, mirrors [chttpd] max_http_request_size
+
+	// SessionTimeout mirrors the server's [chttpd_auth] timeout and controls
+	// when cookie sessions from Login are renewed. Defaults to 10 minutes.
+	SessionTimeout time.Duration
+
+	// Retry configures automatic retries of transient failures. Nil disables retries.
+	Retry *RetryPolicy
+
+	// Metrics receives per-request metrics. Nil disables collection.
+	Metrics MetricsCollector
+
+	// Transport tunes connection pooling, dialing, TLS and proxying. Nil
+	// uses http.DefaultTransport.
+	Transport *TransportOptions
+
+	// DisableRequestID stops the client from sending an X-Request-ID with
+	// every request, see WithRequestID
+	DisableRequestID bool
+}
+
+// Logger receives diagnostic messages from the client and its HTTP transport
+type Logger interface {
+	Errorf(format string, v ...interface{})
+	Warnf(format string, v ...interface{})
+	Debugf(format string, v ...interface{})
 }
 
 type DatabaseInfo struct {
-	DBName            string `json:"db_name"`
-	DocCount          int64  `json:"doc_count"`
-	DocDelCount       int64  `json:"doc_del_count"`
-	UpdateSeq         string `json:"update_seq"`
-	PurgeSeq          string `json:"purge_seq"`
-	CompactRunning    bool   `json:"compact_running"`
-	DiskSize          int64  `json:"disk_size"`
-	DataSize          int64  `json:"data_size"`
-	InstanceStartTime string `json:"instance_start_time"`
+	DBName            string   `json:"db_name"`
+	DocCount          int64    `json:"doc_count"`
+	DocDelCount       int64    `json:"doc_del_count"`
+	UpdateSeq         Sequence `json:"update_seq"`
+	PurgeSeq          Sequence `json:"purge_seq"`
+	CompactRunning    bool     `json:"compact_running"`
+	DiskSize          int64    `json:"disk_size"`
+	DataSize          int64    `json:"data_size"`
+	InstanceStartTime string   `json:"instance_start_time"`
+
+	// CouchDB 2.0+
+	Sizes   DatabaseSizes  `json:"sizes"`
+	Cluster *ClusterParams `json:"cluster,omitempty"`
+	Props   DatabaseProps  `json:"props"`
+}
+
+// DatabaseSizes holds database sizes in bytes
+type DatabaseSizes struct {
+	File     int64 `json:"file"`     // size of the database files on disk
+	External int64 `json:"external"` // uncompressed size of the live data
+	Active   int64 `json:"active"`   // size of live data inside the files
+}
+
+// ClusterParams holds a database's sharding and quorum parameters
+type ClusterParams struct {
+	Q int `json:"q"` // number of shards
+	N int `json:"n"` // number of replicas of each shard
+	W int `json:"w"` // write quorum
+	R int `json:"r"` // read quorum
+}
+
+// DatabaseProps holds database properties set at creation
+type DatabaseProps struct {
+	Partitioned bool `json:"partitioned,omitempty"`
 }
 
 type BulkResult struct {
@@ -199,11 +389,18 @@ type BulkResult struct {
 
 type BulkDocs struct {
 	Docs     []interface{} `json:"docs"`
-	NewEdits bool          `json:"new_edits,omitempty"`
+	NewEdits *bool         `json:"new_edits,omitempty"`
 }
 
 type Error struct {
 	StatusCode int    `json:"-"`
 	Type       string `json:"error"`
 	Reason     string `json:"reason"`
+
+	// RequestID is the X-Couch-Request-ID of the response, which identifies
+	// the request in the CouchDB logs
+	RequestID string `json:"-"`
+
+	// ClientRequestID is the X-Request-ID sent with the request
+	ClientRequestID string `json:"-"`
 }
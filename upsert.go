@@ -0,0 +1,92 @@
+package couchdb
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// UpsertOptions controls conflict retries in UpsertWithOptions
+type UpsertOptions struct {
+	MaxRetries int           // retries after a conflict, defaults to 5
+	Backoff    time.Duration // initial delay between retries, doubled each time; defaults to 50ms
+}
+
+// UpsertFunc receives the current document, or nil if it does not exist,
+// and returns the document to write. Returning a nil document skips the
+// write. It may be called several times and must not have side effects.
+type UpsertFunc func(current *Document) (interface{}, error)
+
+// Upsert performs a read-modify-write of a document, retrying when another
+// writer updates it concurrently. The _id and _rev of the written document
+// are set from id and the current revision. Attachment stubs of the current
+// document are carried over unless the returned document sets _attachments.
+func (db *Database) Upsert(ctx context.Context, id string, mutate UpsertFunc) (*Document, error) {
+	return db.UpsertWithOptions(ctx, id, mutate, nil)
+}
+
+// UpsertWithOptions is Upsert with configurable conflict retries
+func (db *Database) UpsertWithOptions(ctx context.Context, id string, mutate UpsertFunc, opts *UpsertOptions) (*Document, error) {
+	maxRetries, backoff := 5, 50*time.Millisecond
+	if opts != nil {
+		if opts.MaxRetries > 0 {
+			maxRetries = opts.MaxRetries
+		}
+		if opts.Backoff > 0 {
+			backoff = opts.Backoff
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		result, err := db.upsertOnce(ctx, id, mutate)
+		if err == nil || !isStatus(err, http.StatusConflict) || attempt >= maxRetries {
+			return result, err
+		}
+
+		// Jitter spreads out writers that conflicted with each other
+		delay := backoff<<attempt + time.Duration(rand.Int63n(int64(backoff)))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (db *Database) upsertOnce(ctx context.Context, id string, mutate UpsertFunc) (*Document, error) {
+	current, err := db.Get(ctx, id)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		return nil, err
+	}
+
+	updated, err := mutate(current)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		if current == nil {
+			return nil, nil
+		}
+		return &Document{ID: current.ID, Rev: current.Rev}, nil
+	}
+
+	body, err := toMap(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	body["_id"] = id
+	delete(body, "_rev")
+	if current != nil {
+		body["_rev"] = current.Rev
+
+		// Keep existing attachments unless the update sets _attachments itself
+		if _, ok := body["_attachments"]; !ok && len(current.Attachments) > 0 {
+			body["_attachments"] = current.Attachments
+		}
+	}
+
+	return db.Update(ctx, id, body)
+}
@@ -0,0 +1,61 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsert(t *testing.T) {
+	stored := map[string]interface{}{"_id": "counter", "_rev": "1-a", "n": float64(1)}
+	conflicts := 2
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			_ = json.NewEncoder(w).Encode(stored)
+		case "PUT":
+			var doc map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+			assert.Equal(t, stored["_rev"], doc["_rev"])
+
+			if conflicts > 0 {
+				// Simulate a concurrent writer
+				conflicts--
+				stored["n"] = stored["n"].(float64) + 10
+				stored["_rev"] = stored["_rev"].(string) + "x"
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error":"conflict","reason":"Document update conflict."}`))
+				return
+			}
+
+			doc["_rev"] = "9-z"
+			stored = doc
+			_, _ = w.Write([]byte(`{"ok":true,"id":"counter","rev":"9-z"}`))
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	increment := func(current *Document) (interface{}, error) {
+		current.Data["n"] = current.Data["n"].(float64) + 1
+		return current, nil
+	}
+
+	doc, err := db.UpsertWithOptions(context.Background(), "counter", increment, &UpsertOptions{Backoff: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "9-z", doc.Rev)
+	assert.Equal(t, float64(22), stored["n"], "the mutation must be applied to the latest revision")
+
+	conflicts = 10
+	_, err = db.UpsertWithOptions(context.Background(), "counter", increment, &UpsertOptions{MaxRetries: 2, Backoff: time.Millisecond})
+	assert.True(t, isStatus(err, http.StatusConflict))
+	assert.Equal(t, 7, conflicts, "one attempt plus two retries")
+}
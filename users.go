@@ -0,0 +1,186 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// UsersDB is the authentication database holding user documents
+	UsersDB = "_users"
+
+	// UserIDPrefix is the prefix CouchDB requires for user document IDs
+	UserIDPrefix = "org.couchdb.user:"
+)
+
+// User represents a document in the _users database.
+//
+// Password is write-only: CouchDB replaces it with the derived key and salt
+// when the document is saved, so it is always empty on documents read back.
+type User struct {
+	ID    string   `json:"_id,omitempty"`
+	Rev   string   `json:"_rev,omitempty"`
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Roles []string `json:"roles"`
+
+	Password string `json:"password,omitempty"`
+
+	// Hashing fields maintained by the server (read-only)
+	PasswordScheme string `json:"password_scheme,omitempty"`
+	Iterations     int    `json:"iterations,omitempty"`
+	DerivedKey     string `json:"derived_key,omitempty"`
+	Salt           string `json:"salt,omitempty"`
+}
+
+// Users manages user documents in the _users database
+type Users struct {
+	db *Database
+}
+
+// Users returns a helper for managing users
+func (c *Client) Users() *Users {
+	return &Users{db: c.DB(UsersDB)}
+}
+
+// UserID returns the document ID of the named user
+func UserID(name string) string {
+	if strings.HasPrefix(name, UserIDPrefix) {
+		return name
+	}
+	return UserIDPrefix + name
+}
+
+// CreateUser creates a user. The password is sent in plain text and hashed by the server.
+func (u *Users) CreateUser(ctx context.Context, name, password string, roles ...string) (*User, error) {
+	name = strings.TrimPrefix(name, UserIDPrefix)
+	if name == "" || password == "" {
+		return nil, fmt.Errorf("user name and password are required")
+	}
+
+	if roles == nil {
+		roles = []string{}
+	}
+
+	user := &User{
+		ID:       UserID(name),
+		Name:     name,
+		Type:     "user",
+		Roles:    roles,
+		Password: password,
+	}
+
+	result, err := u.db.Update(ctx, user.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Rev = result.Rev
+	user.Password = ""
+	return user, nil
+}
+
+// GetUser retrieves a user by name
+func (u *Users) GetUser(ctx context.Context, name string) (*User, error) {
+	doc, err := u.db.Get(ctx, UserID(name))
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := convertDoc(doc, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UpdatePassword changes a user's password
+func (u *Users) UpdatePassword(ctx context.Context, name, password string) error {
+	if password == "" {
+		return fmt.Errorf("password is required")
+	}
+
+	return u.updateUser(ctx, name, func(user *User) bool {
+		user.Password = password
+		return true
+	})
+}
+
+// DeleteUser deletes a user
+func (u *Users) DeleteUser(ctx context.Context, name string) error {
+	doc, err := u.db.Get(ctx, UserID(name))
+	if err != nil {
+		return err
+	}
+
+	return u.db.Delete(ctx, doc.ID, doc.Rev)
+}
+
+// AddRoles grants roles to a user
+func (u *Users) AddRoles(ctx context.Context, name string, roles ...string) error {
+	return u.updateUser(ctx, name, func(user *User) bool {
+		changed := false
+		for _, role := range roles {
+			changed = addUnique(&user.Roles, role) || changed
+		}
+		return changed
+	})
+}
+
+// RemoveRoles revokes roles from a user
+func (u *Users) RemoveRoles(ctx context.Context, name string, roles ...string) error {
+	return u.updateUser(ctx, name, func(user *User) bool {
+		changed := false
+		for _, role := range roles {
+			changed = removeAll(&user.Roles, role) || changed
+		}
+		return changed
+	})
+}
+
+// SetRoles replaces a user's roles
+func (u *Users) SetRoles(ctx context.Context, name string, roles ...string) error {
+	return u.updateUser(ctx, name, func(user *User) bool {
+		if roles == nil {
+			roles = []string{}
+		}
+		user.Roles = roles
+		return true
+	})
+}
+
+// updateUser fetches the user document, applies modify and writes it back
+// only if it changed. Fields not modelled by User are preserved.
+func (u *Users) updateUser(ctx context.Context, name string, modify func(*User) bool) error {
+	doc, err := u.db.Get(ctx, UserID(name))
+	if err != nil {
+		return err
+	}
+
+	var user User
+	if err := convertDoc(doc, &user); err != nil {
+		return err
+	}
+	if user.Roles == nil {
+		user.Roles = []string{}
+	}
+
+	if !modify(&user) {
+		return nil
+	}
+
+	fields, err := toMap(&user)
+	if err != nil {
+		return err
+	}
+	delete(fields, "_id")
+	delete(fields, "_rev")
+	for k, v := range fields {
+		doc.Data[k] = v
+	}
+
+	_, err = u.db.Update(ctx, doc.ID, doc)
+	return err
+}
@@ -0,0 +1,68 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsers(t *testing.T) {
+	stored := map[string]interface{}{}
+	writes := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/_users/org.couchdb.user:alice", r.URL.Path)
+
+		switch r.Method {
+		case "GET":
+			_ = json.NewEncoder(w).Encode(stored)
+		case "PUT":
+			writes++
+			stored = map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&stored)
+			if _, ok := stored["password"]; ok {
+				// Emulate the server replacing the password with its hash
+				delete(stored, "password")
+				stored["derived_key"] = "abc"
+			}
+			stored["_rev"] = "1-x"
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "id": stored["_id"], "rev": "1-x"})
+		}
+	}))
+	defer server.Close()
+
+	users := NewClient(server.URL, nil).Users()
+	ctx := context.Background()
+
+	user, err := users.CreateUser(ctx, "alice", "secret", "reader")
+	require.NoError(t, err)
+	assert.Equal(t, "org.couchdb.user:alice", user.ID)
+	assert.Equal(t, "user", stored["type"])
+	assert.Empty(t, user.Password)
+
+	stored["email"] = "alice@example.com"
+
+	require.NoError(t, users.AddRoles(ctx, "alice", "reader", "writer"))
+	require.NoError(t, users.AddRoles(ctx, "alice", "writer"))
+	assert.Equal(t, 2, writes, "adding existing roles must not write")
+
+	user, err = users.GetUser(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reader", "writer"}, user.Roles)
+	assert.Equal(t, "abc", user.DerivedKey)
+	assert.Equal(t, "alice@example.com", stored["email"], "unmodelled fields must be preserved")
+
+	require.NoError(t, users.UpdatePassword(ctx, "alice", "new-secret"))
+	assert.Equal(t, 3, writes)
+}
+
+func TestUserID(t *testing.T) {
+	assert.Equal(t, "org.couchdb.user:bob", UserID("bob"))
+	assert.Equal(t, "org.couchdb.user:bob", UserID("org.couchdb.user:bob"))
+}
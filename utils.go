@@ -3,24 +3,62 @@ package couchdb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-resty/resty/v2"
+	"io"
+	"net/http"
+	"strings"
 )
 
 // Helper methods
 
 func (c *Client) parseError(resp *resty.Response) error {
+	return parseErrorBody(resp, resp.Body())
+}
+
+// parseStreamError reads the error body of a response requested with SetDoNotParseResponse
+func (c *Client) parseStreamError(resp *resty.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.RawBody(), 1<<20))
+	return parseErrorBody(resp, body)
+}
+
+func parseErrorBody(resp *resty.Response, body []byte) error {
 	var couchError Error
 	couchError.StatusCode = resp.StatusCode()
+	couchError.RequestID = resp.Header().Get(couchRequestIDHeader)
+	if resp.Request != nil {
+		couchError.ClientRequestID = resp.Request.Header.Get(requestIDHeader)
+	}
 
-	if err := json.Unmarshal(resp.Body(), &couchError); err != nil {
+	if len(body) == 0 {
+		// Responses to HEAD requests carry no error body
+		couchError.Type = statusErrorType(couchError.StatusCode)
+		couchError.Reason = http.StatusText(couchError.StatusCode)
+	} else if err := json.Unmarshal(body, &couchError); err != nil {
 		couchError.Type = "unknown"
-		couchError.Reason = string(resp.Body())
+		couchError.Reason = string(body)
 	}
 
 	return &couchError
 }
 
+// statusErrorType names an error after its HTTP status the way CouchDB
+// does, e.g. "not_found" for 404
+func statusErrorType(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "unknown"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
+
+// isStatus reports whether err is a CouchDB error with the given HTTP status
+func isStatus(err error, status int) bool {
+	var couchErr *Error
+	return errors.As(err, &couchErr) && couchErr.StatusCode == status
+}
+
 // Utility functions
 
 // UUID generates a UUID from CouchDB
@@ -71,3 +109,12 @@ func (c *Client) UUIDs(ctx context.Context, count int) ([]string, error) {
 
 	return result.UUIDs, nil
 }
+
+// convertDoc re-encodes a document value into another type via JSON
+func convertDoc(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
@@ -0,0 +1,103 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// maxUUIDCount is the most UUIDs CouchDB returns per request by default
+// (uuids/max_count)
+const maxUUIDCount = 1000
+
+// UUIDSourceOptions configures a UUIDSource
+type UUIDSourceOptions struct {
+	BatchSize int // UUIDs fetched per request, defaults to 100
+}
+
+// UUIDSource hands out UUIDs from the server's _uuids endpoint, fetching
+// them a batch at a time and serving the rest from memory. It is safe for
+// concurrent use and refills itself when empty. A UUIDSource is an
+// IDGenerator, e.g. for WithIDGenerator.
+type UUIDSource struct {
+	client    *Client
+	batchSize int
+
+	mu   sync.Mutex
+	pool []string
+}
+
+// NewUUIDSource creates a UUIDSource. No UUIDs are fetched until the first
+// is needed or Refill is called.
+func (c *Client) NewUUIDSource(opts *UUIDSourceOptions) *UUIDSource {
+	s := &UUIDSource{client: c, batchSize: 100}
+	if opts != nil && opts.BatchSize > 0 {
+		s.batchSize = min(opts.BatchSize, maxUUIDCount)
+	}
+	return s
+}
+
+// Next returns a UUID, fetching a batch first if none are left
+func (s *UUIDSource) Next(ctx context.Context) (string, error) {
+	uuids, err := s.Take(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	return uuids[0], nil
+}
+
+// NewID implements IDGenerator
+func (s *UUIDSource) NewID(ctx context.Context) (string, error) {
+	return s.Next(ctx)
+}
+
+// Take returns n UUIDs, fetching as many more as needed
+func (s *UUIDSource) Take(ctx context.Context, n int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for len(s.pool) < n {
+		if err := s.fetch(ctx, max(s.batchSize, n-len(s.pool))); err != nil {
+			return nil, err
+		}
+	}
+
+	uuids := s.pool[:n:n]
+	s.pool = s.pool[n:]
+	return uuids, nil
+}
+
+// Refill fetches a batch now, e.g. at startup so that the first writes do
+// not wait for the server, unless a full batch is already available
+func (s *UUIDSource) Refill(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pool) >= s.batchSize {
+		return nil
+	}
+	return s.fetch(ctx, s.batchSize)
+}
+
+// Len returns the number of UUIDs available without a request
+func (s *UUIDSource) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pool)
+}
+
+// fetch appends count UUIDs to the pool
+func (s *UUIDSource) fetch(ctx context.Context, count int) error {
+	count = min(count, maxUUIDCount)
+
+	uuids, err := s.client.UUIDs(ctx, count)
+	if err != nil {
+		return err
+	}
+	if len(uuids) == 0 {
+		return fmt.Errorf("no UUID returned")
+	}
+
+	s.pool = append(s.pool, uuids...)
+	return nil
+}
@@ -0,0 +1,116 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/SwanHtetAungPhyo/couchdb/couchdbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUIDSource(t *testing.T) {
+	var mu sync.Mutex
+	var counts []int
+	next := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+		mu.Lock()
+		counts = append(counts, count)
+		uuids := make([]string, count)
+		for i := range uuids {
+			uuids[i] = fmt.Sprintf("%06d", next)
+			next++
+		}
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string][]string{"uuids": uuids})
+	}))
+	defer server.Close()
+
+	source := NewClient(server.URL, nil).NewUUIDSource(&UUIDSourceOptions{BatchSize: 10})
+	ctx := context.Background()
+
+	require.NoError(t, source.Refill(ctx))
+	assert.Equal(t, 10, source.Len())
+	require.NoError(t, source.Refill(ctx), "a full pool is not refilled")
+
+	id, err := source.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "000000", id)
+
+	// Taking more than a batch fetches the difference in one request
+	uuids, err := source.Take(ctx, 25)
+	require.NoError(t, err)
+	assert.Len(t, uuids, 25)
+	assert.Equal(t, "000001", uuids[0])
+	assert.Equal(t, "000025", uuids[24])
+	assert.Equal(t, 0, source.Len())
+
+	// Concurrent callers never get the same UUID
+	seen := make(chan string, 100)
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := source.NewID(ctx)
+			assert.NoError(t, err)
+			seen <- id
+		}()
+	}
+	wg.Wait()
+	close(seen)
+
+	ids := map[string]bool{}
+	for id := range seen {
+		ids[id] = true
+	}
+	assert.Len(t, ids, 100)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{10, 16}, counts[:2])
+	assert.Len(t, counts, 12)
+}
+
+func TestBulkLoader_IDGenerator(t *testing.T) {
+	server := couchdbtest.NewServer()
+	defer server.Close()
+	server.CreateDB("events")
+
+	client := NewClient(server.URL, nil)
+	db := client.DB("events").WithIDGenerator(client.NewUUIDSource(nil))
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var ids []string
+	loader := db.NewBulkLoader(ctx, &BulkLoaderOptions{
+		OnResult: func(doc interface{}, result BulkResult, err error) {
+			assert.NoError(t, err)
+			mu.Lock()
+			ids = append(ids, result.ID)
+			mu.Unlock()
+		},
+	})
+	for i := range 5 {
+		require.NoError(t, loader.Add(ctx, map[string]interface{}{"n": i}))
+	}
+	require.NoError(t, loader.Add(ctx, map[string]interface{}{"_id": "given"}))
+	require.NoError(t, loader.Close())
+
+	assert.Len(t, ids, 6)
+	assert.Contains(t, ids, "given")
+	for _, id := range ids {
+		assert.Regexp(t, `^([0-9a-f]{32}|given)$`, id)
+	}
+}
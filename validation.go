@@ -0,0 +1,201 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ValidationRule is one check of a validate_doc_update function. JS holds
+// the statements run by CouchDB, which throw {forbidden: reason} to reject
+// a write; Check is the same rule in Go and returns the reason as an error.
+// oldDoc is nil for new documents and user is nil when unknown, as for
+// checks made by the client before a write.
+type ValidationRule struct {
+	JS    string
+	Check func(newDoc, oldDoc map[string]interface{}, user *UserContext) error
+}
+
+// RequireFields rejects documents lacking any of the given top-level fields.
+// Deletions are allowed.
+func RequireFields(fields ...string) ValidationRule {
+	var js []string
+	for _, field := range fields {
+		js = append(js, fmt.Sprintf("if (!newDoc._deleted && newDoc[%s] === undefined) throw({forbidden: %s});",
+			jsString(field), jsString(field+" is required")))
+	}
+
+	return ValidationRule{
+		JS: strings.Join(js, "\n"),
+		Check: func(newDoc, oldDoc map[string]interface{}, user *UserContext) error {
+			if deleted(newDoc) {
+				return nil
+			}
+			for _, field := range fields {
+				if _, ok := newDoc[field]; !ok {
+					return errors.New(field + " is required")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ImmutableFields rejects updates changing any of the given top-level
+// fields once a document exists, e.g. ImmutableFields("type")
+func ImmutableFields(fields ...string) ValidationRule {
+	var js []string
+	for _, field := range fields {
+		js = append(js, fmt.Sprintf("if (oldDoc && !newDoc._deleted && JSON.stringify(newDoc[%[1]s]) !== JSON.stringify(oldDoc[%[1]s])) throw({forbidden: %[2]s});",
+			jsString(field), jsString(field+" cannot be changed")))
+	}
+
+	return ValidationRule{
+		JS: strings.Join(js, "\n"),
+		Check: func(newDoc, oldDoc map[string]interface{}, user *UserContext) error {
+			if oldDoc == nil || deleted(newDoc) {
+				return nil
+			}
+			for _, field := range fields {
+				if !reflect.DeepEqual(newDoc[field], oldDoc[field]) {
+					return errors.New(field + " cannot be changed")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// AuthorOnly lets only the user named in field edit or delete a document,
+// and requires new documents to name the user creating them. Server admins
+// are exempt.
+func AuthorOnly(field string) ValidationRule {
+	name := jsString(field)
+	return ValidationRule{
+		JS: fmt.Sprintf(`if (userCtx.roles.indexOf("_admin") === -1) {
+  if (oldDoc && oldDoc[%[1]s] !== userCtx.name) throw({forbidden: %[2]s});
+  if (!newDoc._deleted && newDoc[%[1]s] !== userCtx.name) throw({forbidden: %[3]s});
+}`, name, jsString("only the author may change this document"), jsString(field+" must be the current user")),
+		Check: func(newDoc, oldDoc map[string]interface{}, user *UserContext) error {
+			if user == nil || hasRole(user.Roles, "_admin") {
+				return nil
+			}
+			if oldDoc != nil && oldDoc[field] != user.Name {
+				return errors.New("only the author may change this document")
+			}
+			if !deleted(newDoc) && newDoc[field] != user.Name {
+				return errors.New(field + " must be the current user")
+			}
+			return nil
+		},
+	}
+}
+
+// DocValidator checks documents before a Database handle writes them, see
+// Database.WithValidator
+type DocValidator interface {
+	ValidateDoc(newDoc, oldDoc map[string]interface{}, user *UserContext) error
+}
+
+// Validator composes validation rules into a validate_doc_update function
+// and checks documents against the same rules in Go
+type Validator struct {
+	rules []ValidationRule
+}
+
+// NewValidator returns a Validator applying rules in order
+func NewValidator(rules ...ValidationRule) *Validator {
+	return &Validator{rules: rules}
+}
+
+// Function returns the validate_doc_update source for a design document
+func (v *Validator) Function() string {
+	var b strings.Builder
+	b.WriteString("function (newDoc, oldDoc, userCtx, secObj) {\n")
+	for _, rule := range v.rules {
+		for _, line := range strings.Split(rule.JS, "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// ValidateDoc runs the rules and returns a *ValidationError for the first
+// that fails
+func (v *Validator) ValidateDoc(newDoc, oldDoc map[string]interface{}, user *UserContext) error {
+	for _, rule := range v.rules {
+		if err := rule.Check(newDoc, oldDoc, user); err != nil {
+			return &ValidationError{Index: -1, Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+// ValidationError is a write rejected by a DocValidator before it was sent.
+// errors.Is matches it with ErrForbidden, like a rejection by CouchDB.
+type ValidationError struct {
+	DocID  string // document ID, if known
+	Index  int    // position in a bulk request, or -1
+	Reason string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	doc := e.DocID
+	if doc == "" {
+		doc = "(new document)"
+	}
+	if e.Index >= 0 {
+		return fmt.Sprintf("document %s at index %d is invalid: %s", doc, e.Index, e.Reason)
+	}
+	return fmt.Sprintf("document %s is invalid: %s", doc, e.Reason)
+}
+
+// Is reports whether target is ErrForbidden
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// validate runs the handle's validator, if any, on a document about to be
+// written. The stored document and the user are not known on the client,
+// so rules depending on them are left to CouchDB.
+func (db *Database) validate(doc interface{}, index int) error {
+	if db.validator == nil {
+		return nil
+	}
+
+	var m map[string]interface{}
+	if err := convertDoc(doc, &m); err != nil {
+		return err
+	}
+
+	err := db.validator.ValidateDoc(m, nil, nil)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.DocID, _ = m["_id"].(string)
+		validationErr.Index = index
+	}
+	return err
+}
+
+func deleted(doc map[string]interface{}) bool {
+	d, _ := doc["_deleted"].(bool)
+	return d
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func jsString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
@@ -0,0 +1,78 @@
+package couchdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator(t *testing.T) {
+	v := NewValidator(RequireFields("type", "title"), ImmutableFields("type"), AuthorOnly("author"))
+
+	assert.Equal(t, `function (newDoc, oldDoc, userCtx, secObj) {
+  if (!newDoc._deleted && newDoc["type"] === undefined) throw({forbidden: "type is required"});
+  if (!newDoc._deleted && newDoc["title"] === undefined) throw({forbidden: "title is required"});
+  if (oldDoc && !newDoc._deleted && JSON.stringify(newDoc["type"]) !== JSON.stringify(oldDoc["type"])) throw({forbidden: "type cannot be changed"});
+  if (userCtx.roles.indexOf("_admin") === -1) {
+    if (oldDoc && oldDoc["author"] !== userCtx.name) throw({forbidden: "only the author may change this document"});
+    if (!newDoc._deleted && newDoc["author"] !== userCtx.name) throw({forbidden: "author must be the current user"});
+  }
+}`, v.Function())
+
+	alice := &UserContext{Name: "alice"}
+	post := map[string]interface{}{"type": "post", "title": "Hi", "author": "alice"}
+
+	assert.NoError(t, v.ValidateDoc(post, nil, alice))
+	assert.NoError(t, v.ValidateDoc(map[string]interface{}{"_deleted": true}, post, alice))
+
+	err := v.ValidateDoc(map[string]interface{}{"type": "post"}, nil, nil)
+	assert.EqualError(t, err, "document (new document) is invalid: title is required")
+	assert.True(t, IsForbidden(err))
+
+	changed := map[string]interface{}{"type": "page", "title": "Hi", "author": "alice"}
+	assert.ErrorContains(t, v.ValidateDoc(changed, post, alice), "type cannot be changed")
+
+	assert.ErrorContains(t, v.ValidateDoc(post, post, &UserContext{Name: "bob"}), "only the author")
+	assert.ErrorContains(t, v.ValidateDoc(post, nil, &UserContext{Name: "bob"}), "author must be the current user")
+	assert.NoError(t, v.ValidateDoc(post, post, &UserContext{Name: "root", Roles: []string{"_admin"}}))
+}
+
+func TestWithValidator(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db/_bulk_docs" {
+			_, _ = w.Write([]byte(`[{"ok":true,"id":"a","rev":"1-a"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"id":"a","rev":"1-a"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db").WithValidator(NewValidator(RequireFields("type")))
+	ctx := context.Background()
+
+	_, err := db.Put(ctx, map[string]interface{}{"_id": "a"})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "a", validationErr.DocID)
+
+	_, err = db.Bulk(ctx, []interface{}{map[string]interface{}{"type": "x"}, map[string]interface{}{}})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, 1, validationErr.Index)
+	assert.Zero(t, requests)
+
+	_, err = db.Put(ctx, map[string]interface{}{"_id": "a", "type": "x"})
+	require.NoError(t, err)
+	_, err = db.Bulk(ctx, []interface{}{map[string]interface{}{"type": "x"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	assert.Equal(t, "mydesign", db.WithDesignDoc("mydesign").designDoc)
+	assert.NotNil(t, db.WithDesignDoc("mydesign").validator)
+}
@@ -0,0 +1,36 @@
+package couchdb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion returns the CouchDB version reported by the server. The
+// result is cached for the lifetime of the client.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.serverVersion != "" {
+		return c.serverVersion, nil
+	}
+
+	info, err := c.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.serverVersion = info.Version
+	return c.serverVersion, nil
+}
+
+// majorVersion returns the major component of a version such as "3.2.0"
+func majorVersion(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
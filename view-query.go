@@ -1,16 +1,26 @@
 package couchdb
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // NewViewQuery creates a new view query builder
 func (db *Database) NewViewQuery(designDoc, viewName string) *ViewBuilder {
 	return &ViewBuilder{
+		db:        db,
 		designDoc: designDoc,
 		viewName:  viewName,
 		options:   &ViewOptions{},
 	}
 }
 
+// Q creates a view query builder for a view of the default design
+// document set with WithDesignDoc. Run it with Run.
+func (db *Database) Q(viewName string) *ViewBuilder {
+	return db.NewViewQuery(db.designDoc, viewName)
+}
+
 // Key sets a specific key to query
 func (vb *ViewBuilder) Key(key interface{}) *ViewBuilder {
 	vb.options.Key = key
@@ -77,13 +87,34 @@ func (vb *ViewBuilder) IncludeDocs(include bool) *ViewBuilder {
 	return vb
 }
 
-// Stale controls staleness tolerance
+// Stale controls staleness tolerance. Deprecated in CouchDB 2.0+; the client
+// translates it into Stable and Update when talking to newer servers.
 func (vb *ViewBuilder) Stale(stale string) *ViewBuilder {
 	vb.options.Stale = stale
 	return vb
 }
 
+// Stable requests results from a stable set of shards
+func (vb *ViewBuilder) Stable(stable bool) *ViewBuilder {
+	vb.options.Stable = stable
+	return vb
+}
+
+// Update controls index updating: "true", "false" or "lazy"
+func (vb *ViewBuilder) Update(update string) *ViewBuilder {
+	vb.options.Update = update
+	return vb
+}
+
 // Execute runs the view query
 func (vb *ViewBuilder) Execute(ctx context.Context, db *Database) (*ViewResult, error) {
 	return db.View(ctx, vb.designDoc, vb.viewName, vb.options)
 }
+
+// Run runs the view query against the database that created the builder
+func (vb *ViewBuilder) Run(ctx context.Context) (*ViewResult, error) {
+	if vb.designDoc == "" {
+		return nil, errors.New("view query: no design document, see Database.WithDesignDoc")
+	}
+	return vb.Execute(ctx, vb.db)
+}
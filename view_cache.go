@@ -0,0 +1,171 @@
+package couchdb
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ViewCacheOptions configures a ViewCache
+type ViewCacheOptions struct {
+	// MaxStaleness is how long a cached result is returned without checking
+	// the database for writes. Zero checks the update sequence on every hit.
+	MaxStaleness time.Duration
+
+	// MaxEntries is the number of results kept, defaults to 1000. The least
+	// recently used results are evicted first.
+	MaxEntries int
+}
+
+// ViewCache caches view query results, e.g. for dashboards that repeat the
+// same queries. Results are keyed by design document, view and options.
+//
+// A result younger than MaxStaleness is returned as is. After that the
+// cache compares the database's update_seq with the one the result was
+// queried at: if nothing was written, the result is kept for another
+// window, otherwise the view is queried again. Results are shared between
+// callers and must not be modified.
+type ViewCache struct {
+	db   *Database
+	opts ViewCacheOptions
+
+	mu      sync.Mutex
+	lru     *list.List // *viewCacheEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+// viewCacheEntry is a cached result and the update_seq it was queried at
+type viewCacheEntry struct {
+	key     string
+	result  *ViewResult
+	seq     Sequence
+	checked time.Time
+}
+
+// NewViewCache creates a cache for view queries on db
+func (db *Database) NewViewCache(opts *ViewCacheOptions) *ViewCache {
+	c := &ViewCache{
+		db:      db,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.MaxEntries <= 0 {
+		c.opts.MaxEntries = 1000
+	}
+
+	return c
+}
+
+// View returns the result of a view query, from the cache while it is fresh
+func (c *ViewCache) View(ctx context.Context, designDoc, viewName string, opts *ViewOptions) (*ViewResult, error) {
+	key, err := viewCacheKey(designDoc, viewName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, cached := c.lookup(key)
+	if cached && time.Since(entry.checked) < c.opts.MaxStaleness {
+		return entry.result, nil
+	}
+
+	// The sequence is read before the query, so that writes made while it
+	// runs invalidate the result
+	info, err := c.db.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && entry.seq == info.UpdateSeq {
+		c.revalidate(key, entry.seq)
+		return entry.result, nil
+	}
+
+	result, err := c.db.View(ctx, designDoc, viewName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(&viewCacheEntry{key: key, result: result, seq: info.UpdateSeq, checked: time.Now()})
+	return result, nil
+}
+
+// Invalidate drops all cached results, e.g. after a write whose effect has
+// to be visible before MaxStaleness has passed
+func (c *ViewCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Len returns the number of cached results
+func (c *ViewCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// lookup returns a copy of the cached entry for key, marking it as used.
+// Entries are only read and written under c.mu, as callers share them.
+func (c *ViewCache) lookup(key string) (viewCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return viewCacheEntry{}, false
+	}
+	c.lru.MoveToFront(elem)
+	return *elem.Value.(*viewCacheEntry), true
+}
+
+// revalidate starts a new staleness window for the entry of key, unless it
+// was replaced by a result queried at another sequence meanwhile
+func (c *ViewCache) revalidate(key string, seq Sequence) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		if entry := elem.Value.(*viewCacheEntry); entry.seq == seq {
+			entry.checked = time.Now()
+		}
+	}
+}
+
+// store caches an entry, replacing an older result for its key and
+// evicting the least recently used
+func (c *ViewCache) store(entry *viewCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.lru.Remove(elem)
+	}
+
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.opts.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*viewCacheEntry).key)
+	}
+}
+
+// viewCacheKey identifies a query by its view and JSON encoded options
+func viewCacheKey(designDoc, viewName string, opts *ViewOptions) (string, error) {
+	if opts == nil {
+		opts = &ViewOptions{}
+	}
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(designDoc, "_design/") + "\x00" + viewName + "\x00" + string(encoded), nil
+}
@@ -0,0 +1,128 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewCache(t *testing.T) {
+	var mu sync.Mutex
+	updateSeq := "1-a"
+	infos, queries := 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/db":
+			infos++
+			fmt.Fprintf(w, `{"db_name":"db","update_seq":%q}`, updateSeq)
+		case "/db/_design/app/_view/by_date":
+			queries++
+			fmt.Fprintf(w, `{"total_rows":1,"offset":0,"rows":[{"id":"a","key":%q,"value":%d}]}`, r.URL.Query().Get("key"), queries)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	counts := func() (int, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return infos, queries
+	}
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	// Within the staleness window hits need no requests
+	cache := db.NewViewCache(&ViewCacheOptions{MaxStaleness: time.Hour, MaxEntries: 2})
+	first, err := cache.View(ctx, "app", "by_date", &ViewOptions{Key: "2024"})
+	require.NoError(t, err)
+	again, err := cache.View(ctx, "_design/app", "by_date", &ViewOptions{Key: "2024"})
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	infos, queries = counts()
+	assert.Equal(t, 1, infos)
+	assert.Equal(t, 1, queries)
+
+	// Other options are cached separately, evicting the least recently used
+	_, err = cache.View(ctx, "app", "by_date", &ViewOptions{Key: "2025"})
+	require.NoError(t, err)
+	_, err = cache.View(ctx, "app", "by_date", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+	_, err = cache.View(ctx, "app", "by_date", &ViewOptions{Key: "2024"})
+	require.NoError(t, err)
+	_, queries = counts()
+	assert.Equal(t, 4, queries)
+
+	cache.Invalidate()
+	assert.Equal(t, 0, cache.Len())
+
+	// Without a window every hit checks update_seq and only a write
+	// invalidates the result
+	cache = db.NewViewCache(nil)
+	first, err = cache.View(ctx, "app", "by_date", nil)
+	require.NoError(t, err)
+	again, err = cache.View(ctx, "app", "by_date", nil)
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	mu.Lock()
+	updateSeq = "2-b"
+	mu.Unlock()
+
+	refreshed, err := cache.View(ctx, "app", "by_date", nil)
+	require.NoError(t, err)
+	assert.NotSame(t, first, refreshed)
+	assert.NotEqual(t, first.Rows[0].Value, refreshed.Rows[0].Value)
+
+	infos, queries = counts()
+	assert.Equal(t, 7, infos)
+	assert.Equal(t, 6, queries)
+}
+
+func TestViewCache_Concurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/db" {
+			fmt.Fprint(w, `{"db_name":"db","update_seq":"1-a"}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_rows":1,"offset":0,"rows":[{"id":"a","key":"a","value":1}]}`)
+	}))
+	defer server.Close()
+
+	// A tiny window makes callers revalidate the shared entry concurrently
+	cache := NewClient(server.URL, nil).DB("db").NewViewCache(&ViewCacheOptions{MaxStaleness: time.Microsecond})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				result, err := cache.View(ctx, "app", "by_date", nil)
+				if assert.NoError(t, err) {
+					assert.Len(t, result.Rows, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,205 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ViewRows iterates over view rows decoded incrementally from the response
+// body, so large results are never held in memory at once. It must be closed.
+//
+//	rows, err := db.ViewStream(ctx, "app", "by_date", nil)
+//	if err != nil { ... }
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//	}
+//	if err := rows.Err(); err != nil { ... }
+type ViewRows struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+
+	// Metadata reported before the rows
+	TotalRows int64
+	Offset    int64
+	// UpdateSeq is reported after the rows and is set once Next returns false
+	UpdateSeq Sequence
+
+	row  ViewRow
+	err  error
+	done bool
+}
+
+// ViewStream queries a view and returns its rows as a stream. When opts.Keys
+// is set the query is sent as a POST. The request is not subject to the
+// client timeout; use ctx to bound it.
+func (db *Database) ViewStream(ctx context.Context, designDoc, viewName string, opts *ViewOptions) (*ViewRows, error) {
+	return db.streamRows(ctx, db.designPath(designDoc, "_view", viewName), opts)
+}
+
+// AllDocsStream returns the rows of _all_docs as a stream
+func (db *Database) AllDocsStream(ctx context.Context, opts *ViewOptions) (*ViewRows, error) {
+	return db.streamRows(ctx, db.path("_all_docs"), opts)
+}
+
+func (db *Database) streamRows(ctx context.Context, path string, opts *ViewOptions) (*ViewRows, error) {
+	opts = db.resolveStale(ctx, opts)
+
+	req := db.client.stream.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true)
+
+	var resp *resty.Response
+	var err error
+
+	if opts != nil && opts.Keys != nil {
+		resp, err = req.SetBody(opts.bodyParams()).Post(path)
+	} else {
+		params, encodeErr := opts.queryParams()
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+		resp, err = req.SetQueryParams(params).Get(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		defer resp.RawBody().Close()
+		return nil, db.client.parseStreamError(resp)
+	}
+
+	rows := &ViewRows{body: resp.RawBody(), dec: json.NewDecoder(resp.RawBody())}
+	if err := rows.readHeader(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// readHeader consumes the response up to the first row
+func (r *ViewRows) readHeader() error {
+	if err := r.expectDelim('{'); err != nil {
+		return err
+	}
+
+	for r.dec.More() {
+		key, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if key == "rows" {
+			return r.expectDelim('[')
+		}
+
+		if err := r.readField(key); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("view stream: response has no rows")
+}
+
+// readField decodes a top-level field other than rows
+func (r *ViewRows) readField(key json.Token) error {
+	switch key {
+	case "total_rows":
+		return r.dec.Decode(&r.TotalRows)
+	case "offset":
+		return r.dec.Decode(&r.Offset)
+	case "update_seq":
+		var seq Sequence
+		if err := r.dec.Decode(&seq); err != nil {
+			return err
+		}
+		r.UpdateSeq = seq
+		return nil
+	default:
+		var skip json.RawMessage
+		return r.dec.Decode(&skip)
+	}
+}
+
+func (r *ViewRows) expectDelim(delim json.Delim) error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("view stream: expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// Next advances to the next row, returning false when the rows are
+// exhausted or an error occurred
+func (r *ViewRows) Next() bool {
+	if r.done || r.err != nil {
+		return false
+	}
+
+	if !r.dec.More() {
+		r.done = true
+		r.err = r.readTrailer()
+		return false
+	}
+
+	r.row = ViewRow{}
+	if err := r.dec.Decode(&r.row); err != nil {
+		r.err = err
+		return false
+	}
+
+	return true
+}
+
+// readTrailer consumes the fields that follow the rows
+func (r *ViewRows) readTrailer() error {
+	if err := r.expectDelim(']'); err != nil {
+		return err
+	}
+
+	for r.dec.More() {
+		key, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := r.readField(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Row returns the current row
+func (r *ViewRows) Row() ViewRow {
+	return r.row
+}
+
+// ScanDoc decodes the current row's document into dest
+func (r *ViewRows) ScanDoc(dest interface{}) error {
+	if r.row.Doc == nil {
+		return fmt.Errorf("row %q has no document", r.row.ID)
+	}
+	return convertDoc(r.row.Doc, dest)
+}
+
+// Err returns the error, if any, that stopped iteration
+func (r *ViewRows) Err() error {
+	return r.err
+}
+
+// Close releases the response body
+func (r *ViewRows) Close() error {
+	r.done = true
+	return r.body.Close()
+}
@@ -4,170 +4,229 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/go-resty/resty/v2"
 )
 
 // Enhanced View Methods
 
-// View executes a view query with comprehensive options
+// View executes a view query with comprehensive options. When opts.Keys
+// is set the keys are sent in a POST body, as for AllDocs.
 func (db *Database) View(ctx context.Context, designDoc, viewName string, opts *ViewOptions) (*ViewResult, error) {
-	req := db.client.resty.R().SetContext(ctx)
+	return db.queryRows(ctx, db.designPath(designDoc, "_view", viewName), opts)
+}
 
+// ViewWithKeys executes a view query with multiple keys (POST request).
+// All options are sent in the request body alongside the keys.
+func (db *Database) ViewWithKeys(ctx context.Context, designDoc, viewName string, keys []interface{}, opts *ViewOptions) (*ViewResult, error) {
+	var o ViewOptions
 	if opts != nil {
-		// Handle key-based queries
-		if opts.Key != nil {
-			keyBytes, _ := json.Marshal(opts.Key)
-			req.SetQueryParam("key", string(keyBytes))
-		}
-
-		if len(opts.Keys) > 0 {
-			keysBytes, _ := json.Marshal(opts.Keys)
-			req.SetQueryParam("keys", string(keysBytes))
-		}
-
-		if opts.StartKey != nil {
-			keyBytes, _ := json.Marshal(opts.StartKey)
-			req.SetQueryParam("startkey", string(keyBytes))
-		}
-
-		if opts.EndKey != nil {
-			keyBytes, _ := json.Marshal(opts.EndKey)
-			req.SetQueryParam("endkey", string(keyBytes))
-		}
+		o = *opts
+	}
+	o.Keys = keys
 
-		if opts.StartKeyDocID != "" {
-			req.SetQueryParam("startkey_docid", opts.StartKeyDocID)
-		}
+	return db.View(ctx, designDoc, viewName, &o)
+}
 
-		if opts.EndKeyDocID != "" {
-			req.SetQueryParam("endkey_docid", opts.EndKeyDocID)
-		}
+// queryRows runs a view or _all_docs query at path, relative to the
+// database. Options are encoded the same way for both endpoints: in the
+// query string, or in a POST body when keys are given so that large key
+// sets are not limited by URL length.
+func (db *Database) queryRows(ctx context.Context, path string, opts *ViewOptions) (*ViewResult, error) {
+	opts = db.resolveStale(ctx, opts)
 
-		// Result control
-		if opts.Limit > 0 {
-			req.SetQueryParam("limit", fmt.Sprintf("%d", opts.Limit))
+	var result ViewResult
+	req := db.client.resty.R().
+		SetContext(ctx).
+		SetResult(&result)
+
+	var resp *resty.Response
+	var err error
+
+	if opts != nil && opts.Keys != nil {
+		resp, err = req.
+			SetBody(opts.bodyParams()).
+			Post(path)
+	} else {
+		params, encodeErr := opts.queryParams()
+		if encodeErr != nil {
+			return nil, encodeErr
 		}
+		resp, err = req.
+			SetQueryParams(params).
+			Get(path)
+	}
 
-		if opts.Skip > 0 {
-			req.SetQueryParam("skip", fmt.Sprintf("%d", opts.Skip))
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		if opts.Descending {
-			req.SetQueryParam("descending", "true")
-		}
+	if resp.IsError() {
+		return nil, db.client.parseError(resp)
+	}
 
-		if opts.InclusiveEnd {
-			req.SetQueryParam("inclusive_end", "true")
-		}
+	db.dropDeletedRows(&result)
+	return &result, nil
+}
 
-		// Group/Reduce options
-		if opts.Group {
-			req.SetQueryParam("group", "true")
-		}
+// resolveStale translates the deprecated stale option into the equivalent
+// stable/update options when the server is CouchDB 2.0 or newer. If the
+// version cannot be determined the options are sent unchanged.
+func (db *Database) resolveStale(ctx context.Context, opts *ViewOptions) *ViewOptions {
+	if opts == nil || opts.Stale == "" {
+		return opts
+	}
 
-		if opts.GroupLevel > 0 {
-			req.SetQueryParam("group_level", fmt.Sprintf("%d", opts.GroupLevel))
-		}
+	version, err := db.client.ServerVersion(ctx)
+	if err != nil || majorVersion(version) < 2 {
+		return opts
+	}
 
-		if opts.Reduce != nil {
-			req.SetQueryParam("reduce", fmt.Sprintf("%t", *opts.Reduce))
-		}
+	db.client.staleWarning.Do(func() {
+		db.client.logger.Warnf("view option stale is deprecated since CouchDB 2.0; translating to stable/update")
+	})
 
-		// Additional options
-		if opts.IncludeDocs {
-			req.SetQueryParam("include_docs", "true")
-		}
+	translated := *opts
+	translated.Stale = ""
+	translated.Stable = true
 
-		if opts.UpdateSeq {
-			req.SetQueryParam("update_seq", "true")
-		}
+	switch opts.Stale {
+	case "ok":
+		translated.Update = "false"
+	case "update_after":
+		translated.Update = "lazy"
+	}
 
-		if opts.Conflicts {
-			req.SetQueryParam("conflicts", "true")
-		}
+	return &translated
+}
 
-		if opts.Attachments {
-			req.SetQueryParam("attachments", "true")
-		}
+// jsonKeyParams are view parameters whose values are JSON encoded in query strings
+var jsonKeyParams = map[string]bool{
+	"key":      true,
+	"keys":     true,
+	"startkey": true,
+	"endkey":   true,
+}
 
-		if opts.AttEncodingInfo {
-			req.SetQueryParam("att_encoding_info", "true")
-		}
+// fields returns the set options keyed by CouchDB parameter name
+func (opts *ViewOptions) fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	if opts == nil {
+		return fields
+	}
 
-		// Staleness control
-		if opts.Stale != "" {
-			req.SetQueryParam("stale", opts.Stale)
-		}
+	// Key selection
+	if opts.Key != nil {
+		fields["key"] = opts.Key
+	}
+	if opts.Keys != nil {
+		fields["keys"] = opts.Keys
+	}
+	if opts.StartKey != nil {
+		fields["startkey"] = opts.StartKey
+	}
+	if opts.EndKey != nil {
+		fields["endkey"] = opts.EndKey
+	}
+	if opts.StartKeyDocID != "" {
+		fields["startkey_docid"] = opts.StartKeyDocID
+	}
+	if opts.EndKeyDocID != "" {
+		fields["endkey_docid"] = opts.EndKeyDocID
+	}
 
-		if opts.Update != "" {
-			req.SetQueryParam("update", opts.Update)
-		}
+	// Result control
+	if opts.Limit > 0 {
+		fields["limit"] = opts.Limit
+	}
+	if opts.Skip > 0 {
+		fields["skip"] = opts.Skip
+	}
+	if opts.Descending {
+		fields["descending"] = true
+	}
+	if opts.InclusiveEnd {
+		fields["inclusive_end"] = true
+	}
+	if opts.Sorted != nil {
+		fields["sorted"] = *opts.Sorted
 	}
 
-	var result ViewResult
-	resp, err := req.
-		SetResult(&result).
-		Get("/" + db.name + "/_design/" + designDoc + "/_view/" + viewName)
+	// Group/Reduce options
+	if opts.Group {
+		fields["group"] = true
+	}
+	if opts.GroupLevel > 0 {
+		fields["group_level"] = opts.GroupLevel
+	}
+	if opts.Reduce != nil {
+		fields["reduce"] = *opts.Reduce
+	}
 
-	if err != nil {
-		return nil, err
+	// Additional options
+	if opts.IncludeDocs {
+		fields["include_docs"] = true
+	}
+	if opts.UpdateSeq {
+		fields["update_seq"] = true
+	}
+	if opts.Conflicts {
+		fields["conflicts"] = true
+	}
+	if opts.Attachments {
+		fields["attachments"] = true
+	}
+	if opts.AttEncodingInfo {
+		fields["att_encoding_info"] = true
 	}
 
-	if resp.IsError() {
-		return nil, db.client.parseError(resp)
+	// Staleness control
+	if opts.Stale != "" {
+		fields["stale"] = opts.Stale
+	}
+	if opts.Update != "" {
+		fields["update"] = opts.Update
+	}
+	if opts.Stable {
+		fields["stable"] = true
 	}
 
-	return &result, nil
+	return fields
 }
 
-// ViewWithKeys executes a view query with multiple keys (POST request)
-func (db *Database) ViewWithKeys(ctx context.Context, designDoc, viewName string, keys []interface{}, opts *ViewOptions) (*ViewResult, error) {
-	body := map[string]interface{}{
-		"keys": keys,
-	}
+// queryParams encodes the options as URL query parameters
+func (opts *ViewOptions) queryParams() (map[string]string, error) {
+	params := make(map[string]string)
 
-	req := db.client.resty.R().
-		SetContext(ctx).
-		SetBody(body)
-
-	if opts != nil {
-		// Add query parameters for other options
-		if opts.IncludeDocs {
-			req.SetQueryParam("include_docs", "true")
-		}
-		if opts.Limit > 0 {
-			req.SetQueryParam("limit", fmt.Sprintf("%d", opts.Limit))
-		}
-		if opts.Skip > 0 {
-			req.SetQueryParam("skip", fmt.Sprintf("%d", opts.Skip))
-		}
-		if opts.Descending {
-			req.SetQueryParam("descending", "true")
+	for name, value := range opts.fields() {
+		if s, ok := value.(string); ok && !jsonKeyParams[name] {
+			params[name] = s
+			continue
 		}
-		if opts.Group {
-			req.SetQueryParam("group", "true")
-		}
-		if opts.GroupLevel > 0 {
-			req.SetQueryParam("group_level", fmt.Sprintf("%d", opts.GroupLevel))
-		}
-		if opts.Reduce != nil {
-			req.SetQueryParam("reduce", fmt.Sprintf("%t", *opts.Reduce))
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("encode view option %s: %w", name, err)
 		}
+		params[name] = string(encoded)
 	}
 
-	var result ViewResult
-	resp, err := req.
-		SetResult(&result).
-		Post("/" + db.name + "/_design/" + designDoc + "/_view/" + viewName)
+	return params, nil
+}
 
-	if err != nil {
-		return nil, err
-	}
+// bodyParams returns the options as a JSON request body
+func (opts *ViewOptions) bodyParams() map[string]interface{} {
+	body := opts.fields()
 
-	if resp.IsError() {
-		return nil, db.client.parseError(resp)
+	// In a request body update is a boolean unless it is "lazy"
+	switch body["update"] {
+	case "true":
+		body["update"] = true
+	case "false":
+		body["update"] = false
 	}
 
-	return &result, nil
+	return body
 }
 
 // ViewInfo gets information about a view
@@ -176,7 +235,7 @@ func (db *Database) ViewInfo(ctx context.Context, designDoc, viewName string) (m
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
 		SetResult(&result).
-		Get("/" + db.name + "/_design/" + designDoc + "/_info")
+		Get(db.designPath(designDoc, "_info"))
 
 	if err != nil {
 		return nil, err
@@ -193,7 +252,7 @@ func (db *Database) ViewInfo(ctx context.Context, designDoc, viewName string) (m
 func (db *Database) ViewCleanup(ctx context.Context) error {
 	resp, err := db.client.resty.R().
 		SetContext(ctx).
-		Post("/" + db.name + "/_view_cleanup")
+		Post(db.path("_view_cleanup"))
 
 	if err != nil {
 		return err
@@ -205,3 +264,43 @@ func (db *Database) ViewCleanup(ctx context.Context) error {
 
 	return nil
 }
+
+// ScanKeys decodes the key of every row into dest, which must be a pointer
+// to a slice, e.g. *[]string or *[][]interface{}
+func (r *ViewResult) ScanKeys(dest interface{}) error {
+	keys := make([]interface{}, len(r.Rows))
+	for i, row := range r.Rows {
+		keys[i] = row.Key
+	}
+	return scanInto(keys, dest)
+}
+
+// ScanValues decodes the value of every row into dest, which must be a
+// pointer to a slice of the view's value type
+func (r *ViewResult) ScanValues(dest interface{}) error {
+	values := make([]interface{}, len(r.Rows))
+	for i, row := range r.Rows {
+		values[i] = row.Value
+	}
+	return scanInto(values, dest)
+}
+
+// ScanDocs decodes the included documents into dest, which must be a
+// pointer to a slice of the document type. Rows without a document, such as
+// deleted or missing ones, are skipped.
+func (r *ViewResult) ScanDocs(dest interface{}) error {
+	docs := make([]*Document, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		if row.Doc != nil {
+			docs = append(docs, row.Doc)
+		}
+	}
+	return scanInto(docs, dest)
+}
+
+func scanInto(src, dest interface{}) error {
+	if err := convertDoc(src, dest); err != nil {
+		return fmt.Errorf("scan view rows: %w", err)
+	}
+	return nil
+}
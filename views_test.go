@@ -0,0 +1,268 @@
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewWithKeys_SendsAllOptions(t *testing.T) {
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ViewResult{})
+	}))
+	defer server.Close()
+
+	sorted := false
+	db := NewClient(server.URL, nil).DB("db")
+	_, err := db.ViewWithKeys(context.Background(), "ddoc", "view", []interface{}{"a", 1}, &ViewOptions{
+		StartKeyDocID: "doc-1",
+		InclusiveEnd:  true,
+		Stable:        true,
+		Sorted:        &sorted,
+		Update:        "false",
+		Conflicts:     true,
+		Attachments:   true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"a", float64(1)}, body["keys"])
+	assert.Equal(t, "doc-1", body["startkey_docid"])
+	assert.Equal(t, true, body["inclusive_end"])
+	assert.Equal(t, true, body["stable"])
+	assert.Equal(t, false, body["sorted"])
+	assert.Equal(t, false, body["update"])
+	assert.Equal(t, true, body["conflicts"])
+	assert.Equal(t, true, body["attachments"])
+}
+
+func TestViewOptions_QueryParams(t *testing.T) {
+	params, err := (&ViewOptions{
+		Key:      "a",
+		StartKey: []interface{}{"x", 1},
+		Stale:    "ok",
+		Limit:    10,
+	}).queryParams()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"key":      `"a"`,
+		"startkey": `["x",1]`,
+		"stale":    "ok",
+		"limit":    "10",
+	}, params)
+}
+
+func TestAllDocs_OptionParity(t *testing.T) {
+	var method, query string
+	var body map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, query, body = r.Method, r.URL.RawQuery, nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ViewResult{})
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+
+	_, err := db.AllDocs(context.Background(), &ViewOptions{
+		StartKey:     "a",
+		EndKeyDocID:  "z",
+		InclusiveEnd: true,
+		Conflicts:    true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "GET", method)
+	assert.Contains(t, query, "startkey=%22a%22")
+	assert.Contains(t, query, "endkey_docid=z")
+	assert.Contains(t, query, "inclusive_end=true")
+	assert.Contains(t, query, "conflicts=true")
+
+	_, err = db.AllDocs(context.Background(), &ViewOptions{Keys: []interface{}{"a", "b"}, IncludeDocs: true})
+	require.NoError(t, err)
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, []interface{}{"a", "b"}, body["keys"])
+	assert.Equal(t, true, body["include_docs"])
+
+	// Views share the same encoding, including POSTing keys
+	_, err = db.View(context.Background(), "app", "by_tag", &ViewOptions{
+		Keys:      []interface{}{[]interface{}{"go", 1}},
+		Conflicts: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, []interface{}{[]interface{}{"go", float64(1)}}, body["keys"])
+	assert.Equal(t, true, body["conflicts"])
+}
+
+type recordingLogger struct {
+	warnings []string
+}
+
+func (l *recordingLogger) Errorf(string, ...interface{}) {}
+func (l *recordingLogger) Debugf(string, ...interface{}) {}
+func (l *recordingLogger) Warnf(format string, v ...interface{}) {
+	l.warnings = append(l.warnings, format)
+}
+
+func TestView_TranslatesStale(t *testing.T) {
+	var query url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_ = json.NewEncoder(w).Encode(ServerInfo{CouchDB: "Welcome", Version: "3.3.2"})
+			return
+		}
+		query = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(ViewResult{})
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	db := NewClient(server.URL, &ClientOptions{Logger: logger}).DB("db")
+
+	_, err := db.View(context.Background(), "ddoc", "view", &ViewOptions{Stale: "ok"})
+	require.NoError(t, err)
+	assert.Empty(t, query.Get("stale"))
+	assert.Equal(t, "true", query.Get("stable"))
+	assert.Equal(t, "false", query.Get("update"))
+
+	_, err = db.View(context.Background(), "ddoc", "view", &ViewOptions{Stale: "update_after"})
+	require.NoError(t, err)
+	assert.Equal(t, "lazy", query.Get("update"))
+	assert.Len(t, logger.warnings, 1)
+}
+
+func TestViewStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.Equal(t, "/db/_design/app/_view/by_name", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("include_docs"))
+		_, _ = w.Write([]byte(`{"total_rows":3,"offset":0,"rows":[
+			{"id":"a","key":"alice","value":1,"doc":{"_id":"a","name":"Alice"}},
+			{"id":"b","key":"bob","value":2,"doc":{"_id":"b","name":"Bob"}},
+			{"id":"c","key":"carol","value":3,"doc":{"_id":"c","name":"Carol"}}
+		],"update_seq":"42-abc"}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	rows, err := db.ViewStream(context.Background(), "app", "by_name", &ViewOptions{IncludeDocs: true})
+	require.NoError(t, err)
+	defer rows.Close()
+
+	assert.Equal(t, int64(3), rows.TotalRows)
+
+	var names []string
+	for rows.Next() {
+		var person struct {
+			Name string `json:"name"`
+		}
+		require.NoError(t, rows.ScanDoc(&person))
+		names = append(names, person.Name)
+	}
+
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"Alice", "Bob", "Carol"}, names)
+	assert.Equal(t, Sequence("42-abc"), rows.UpdateSeq)
+}
+
+func TestViewResult_Scan(t *testing.T) {
+	var result ViewResult
+	require.NoError(t, json.Unmarshal([]byte(`{"rows":[
+		{"id":"a","key":["2024",1],"value":{"total":10},"doc":{"_id":"a","_rev":"1-a","name":"Ann","age":30}},
+		{"id":"b","key":["2024",2],"value":{"total":20},"doc":null}
+	]}`), &result))
+
+	var keys [][]interface{}
+	require.NoError(t, result.ScanKeys(&keys))
+	assert.Equal(t, []interface{}{"2024", float64(2)}, keys[1])
+
+	var values []struct {
+		Total int `json:"total"`
+	}
+	require.NoError(t, result.ScanValues(&values))
+	require.Len(t, values, 2)
+	assert.Equal(t, 20, values[1].Total)
+
+	var people []testPerson
+	require.NoError(t, result.ScanDocs(&people))
+	require.Len(t, people, 1)
+	assert.Equal(t, "Ann", people[0].Name)
+	assert.Equal(t, "1-a", people[0].Rev)
+
+	var wrong []int
+	assert.Error(t, result.ScanValues(&wrong))
+}
+
+func TestAllDocsByKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/db/_all_docs", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []interface{}{"a", "missing"}, body["keys"])
+		assert.Equal(t, true, body["include_docs"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total_rows":5,"rows":[
+			{"id":"a","key":"a","value":{"rev":"1-a"},"doc":{"_id":"a","_rev":"1-a"}},
+			{"key":"missing","error":"not_found"}]}`))
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	result, err := db.AllDocsByKeys(context.Background(), []string{"a", "missing"}, &ViewOptions{IncludeDocs: true})
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	assert.Equal(t, "1-a", result.Rows[0].Doc.Rev)
+	assert.Equal(t, "not_found", result.Rows[1].Error)
+
+	result, err = db.AllDocsByKeys(context.Background(), nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.Rows)
+}
+
+func TestViewResult_DecodesAttachmentsAndConflicts(t *testing.T) {
+	input := `{"total_rows":1,"offset":0,"rows":[{"id":"doc-1","key":"doc-1","value":{"rev":"2-b"},
+		"doc":{"_id":"doc-1","_rev":"2-b","name":"test","_conflicts":["2-a"],
+		"_attachments":{"note.txt":{"content_type":"text/plain","revpos":1,"digest":"md5-abc","length":5,"data":"aGVsbG8="}}}}]}`
+
+	var result ViewResult
+	require.NoError(t, json.Unmarshal([]byte(input), &result))
+	require.Len(t, result.Rows, 1)
+
+	doc := result.Rows[0].Doc
+	require.NotNil(t, doc)
+	assert.Equal(t, []string{"2-a"}, doc.Conflicts)
+	assert.Equal(t, map[string]interface{}{"name": "test"}, doc.Data)
+
+	require.Contains(t, doc.Attachments, "note.txt")
+	att := doc.Attachments["note.txt"]
+	assert.Equal(t, "text/plain", att.ContentType)
+	assert.Equal(t, []byte("hello"), att.Data)
+	assert.Equal(t, int64(5), att.Length)
+	assert.Equal(t, 1, att.RevPos)
+
+	encoded, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"_attachments"`)
+	assert.NotContains(t, string(encoded), `"_conflicts"`)
+}
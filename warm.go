@@ -0,0 +1,205 @@
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WarmOptions configures WarmViews
+type WarmOptions struct {
+	// Concurrency limits the views queried at once, defaults to all views
+	// of the design document
+	Concurrency int
+
+	// Lazy only starts the index update instead of waiting for it, like
+	// stale=update_after (update=lazy)
+	Lazy bool
+}
+
+// WarmViews brings the indexes of a design document up to date by querying
+// each of its views with limit=0, so that the next real query does not wait
+// for the indexer. The views are queried concurrently; errors are joined
+// and name the view. Design documents of Mango indexes have no views to
+// query and are skipped.
+func (db *Database) WarmViews(ctx context.Context, designDoc string, opts *WarmOptions) error {
+	if opts == nil {
+		opts = &WarmOptions{}
+	}
+
+	// Read as a plain document, as Mango index definitions do not decode
+	// into a DesignDocument
+	doc, err := db.Get(ctx, "_design/"+strings.TrimPrefix(designDoc, "_design/"))
+	if err != nil {
+		return err
+	}
+	defined, _ := doc.Data["views"].(map[string]interface{})
+	if doc.Data["language"] == "query" || len(defined) == 0 {
+		return nil
+	}
+
+	views := make([]string, 0, len(defined))
+	for name := range defined {
+		views = append(views, name)
+	}
+	sort.Strings(views)
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = len(views)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for _, view := range views {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+		go func(view string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := db.warmView(ctx, designDoc, view, opts.Lazy); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("warm view %s/%s: %w", designDoc, view, err))
+				mu.Unlock()
+			}
+		}(view)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// warmView queries a view for no rows. ViewOptions cannot express limit=0,
+// so the request is built here.
+func (db *Database) warmView(ctx context.Context, designDoc, view string, lazy bool) error {
+	update := "true"
+	if lazy {
+		update = "lazy"
+	}
+
+	resp, err := db.client.resty.R().
+		SetContext(ctx).
+		SetQueryParam("limit", "0").
+		SetQueryParam("update", update).
+		Get(db.designPath(designDoc, "_view", view))
+
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return db.client.parseError(resp)
+	}
+
+	return nil
+}
+
+// ViewWarmerOptions configures a ViewWarmer
+type ViewWarmerOptions struct {
+	WarmOptions
+
+	// DesignDocs are the design documents to warm, defaults to all
+	DesignDocs []string
+
+	// Interval is the time between checks for new writes, defaults to a
+	// minute
+	Interval time.Duration
+}
+
+// ViewWarmer keeps view indexes fresh, e.g. after bulk loads: it warms the
+// design documents whenever the database's update sequence has moved since
+// the last pass, so queries find their indexes built.
+type ViewWarmer struct {
+	db      *Database
+	opts    ViewWarmerOptions
+	lastSeq Sequence
+}
+
+// NewViewWarmer creates a warmer for db's view indexes
+func (db *Database) NewViewWarmer(opts *ViewWarmerOptions) *ViewWarmer {
+	w := &ViewWarmer{db: db}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = time.Minute
+	}
+
+	return w
+}
+
+// Warm warms the design documents unless nothing was written since the
+// last successful pass, and reports whether it did
+func (w *ViewWarmer) Warm(ctx context.Context) (bool, error) {
+	info, err := w.db.Info(ctx)
+	if err != nil {
+		return false, err
+	}
+	if w.lastSeq != "" && info.UpdateSeq == w.lastSeq {
+		return false, nil
+	}
+
+	designDocs := w.opts.DesignDocs
+	if len(designDocs) == 0 {
+		result, err := w.db.ListDesignDocs(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, row := range result.Rows {
+			designDocs = append(designDocs, strings.TrimPrefix(row.ID, "_design/"))
+		}
+	}
+
+	var errs []error
+	for _, name := range designDocs {
+		if err := w.db.WarmViews(ctx, name, &w.opts.WarmOptions); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return true, errors.Join(errs...)
+	}
+
+	w.lastSeq = info.UpdateSeq
+	return true, nil
+}
+
+// Run warms the indexes every Interval until ctx is cancelled. A failed
+// pass is logged and retried at the next interval.
+func (w *ViewWarmer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if warmed, err := w.Warm(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			w.db.client.logger.Warnf("view warmer on %s: %v", w.db.name, err)
+		} else if warmed {
+			w.db.client.logger.Debugf("view warmer updated the indexes of %s", w.db.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
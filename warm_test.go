@@ -0,0 +1,103 @@
+package couchdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmViews(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	updateSeq := "1-a"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/db":
+			fmt.Fprintf(w, `{"db_name":"db","update_seq":%q}`, updateSeq)
+		case r.URL.Path == "/db/_all_docs":
+			fmt.Fprint(w, `{"rows":[{"id":"_design/app","key":"_design/app"},{"id":"_design/mango","key":"_design/mango"}]}`)
+		case r.URL.Path == "/db/_design/app":
+			fmt.Fprint(w, `{"_id":"_design/app","views":{"by_date":{"map":"f"},"by_user":{"map":"f"},"broken":{"map":"f"}}}`)
+		case r.URL.Path == "/db/_design/mango":
+			fmt.Fprint(w, `{"_id":"_design/mango","language":"query","views":{"idx":{"map":{"fields":{"a":"asc"}}}}}`)
+		case strings.HasPrefix(r.URL.Path, "/db/_design/app/_view/"):
+			view := strings.TrimPrefix(r.URL.Path, "/db/_design/app/_view/")
+			mu.Lock()
+			queries = append(queries, view+"?"+r.URL.RawQuery)
+			mu.Unlock()
+			if view == "broken" {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"error":"os_process_error","reason":"timeout"}`)
+				return
+			}
+			fmt.Fprint(w, `{"total_rows":10,"offset":0,"rows":[]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := NewClient(server.URL, nil).DB("db")
+	ctx := context.Background()
+
+	sortedQueries := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Strings(queries)
+		out := queries
+		queries = nil
+		return out
+	}
+
+	err := db.WarmViews(ctx, "app", &WarmOptions{Concurrency: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "warm view app/broken")
+	var couchErr *Error
+	require.ErrorAs(t, err, &couchErr)
+	assert.Equal(t, "os_process_error", couchErr.Type)
+	assert.Equal(t, []string{"broken?limit=0&update=true", "by_date?limit=0&update=true", "by_user?limit=0&update=true"}, sortedQueries())
+
+	require.Error(t, db.WarmViews(ctx, "app", &WarmOptions{Lazy: true}))
+	assert.Equal(t, []string{"broken?limit=0&update=lazy", "by_date?limit=0&update=lazy", "by_user?limit=0&update=lazy"}, sortedQueries())
+
+	require.NoError(t, db.WarmViews(ctx, "mango", nil))
+	assert.Empty(t, sortedQueries())
+
+	// The warmer skips passes without new writes, and retries failed ones
+	warmer := db.NewViewWarmer(nil)
+	warmed, err := warmer.Warm(ctx)
+	assert.True(t, warmed)
+	assert.Error(t, err)
+	assert.Len(t, sortedQueries(), 3)
+
+	warmed, err = warmer.Warm(ctx)
+	assert.True(t, warmed)
+	assert.Error(t, err)
+	assert.Len(t, sortedQueries(), 3)
+
+	warmer = db.NewViewWarmer(&ViewWarmerOptions{DesignDocs: []string{"mango"}})
+	warmed, err = warmer.Warm(ctx)
+	require.NoError(t, err)
+	assert.True(t, warmed)
+
+	warmed, err = warmer.Warm(ctx)
+	require.NoError(t, err)
+	assert.False(t, warmed)
+
+	updateSeq = "2-b"
+	warmed, err = warmer.Warm(ctx)
+	require.NoError(t, err)
+	assert.True(t, warmed)
+}